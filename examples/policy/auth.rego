@@ -0,0 +1,22 @@
+package auth
+
+import rego.v1
+
+# Every request to /msg/* is denied unless one of the following rules allows it.
+default allow := false
+
+# Allow GitHub webhook validated by secret key
+allow if {
+    input.auth.github.webhook.valid
+}
+
+# Allow Pub/Sub push subscription authenticated by Google ID token of the service account
+allow if {
+    input.auth.google.email == "pubsub-invoker@my-project.iam.gserviceaccount.com"
+    input.auth.google.email_verified
+}
+
+# Allow GitHub Actions workflow of the organization
+allow if {
+    input.auth.github.actions.repository_owner == "my-org"
+}
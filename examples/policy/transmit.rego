@@ -15,7 +15,8 @@ slack contains {
     "title": "Hello",
     "emoji": ":wave:",
     "channel": "#github-notify",
-    "body": json.marshal(input.data),
+    "body": sprintf("Received %s message from %s", [input.schema, input.source]),
+    "files": [{"filename": "data.json", "content": input.data, "title": "Full payload"}],
 } if {
     is_object(input.data)
 }
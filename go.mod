@@ -4,6 +4,7 @@ go 1.23.4
 
 require (
 	github.com/fatih/color v1.16.0
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/go-chi/chi/v5 v5.2.0
 	github.com/go-test/deep v1.0.4
 	github.com/google/go-github/v68 v68.0.0
@@ -14,6 +15,7 @@ require (
 	github.com/m-mizutani/gt v0.0.10
 	github.com/m-mizutani/masq v0.1.10
 	github.com/m-mizutani/opac v0.2.2
+	github.com/open-policy-agent/opa v1.0.0
 	github.com/slack-go/slack v0.15.0
 	github.com/urfave/cli/v3 v3.0.0-beta1
 )
@@ -42,7 +44,6 @@ require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
-	github.com/open-policy-agent/opa v1.0.0 // indirect
 	github.com/prometheus/client_golang v1.20.5 // indirect
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.55.0 // indirect
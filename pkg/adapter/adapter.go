@@ -1,10 +1,27 @@
 package adapter
 
-import "github.com/m-mizutani/xroute/pkg/domain/interfaces"
+import (
+	"net/http"
+	"sync"
+
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+)
 
 type Adapters struct {
-	slack  interfaces.Slack
-	policy interfaces.Policy
+	slack     interfaces.Slack
+	threads   interfaces.SlackThreadStore
+	teams     interfaces.Teams
+	discord   interfaces.Discord
+	email     interfaces.Email
+	pagerDuty interfaces.PagerDuty
+	github    interfaces.GitHub
+	policy    interfaces.Policy
+	queue     interfaces.Queue
+
+	httpClient interfaces.HTTPClient
+	deadLetter interfaces.DeadLetterStore
+
+	policyMutex sync.RWMutex
 }
 
 func New(options ...Option) *Adapters {
@@ -18,10 +35,68 @@ func New(options ...Option) *Adapters {
 func (x *Adapters) Slack() interfaces.Slack {
 	return x.slack
 }
+
+// SlackThreadStore returns store of Slack threads. It returns nil if not configured, and then thread key is ignored.
+func (x *Adapters) SlackThreadStore() interfaces.SlackThreadStore {
+	return x.threads
+}
+
+// Teams returns Microsoft Teams client. It returns nil if no webhook is configured.
+func (x *Adapters) Teams() interfaces.Teams {
+	return x.teams
+}
+
+// Discord returns Discord client. It returns nil if no webhook is configured.
+func (x *Adapters) Discord() interfaces.Discord {
+	return x.discord
+}
+
+// Email returns mail client. It returns nil if SMTP is not configured.
+func (x *Adapters) Email() interfaces.Email {
+	return x.email
+}
+
+// PagerDuty returns PagerDuty client. It returns nil if no routing key is configured.
+func (x *Adapters) PagerDuty() interfaces.PagerDuty {
+	return x.pagerDuty
+}
+
+// GitHub returns GitHub client for issue destination. It returns nil if GitHub credential is not configured.
+func (x *Adapters) GitHub() interfaces.GitHub {
+	return x.github
+}
+
 func (x *Adapters) Policy() interfaces.Policy {
+	x.policyMutex.RLock()
+	defer x.policyMutex.RUnlock()
 	return x.policy
 }
 
+// SetPolicy replaces the policy. It's safe to call while other goroutines are querying the policy.
+func (x *Adapters) SetPolicy(policy interfaces.Policy) {
+	x.policyMutex.Lock()
+	defer x.policyMutex.Unlock()
+	x.policy = policy
+}
+
+// Queue returns delivery queue. It returns nil if the queue is not configured, and then messages are transmitted synchronously.
+func (x *Adapters) Queue() interfaces.Queue {
+	return x.queue
+}
+
+// DeadLetter returns store of given up deliveries. It returns nil if the store is not configured.
+func (x *Adapters) DeadLetter() interfaces.DeadLetterStore {
+	return x.deadLetter
+}
+
+// HTTPClient returns HTTP client for webhook destination. It returns http.DefaultClient if not configured.
+func (x *Adapters) HTTPClient() interfaces.HTTPClient {
+	if x.httpClient == nil {
+		return http.DefaultClient
+	}
+	return x.httpClient
+}
+
 type Option func(*Adapters)
 
 func WithSlack(slack interfaces.Slack) Option {
@@ -30,8 +105,62 @@ func WithSlack(slack interfaces.Slack) Option {
 	}
 }
 
+func WithSlackThreadStore(store interfaces.SlackThreadStore) Option {
+	return func(a *Adapters) {
+		a.threads = store
+	}
+}
+
+func WithTeams(teams interfaces.Teams) Option {
+	return func(a *Adapters) {
+		a.teams = teams
+	}
+}
+
+func WithDiscord(discord interfaces.Discord) Option {
+	return func(a *Adapters) {
+		a.discord = discord
+	}
+}
+
+func WithEmail(email interfaces.Email) Option {
+	return func(a *Adapters) {
+		a.email = email
+	}
+}
+
+func WithPagerDuty(pagerDuty interfaces.PagerDuty) Option {
+	return func(a *Adapters) {
+		a.pagerDuty = pagerDuty
+	}
+}
+
+func WithGitHub(github interfaces.GitHub) Option {
+	return func(a *Adapters) {
+		a.github = github
+	}
+}
+
 func WithPolicy(policy interfaces.Policy) Option {
 	return func(a *Adapters) {
 		a.policy = policy
 	}
 }
+
+func WithQueue(queue interfaces.Queue) Option {
+	return func(a *Adapters) {
+		a.queue = queue
+	}
+}
+
+func WithDeadLetter(store interfaces.DeadLetterStore) Option {
+	return func(a *Adapters) {
+		a.deadLetter = store
+	}
+}
+
+func WithHTTPClient(client interfaces.HTTPClient) Option {
+	return func(a *Adapters) {
+		a.httpClient = client
+	}
+}
@@ -0,0 +1,262 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	opabundle "github.com/open-policy-agent/opa/bundle"
+)
+
+const (
+	// maxBundleSize is maximum size of downloaded bundle tarball.
+	maxBundleSize = 128 * 1024 * 1024
+
+	// httpTimeout bounds each request to bundle server, OCI registry and token server so that a stalled server does not block policy reload forever.
+	httpTimeout = 60 * time.Second
+
+	cacheBundleFile  = "bundle.tar.gz"
+	cacheVersionFile = "bundle.version"
+)
+
+// Client downloads OPA bundle from HTTP server or OCI registry and builds policy client from it. The last good bundle is kept in memory, and also on disk if cache directory is configured.
+type Client struct {
+	url          *url.URL
+	httpClient   interfaces.HTTPClient
+	token        string
+	verification *opabundle.VerificationConfig
+	cacheDir     string
+
+	mutex   sync.Mutex
+	version string
+	policy  *opac.Client
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces HTTP client. Default is http.Client with httpTimeout.
+func WithHTTPClient(client interfaces.HTTPClient) Option {
+	return func(x *Client) {
+		x.httpClient = client
+	}
+}
+
+// WithToken sets Bearer token for the bundle server or OCI registry.
+func WithToken(token string) Option {
+	return func(x *Client) {
+		x.token = token
+	}
+}
+
+// WithPublicKey requires the bundle to be signed by the key. key is PEM encoded public key (or secret for HS256), and alg is signing algorithm such as RS256 and ES256. keyID must match "keyid" of the signature.
+func WithPublicKey(keyID, alg, key string) Option {
+	return func(x *Client) {
+		x.verification = opabundle.NewVerificationConfig(map[string]*opabundle.KeyConfig{
+			keyID: {Key: key, Algorithm: alg},
+		}, keyID, "", nil)
+	}
+}
+
+// WithCacheDir enables to save the last good bundle into the directory. The saved bundle is used if the bundle can not be downloaded at startup.
+func WithCacheDir(dir string) Option {
+	return func(x *Client) {
+		x.cacheDir = dir
+	}
+}
+
+// New creates bundle client. rawURL must be http(s)://... for HTTP bundle server or oci://<registry>/<repository>[:<tag>|@<digest>] for OCI registry.
+func New(rawURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid bundle URL", goerr.V("url", rawURL))
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	case "oci":
+		if _, err := parseOCIReference(u); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, goerr.New("unsupported scheme of bundle URL", goerr.V("url", rawURL))
+	}
+
+	client := &Client{
+		url:        u,
+		httpClient: &http.Client{Timeout: httpTimeout},
+	}
+	for _, opt := range options {
+		opt(client)
+	}
+
+	return client, nil
+}
+
+// Fetch downloads the bundle if it has been changed since the last fetch. The bundle is verified and compiled before replacing the current one, so a broken bundle is never used. It returns true if the bundle is replaced.
+func (x *Client) Fetch(ctx context.Context) (bool, error) {
+	x.mutex.Lock()
+	current := x.version
+	x.mutex.Unlock()
+
+	var raw []byte
+	var version string
+	var err error
+	if x.url.Scheme == "oci" {
+		raw, version, err = x.fetchOCI(ctx, current)
+	} else {
+		raw, version, err = x.fetchHTTP(ctx, current)
+	}
+	if err != nil {
+		return false, err
+	}
+	if raw == nil || (current != "" && version == current) {
+		return false, nil
+	}
+
+	policy, err := x.build(raw)
+	if err != nil {
+		return false, goerr.Wrap(err, "downloaded bundle is not acceptable", goerr.V("version", version))
+	}
+
+	x.mutex.Lock()
+	x.version, x.policy = version, policy
+	x.mutex.Unlock()
+
+	if err := x.saveCache(raw, version); err != nil {
+		logging.Extract(ctx).Warn("Failed to save bundle cache", "error", err, "dir", x.cacheDir)
+	}
+
+	logging.Extract(ctx).Info("Downloaded policy bundle", "url", x.url.String(), "version", version)
+	return true, nil
+}
+
+// Load fetches the bundle at startup and returns policy client built from it. If the bundle can not be fetched, the cached bundle on disk is used.
+func (x *Client) Load(ctx context.Context) (interfaces.Policy, error) {
+	if _, err := x.Fetch(ctx); err != nil {
+		if cacheErr := x.loadCache(); cacheErr != nil {
+			logging.Extract(ctx).Warn("Failed to load bundle cache", "error", cacheErr, "dir", x.cacheDir)
+			return nil, err
+		}
+		logging.Extract(ctx).Warn("Failed to fetch policy bundle, use cached bundle", "error", err, "version", x.Version())
+	}
+
+	return x.Policy(ctx)
+}
+
+// Policy returns policy client built from the last good bundle without fetching. It's used as policy loader for reloading after Fetch replaces the bundle.
+func (x *Client) Policy(ctx context.Context) (interfaces.Policy, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	if x.policy == nil {
+		return nil, goerr.New("policy bundle has not been loaded")
+	}
+	return x.policy, nil
+}
+
+// Version returns ETag (HTTP) or manifest digest (OCI) of the current bundle.
+func (x *Client) Version() string {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	return x.version
+}
+
+// build reads the bundle tarball with signature verification and compiles policies in it.
+func (x *Client) build(raw []byte) (*opac.Client, error) {
+	reader := opabundle.NewCustomReader(opabundle.NewTarballLoaderWithBaseURL(bytes.NewReader(raw), ""))
+	if x.verification != nil {
+		reader = reader.WithBundleVerificationConfig(x.verification)
+	} else {
+		reader = reader.WithSkipBundleVerification(true)
+	}
+
+	b, err := reader.Read()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read bundle")
+	}
+
+	// opac can not load data documents, so a policy depending on them would be evaluated wrongly.
+	if len(b.Data) > 0 {
+		return nil, goerr.New("data documents in bundle are not supported")
+	}
+
+	policies := make(map[string]string, len(b.Modules))
+	for _, m := range b.Modules {
+		policies[m.Path] = string(m.Raw)
+	}
+	if len(policies) == 0 {
+		return nil, goerr.New("no policy in bundle")
+	}
+
+	client, err := opac.New(opac.Data(policies))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to compile policies in bundle")
+	}
+	return client, nil
+}
+
+func (x *Client) saveCache(raw []byte, version string) error {
+	if x.cacheDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(x.cacheDir, 0700); err != nil {
+		return goerr.Wrap(err, "failed to create cache directory")
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{cacheBundleFile, raw},
+		{cacheVersionFile, []byte(version)},
+	}
+	for _, f := range files {
+		path := filepath.Join(x.cacheDir, f.name)
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, f.data, 0600); err != nil {
+			return goerr.Wrap(err, "failed to write cache file", goerr.V("path", tmp))
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return goerr.Wrap(err, "failed to rename cache file", goerr.V("path", path))
+		}
+	}
+
+	return nil
+}
+
+func (x *Client) loadCache() error {
+	if x.cacheDir == "" {
+		return goerr.New("bundle cache directory is not configured")
+	}
+
+	raw, err := os.ReadFile(filepath.Join(x.cacheDir, cacheBundleFile))
+	if err != nil {
+		return goerr.Wrap(err, "failed to read cached bundle")
+	}
+	version, err := os.ReadFile(filepath.Join(x.cacheDir, cacheVersionFile))
+	if err != nil {
+		return goerr.Wrap(err, "failed to read cached bundle version")
+	}
+
+	// The cached bundle is verified again because the file might be modified.
+	policy, err := x.build(raw)
+	if err != nil {
+		return goerr.Wrap(err, "cached bundle is not acceptable")
+	}
+
+	x.mutex.Lock()
+	x.version, x.policy = string(version), policy
+	x.mutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package bundle_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/bundle"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	opabundle "github.com/open-policy-agent/opa/bundle"
+)
+
+func buildBundle(t *testing.T, policy string, sign *rsa.PrivateKey) []byte {
+	b := opabundle.Bundle{
+		Data: map[string]any{},
+		Modules: []opabundle.ModuleFile{
+			{URL: "/auth.rego", Path: "/auth.rego", Raw: []byte(policy)},
+		},
+	}
+
+	if sign != nil {
+		der, err := x509.MarshalPKCS8PrivateKey(sign)
+		gt.NoError(t, err)
+		key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		gt.NoError(t, b.GenerateSignature(opabundle.NewSigningConfig(string(key), "RS256", ""), "my-key", false))
+	}
+
+	var buf bytes.Buffer
+	gt.NoError(t, opabundle.NewWriter(&buf).Write(b))
+	return buf.Bytes()
+}
+
+func publicKeyPEM(t *testing.T, key *rsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	gt.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+const (
+	allowPolicy = "package auth\nimport rego.v1\ndefault allow := true\n"
+	denyPolicy  = "package auth\nimport rego.v1\ndefault allow := false\n"
+)
+
+func queryAllow(t *testing.T, policy interfaces.Policy) bool {
+	var out struct {
+		Allow bool `json:"allow"`
+	}
+	gt.NoError(t, policy.Query(context.Background(), "data.auth", map[string]any{}, &out))
+	return out.Allow
+}
+
+type bundleServer struct {
+	mutex    sync.Mutex
+	raw      []byte
+	etag     string
+	fail     bool
+	requests int
+	auth     string
+}
+
+func (x *bundleServer) set(raw []byte, etag string) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	x.raw, x.etag = raw, etag
+}
+
+func (x *bundleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	x.requests++
+	x.auth = r.Header.Get("Authorization")
+
+	if x.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if x.etag != "" && r.Header.Get("If-None-Match") == x.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if x.etag != "" {
+		w.Header().Set("ETag", x.etag)
+	}
+	_, _ = w.Write(x.raw)
+}
+
+func TestHTTPBundle(t *testing.T) {
+	ctx := context.Background()
+	srv := &bundleServer{}
+	srv.set(buildBundle(t, allowPolicy, nil), `"v1"`)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	client := gt.R1(bundle.New(ts.URL+"/bundle.tar.gz", bundle.WithToken("my-token"))).NoError(t)
+	policy := gt.R1(client.Load(ctx)).NoError(t)
+	gt.True(t, queryAllow(t, policy))
+	gt.Equal(t, client.Version(), `"v1"`)
+	gt.Equal(t, srv.auth, "Bearer my-token")
+
+	t.Run("not modified", func(t *testing.T) {
+		changed := gt.R1(client.Fetch(ctx)).NoError(t)
+		gt.False(t, changed)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		srv.set(buildBundle(t, denyPolicy, nil), `"v2"`)
+		changed := gt.R1(client.Fetch(ctx)).NoError(t)
+		gt.True(t, changed)
+
+		// Policy does not download the bundle again
+		requests := srv.requests
+		policy := gt.R1(client.Policy(ctx)).NoError(t)
+		gt.False(t, queryAllow(t, policy))
+		gt.Equal(t, client.Version(), `"v2"`)
+		gt.Equal(t, srv.requests, requests)
+	})
+
+	t.Run("broken bundle is not used", func(t *testing.T) {
+		srv.set(buildBundle(t, "package auth\nallow := {", nil), `"v3"`)
+		gt.R1(client.Fetch(ctx)).Error(t)
+		gt.Equal(t, client.Version(), `"v2"`)
+
+		policy := gt.R1(client.Policy(ctx)).NoError(t)
+		gt.False(t, queryAllow(t, policy))
+	})
+}
+
+func TestBundlePolicyBeforeLoad(t *testing.T) {
+	client := gt.R1(bundle.New("https://example.com/bundle.tar.gz")).NoError(t)
+	gt.R1(client.Policy(context.Background())).Error(t)
+}
+
+func TestHTTPBundleWithoutETag(t *testing.T) {
+	ctx := context.Background()
+	srv := &bundleServer{}
+	srv.set(buildBundle(t, allowPolicy, nil), "")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	client := gt.R1(bundle.New(ts.URL)).NoError(t)
+	gt.True(t, gt.R1(client.Fetch(ctx)).NoError(t))
+
+	// Same content is not regarded as change
+	gt.False(t, gt.R1(client.Fetch(ctx)).NoError(t))
+	gt.Equal(t, srv.requests, 2)
+}
+
+func TestBundleSignature(t *testing.T) {
+	ctx := context.Background()
+	key := gt.R1(rsa.GenerateKey(rand.Reader, 2048)).NoError(t)
+	otherKey := gt.R1(rsa.GenerateKey(rand.Reader, 2048)).NoError(t)
+
+	testCases := map[string]struct {
+		raw   []byte
+		valid bool
+	}{
+		"signed":          {raw: buildBundle(t, allowPolicy, key), valid: true},
+		"signed by other": {raw: buildBundle(t, allowPolicy, otherKey), valid: false},
+		"not signed":      {raw: buildBundle(t, allowPolicy, nil), valid: false},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			srv := &bundleServer{}
+			srv.set(tc.raw, `"v1"`)
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+
+			client := gt.R1(bundle.New(ts.URL,
+				bundle.WithPublicKey("my-key", "RS256", publicKeyPEM(t, key)),
+			)).NoError(t)
+
+			_, err := client.Load(ctx)
+			gt.Equal(t, err == nil, tc.valid)
+		})
+	}
+}
+
+func TestBundleCache(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	srv := &bundleServer{}
+	srv.set(buildBundle(t, allowPolicy, nil), `"v1"`)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	first := gt.R1(bundle.New(ts.URL, bundle.WithCacheDir(cacheDir))).NoError(t)
+	gt.R1(first.Load(ctx)).NoError(t)
+
+	// Server goes down, then a new client falls back to the cached bundle
+	srv.fail = true
+	second := gt.R1(bundle.New(ts.URL, bundle.WithCacheDir(cacheDir))).NoError(t)
+	policy := gt.R1(second.Load(ctx)).NoError(t)
+	gt.True(t, queryAllow(t, policy))
+	gt.Equal(t, second.Version(), `"v1"`)
+
+	// Without cache, it fails
+	third := gt.R1(bundle.New(ts.URL, bundle.WithCacheDir(t.TempDir()))).NoError(t)
+	gt.R1(third.Load(ctx)).Error(t)
+}
+
+func sha256Digest(raw []byte) string {
+	h := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+func TestOCIBundle(t *testing.T) {
+	ctx := context.Background()
+	layer := buildBundle(t, allowPolicy, nil)
+	manifest := gt.R1(json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]any{
+			{
+				"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip",
+				"digest":    sha256Digest(layer),
+				"size":      len(layer),
+			},
+		},
+	})).NoError(t)
+
+	var manifestCalls, blobCalls int
+	mux := http.NewServeMux()
+	var ts *httptest.Server
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.URL.Query().Get("scope"), "repository:org/policy:pull")
+		_, _ = w.Write([]byte(`{"token":"registry-token"}`))
+	})
+	mux.HandleFunc("/v2/org/policy/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:org/policy:pull"`, ts.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/v2/org/policy/manifests/v1":
+			manifestCalls++
+			_, _ = w.Write(manifest)
+		case strings.HasPrefix(r.URL.Path, "/v2/org/policy/blobs/"):
+			blobCalls++
+			gt.Equal(t, strings.TrimPrefix(r.URL.Path, "/v2/org/policy/blobs/"), sha256Digest(layer))
+			_, _ = w.Write(layer)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ts = httptest.NewTLSServer(mux)
+	defer ts.Close()
+
+	ref := "oci://" + strings.TrimPrefix(ts.URL, "https://") + "/org/policy:v1"
+	client := gt.R1(bundle.New(ref, bundle.WithHTTPClient(ts.Client()))).NoError(t)
+
+	policy := gt.R1(client.Load(ctx)).NoError(t)
+	gt.True(t, queryAllow(t, policy))
+	gt.Equal(t, client.Version(), sha256Digest(manifest))
+
+	// Blob is not downloaded again if manifest is not changed
+	gt.False(t, gt.R1(client.Fetch(ctx)).NoError(t))
+	gt.Equal(t, manifestCalls, 2)
+	gt.Equal(t, blobCalls, 1)
+}
+
+func TestOCIBundleTokenRealm(t *testing.T) {
+	layer := buildBundle(t, allowPolicy, nil)
+	manifest := gt.R1(json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]any{
+			{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": sha256Digest(layer), "size": len(layer)},
+		},
+	})).NoError(t)
+
+	newRegistry := func(t *testing.T, realm func(registry string) string, auth *string) *httptest.Server {
+		var ts *httptest.Server
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			*auth = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"token":"registry-token"}`))
+		})
+		mux.HandleFunc("/v2/org/policy/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer registry-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token"`, realm(ts.URL)))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/v2/org/policy/blobs/") {
+				_, _ = w.Write(layer)
+				return
+			}
+			_, _ = w.Write(manifest)
+		})
+		ts = httptest.NewTLSServer(mux)
+		t.Cleanup(ts.Close)
+		return ts
+	}
+
+	t.Run("realm on registry host receives token", func(t *testing.T) {
+		var auth string
+		ts := newRegistry(t, func(registry string) string { return registry }, &auth)
+
+		ref := "oci://" + strings.TrimPrefix(ts.URL, "https://") + "/org/policy:v1"
+		client := gt.R1(bundle.New(ref, bundle.WithHTTPClient(ts.Client()), bundle.WithToken("my-token"))).NoError(t)
+		gt.R1(client.Load(context.Background())).NoError(t)
+		gt.Equal(t, auth, "Bearer my-token")
+	})
+
+	t.Run("realm on other host does not receive token", func(t *testing.T) {
+		realmAuth, registryAuth := "not called", "not called"
+		other := newRegistry(t, func(registry string) string { return registry }, &realmAuth)
+		ts := newRegistry(t, func(string) string { return other.URL }, &registryAuth)
+
+		ref := "oci://" + strings.TrimPrefix(ts.URL, "https://") + "/org/policy:v1"
+		client := gt.R1(bundle.New(ref, bundle.WithHTTPClient(ts.Client()), bundle.WithToken("my-token"))).NoError(t)
+		gt.R1(client.Load(context.Background())).NoError(t)
+		gt.Equal(t, realmAuth, "")
+	})
+}
+
+func TestInvalidBundleURL(t *testing.T) {
+	for _, u := range []string{
+		"ftp://example.com/bundle.tar.gz",
+		"oci://ghcr.io",
+		"oci:///org/policy",
+	} {
+		t.Run(u, func(t *testing.T) {
+			gt.R1(bundle.New(u)).Error(t)
+		})
+	}
+}
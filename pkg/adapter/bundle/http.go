@@ -0,0 +1,68 @@
+package bundle
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+// fetchHTTP downloads bundle tarball with If-None-Match header. It returns nil raw if the server responds 304 Not Modified.
+func (x *Client) fetchHTTP(ctx context.Context, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, x.url.String(), nil)
+	if err != nil {
+		return nil, "", goerr.Wrap(err, "failed to create bundle request", goerr.V("url", x.url.String()))
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if x.token != "" {
+		req.Header.Set("Authorization", "Bearer "+x.token)
+	}
+
+	resp, err := x.httpClient.Do(req)
+	if err != nil {
+		return nil, "", goerr.Wrap(err, "failed to fetch bundle", goerr.V("url", x.url.String()))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// go through
+	case http.StatusNotModified:
+		return nil, etag, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, "", goerr.New("unexpected status code of bundle server",
+			goerr.V("url", x.url.String()),
+			goerr.V("status_code", resp.StatusCode),
+			goerr.V("body", string(body)),
+		)
+	}
+
+	raw, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, "", goerr.Wrap(err, "failed to read bundle", goerr.V("url", x.url.String()))
+	}
+
+	// Without ETag, digest of the content is used to detect change.
+	version := resp.Header.Get("ETag")
+	if version == "" {
+		version = digestOf(raw)
+	}
+
+	return raw, version, nil
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxBundleSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxBundleSize {
+		return nil, goerr.New("bundle is too large", goerr.V("limit", maxBundleSize))
+	}
+	return raw, nil
+}
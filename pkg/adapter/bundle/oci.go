@@ -0,0 +1,240 @@
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+	// opa push (and oras) stores bundle tarball as a layer of these media types.
+	ociLayerMediaType    = "application/vnd.oci.image.layer.v1.tar+gzip"
+	opaBundleMediaType   = "application/vnd.openpolicyagent.layer.v1.tar+gzip"
+	ociManifestSizeLimit = 4 * 1024 * 1024
+)
+
+type ociReference struct {
+	registry   string
+	repository string
+	reference  string // tag or digest
+}
+
+// parseOCIReference parses oci://<registry>/<repository>[:<tag>|@<digest>]. Tag is "latest" if omitted.
+func parseOCIReference(u *url.URL) (*ociReference, error) {
+	repo := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || repo == "" {
+		return nil, goerr.New("invalid OCI reference, oci://<registry>/<repository>[:<tag>] is required", goerr.V("url", u.String()))
+	}
+
+	ref := &ociReference{registry: u.Host, repository: repo, reference: "latest"}
+	if i := strings.Index(repo, "@"); i >= 0 {
+		ref.repository, ref.reference = repo[:i], repo[i+1:]
+	} else if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		ref.repository, ref.reference = repo[:i], repo[i+1:]
+	}
+
+	if ref.repository == "" || ref.reference == "" {
+		return nil, goerr.New("invalid OCI reference", goerr.V("url", u.String()))
+	}
+	return ref, nil
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// fetchOCI pulls bundle from OCI registry. Digest of the manifest is used as version, and the bundle layer is not downloaded if the digest equals to current. It returns nil raw in that case.
+func (x *Client) fetchOCI(ctx context.Context, current string) ([]byte, string, error) {
+	ref, err := parseOCIReference(x.url)
+	if err != nil {
+		return nil, "", err
+	}
+	base := "https://" + ref.registry + "/v2/" + ref.repository
+
+	rawManifest, err := x.ociGet(ctx, base+"/manifests/"+ref.reference, ociManifestMediaType, ociManifestSizeLimit)
+	if err != nil {
+		return nil, "", goerr.Wrap(err, "failed to fetch OCI manifest")
+	}
+	version := digestOf(rawManifest)
+	if version == current {
+		return nil, version, nil
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+		return nil, "", goerr.Wrap(err, "failed to parse OCI manifest", goerr.V("manifest", string(rawManifest)))
+	}
+
+	var layer *ociDescriptor
+	for i, l := range manifest.Layers {
+		if l.MediaType == ociLayerMediaType || l.MediaType == opaBundleMediaType {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, "", goerr.New("no bundle layer in OCI manifest", goerr.V("manifest", string(rawManifest)))
+	}
+
+	raw, err := x.ociGet(ctx, base+"/blobs/"+layer.Digest, "", maxBundleSize)
+	if err != nil {
+		return nil, "", goerr.Wrap(err, "failed to fetch OCI blob", goerr.V("digest", layer.Digest))
+	}
+	if digest := digestOf(raw); digest != layer.Digest {
+		return nil, "", goerr.New("digest mismatch of OCI blob", goerr.V("expected", layer.Digest), goerr.V("actual", digest))
+	}
+
+	return raw, version, nil
+}
+
+// ociGet sends GET request to registry. If the registry requires token, it gets token by the challenge of WWW-Authenticate header and retries.
+func (x *Client) ociGet(ctx context.Context, target, accept string, limit int64) ([]byte, error) {
+	token := x.token
+	for retry := 0; ; retry++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create request", goerr.V("url", target))
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := x.httpClient.Do(req)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to send request", goerr.V("url", target))
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && retry == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			safe.Close(ctx, resp.Body)
+
+			token, err = x.ociToken(ctx, challenge, req.URL.Host)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		defer safe.Close(ctx, resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return nil, goerr.New("unexpected status code of OCI registry",
+				goerr.V("url", target),
+				goerr.V("status_code", resp.StatusCode),
+				goerr.V("body", string(body)),
+			)
+		}
+
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to read response", goerr.V("url", target))
+		}
+		if int64(len(raw)) > limit {
+			return nil, goerr.New("response of OCI registry is too large", goerr.V("url", target), goerr.V("limit", limit))
+		}
+		return raw, nil
+	}
+}
+
+// ociToken gets Bearer token from the realm given by WWW-Authenticate challenge. See https://distribution.github.io/distribution/spec/auth/token/
+// The configured token is sent to the realm only if it is on the registry host, because the challenge is controlled by the registry response and may point anywhere. Otherwise an anonymous token is requested.
+func (x *Client) ociToken(ctx context.Context, challenge, registry string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", goerr.New("unsupported auth challenge of OCI registry", goerr.V("challenge", challenge))
+	}
+
+	attrs := parseChallengeParams(params)
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", goerr.New("invalid realm of OCI registry", goerr.V("challenge", challenge))
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if v, ok := attrs[key]; ok {
+			query.Set(key, v)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to create token request", goerr.V("url", realm.String()))
+	}
+	if x.token != "" && strings.EqualFold(realm.Host, registry) {
+		req.Header.Set("Authorization", "Bearer "+x.token)
+	}
+
+	resp, err := x.httpClient.Do(req)
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to get token of OCI registry", goerr.V("url", realm.String()))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", goerr.New("unexpected status code of token server", goerr.V("url", realm.String()), goerr.V("status_code", resp.StatusCode))
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&tokenResp); err != nil {
+		return "", goerr.Wrap(err, "failed to parse token response")
+	}
+
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
+
+// parseChallengeParams parses `key="value",key="value"` of WWW-Authenticate header.
+func parseChallengeParams(s string) map[string]string {
+	attrs := map[string]string{}
+	for s != "" {
+		key, rest, ok := strings.Cut(strings.TrimLeft(s, " ,"), "=")
+		if !ok {
+			break
+		}
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value, s = rest[1:end+1], rest[end+2:]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+		}
+		attrs[strings.TrimSpace(key)] = value
+	}
+	return attrs
+}
+
+func digestOf(raw []byte) string {
+	h := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
@@ -0,0 +1,54 @@
+package deadletter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/deadletter"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+func testStore(t *testing.T, store interfaces.DeadLetterStore) {
+	ctx := context.Background()
+	now := time.Now()
+
+	gt.NoError(t, store.Put(ctx, &model.DeadLetter{
+		ID:       "second",
+		Delivery: model.Delivery{ID: "second", Slack: &model.SlackMessage{Channel: "#b"}},
+		Error:    "channel_not_found",
+		FailedAt: now.Add(time.Second),
+	}))
+	gt.NoError(t, store.Put(ctx, &model.DeadLetter{
+		ID:       "first",
+		Delivery: model.Delivery{ID: "first", Slack: &model.SlackMessage{Channel: "#a"}},
+		Error:    "rate limited",
+		FailedAt: now,
+	}))
+
+	entry := gt.R1(store.Get(ctx, "second")).NoError(t)
+	gt.V(t, entry).NotNil()
+	gt.Equal(t, entry.Error, "channel_not_found")
+	gt.Equal(t, entry.Delivery.Slack.Channel, "#b")
+
+	gt.V(t, gt.R1(store.Get(ctx, "not_found")).NoError(t)).Nil()
+
+	entries := gt.R1(store.List(ctx)).NoError(t)
+	gt.A(t, entries).Length(2)
+	gt.Equal(t, entries[0].ID, "first")
+	gt.Equal(t, entries[1].ID, "second")
+
+	gt.NoError(t, store.Delete(ctx, "first"))
+	gt.NoError(t, store.Delete(ctx, "first"))
+	gt.A(t, gt.R1(store.List(ctx)).NoError(t)).Length(1)
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, deadletter.NewMemory())
+}
+
+func TestFile(t *testing.T) {
+	testStore(t, gt.R1(deadletter.NewFile(t.TempDir())).NoError(t))
+}
@@ -0,0 +1,122 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+const fileExt = ".json"
+
+// File is dead-letter store backed by local directory. Each entry is stored as a JSON file.
+type File struct {
+	dir string
+}
+
+var _ interfaces.DeadLetterStore = (*File)(nil)
+
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, goerr.Wrap(err, "failed to create dead-letter directory", goerr.V("dir", dir))
+	}
+
+	return &File{dir: dir}, nil
+}
+
+func (x *File) path(id string) (string, error) {
+	if id == "" || filepath.Base(id) != id {
+		return "", goerr.New("invalid dead-letter ID", goerr.V("id", id))
+	}
+	return filepath.Join(x.dir, id+fileExt), nil
+}
+
+func (x *File) Put(ctx context.Context, entry *model.DeadLetter) error {
+	path, err := x.path(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal dead-letter", goerr.V("id", entry.ID))
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return goerr.Wrap(err, "failed to write dead-letter", goerr.V("path", tmp))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return goerr.Wrap(err, "failed to rename dead-letter file", goerr.V("path", path))
+	}
+
+	return nil
+}
+
+func (x *File) Get(ctx context.Context, id string) (*model.DeadLetter, error) {
+	path, err := x.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, goerr.Wrap(err, "failed to read dead-letter", goerr.V("path", path))
+	}
+
+	var entry model.DeadLetter
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, goerr.Wrap(err, "failed to unmarshal dead-letter", goerr.V("path", path))
+	}
+
+	return &entry, nil
+}
+
+func (x *File) List(ctx context.Context) ([]*model.DeadLetter, error) {
+	files, err := os.ReadDir(x.dir)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read dead-letter directory", goerr.V("dir", x.dir))
+	}
+
+	var entries []*model.DeadLetter
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), fileExt) {
+			continue
+		}
+
+		entry, err := x.Get(ctx, strings.TrimSuffix(file.Name(), fileExt))
+		if err != nil {
+			logging.Extract(ctx).Error("Broken dead-letter file is skipped", "file", file.Name(), "error", err)
+			continue
+		}
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	sortEntries(entries)
+
+	return entries, nil
+}
+
+func (x *File) Delete(ctx context.Context, id string) error {
+	path, err := x.path(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return goerr.Wrap(err, "failed to remove dead-letter", goerr.V("path", path))
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package deadletter
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// Memory is in-memory dead-letter store. Entries are lost when the process exits.
+type Memory struct {
+	mutex   sync.Mutex
+	entries map[string]model.DeadLetter
+}
+
+var _ interfaces.DeadLetterStore = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries: map[string]model.DeadLetter{},
+	}
+}
+
+func (x *Memory) Put(ctx context.Context, entry *model.DeadLetter) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	x.entries[entry.ID] = *entry
+	return nil
+}
+
+func (x *Memory) Get(ctx context.Context, id string) (*model.DeadLetter, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	entry, ok := x.entries[id]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (x *Memory) List(ctx context.Context) ([]*model.DeadLetter, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	entries := make([]*model.DeadLetter, 0, len(x.entries))
+	for _, entry := range x.entries {
+		entries = append(entries, &entry)
+	}
+	sortEntries(entries)
+
+	return entries, nil
+}
+
+func (x *Memory) Delete(ctx context.Context, id string) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	delete(x.entries, id)
+	return nil
+}
+
+func sortEntries(entries []*model.DeadLetter) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].FailedAt.Before(entries[j].FailedAt)
+	})
+}
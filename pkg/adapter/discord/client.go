@@ -0,0 +1,173 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+// httpTimeout is timeout of default HTTP client so that a stalled Discord webhook does not block delivery forever.
+const httpTimeout = 10 * time.Second
+
+// Client posts message to Discord webhook. It tracks rate limit bucket given by X-RateLimit-Bucket header, and does not send request while the bucket of the webhook or the global rate limit is exhausted. See https://discord.com/developers/docs/topics/rate-limits
+type Client struct {
+	webhooks   map[string]string
+	httpClient interfaces.HTTPClient
+
+	mutex sync.Mutex
+	// routes is map of webhook URL to bucket ID. Webhooks sharing a bucket share its rate limit.
+	routes        map[string]string
+	buckets       map[string]*bucket
+	globalResetAt time.Time
+}
+
+type bucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces HTTP client. Default is http.Client with httpTimeout.
+func WithHTTPClient(client interfaces.HTTPClient) Option {
+	return func(x *Client) {
+		x.httpClient = client
+	}
+}
+
+// New creates Discord client. webhooks is map of channel name to webhook URL.
+func New(webhooks map[string]string, options ...Option) *Client {
+	client := &Client{
+		webhooks:   webhooks,
+		httpClient: &http.Client{Timeout: httpTimeout},
+		routes:     map[string]string{},
+		buckets:    map[string]*bucket{},
+	}
+	for _, opt := range options {
+		opt(client)
+	}
+	return client
+}
+
+// Post implements interfaces.Discord.
+func (x *Client) Post(ctx context.Context, channel string, payload any) error {
+	url, ok := x.webhooks[channel]
+	if !ok {
+		return goerr.New("Discord webhook is not configured for the channel", goerr.V("channel", channel))
+	}
+
+	if wait := x.waitFor(url); wait > 0 {
+		return goerr.Wrap(types.NewHTTPStatusError("discord webhook", http.StatusTooManyRequests, "rate limit is exhausted", wait),
+			"discord webhook is rate limited", goerr.V("channel", channel))
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal Discord payload")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+	if err != nil {
+		return goerr.Wrap(err, "failed to create Discord request", goerr.V("channel", channel))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := x.httpClient.Do(req)
+	if err != nil {
+		return goerr.Wrap(err, "failed to send Discord request", goerr.V("channel", channel))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	x.updateBucket(url, resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		var retryAfter time.Duration
+		if resp.StatusCode == http.StatusTooManyRequests {
+			var global bool
+			retryAfter, global = parseRateLimit(resp.Header, body)
+			if global {
+				x.updateGlobal(retryAfter)
+			}
+		}
+		discordErr := types.NewHTTPStatusError("discord webhook", resp.StatusCode, string(body), retryAfter)
+		return goerr.Wrap(discordErr, "failed to post Discord message", goerr.V("channel", channel))
+	}
+
+	return nil
+}
+
+// waitFor returns duration until both the global rate limit and the bucket of the webhook are reset. It's zero if request can be sent now.
+func (x *Client) waitFor(url string) time.Duration {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	now := time.Now()
+	wait := max(x.globalResetAt.Sub(now), 0)
+	if b, ok := x.buckets[x.routes[url]]; ok && b.remaining <= 0 {
+		wait = max(wait, b.resetAt.Sub(now))
+	}
+	return wait
+}
+
+// updateBucket records rate limit of the bucket given by X-RateLimit-Bucket header. The webhook URL is used as bucket ID if the header is missing.
+func (x *Client) updateBucket(url string, header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil {
+		return
+	}
+	id := header.Get("X-RateLimit-Bucket")
+	if id == "" {
+		id = url
+	}
+
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	x.routes[url] = id
+	x.buckets[id] = &bucket{
+		remaining: remaining,
+		resetAt:   time.Now().Add(time.Duration(resetAfter * float64(time.Second))),
+	}
+}
+
+// updateGlobal blocks all webhooks until the global rate limit is reset.
+func (x *Client) updateGlobal(retryAfter time.Duration) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	if resetAt := time.Now().Add(retryAfter); resetAt.After(x.globalResetAt) {
+		x.globalResetAt = resetAt
+	}
+}
+
+// parseRateLimit reads retry_after (seconds) and global flag of 429 response body, and falls back to Retry-After and X-RateLimit-Global headers.
+func parseRateLimit(header http.Header, body []byte) (time.Duration, bool) {
+	var resp struct {
+		RetryAfter float64 `json:"retry_after"`
+		Global     bool    `json:"global"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	global := resp.Global || strings.EqualFold(header.Get("X-RateLimit-Global"), "true")
+
+	if resp.RetryAfter > 0 {
+		return time.Duration(resp.RetryAfter * float64(time.Second)), global
+	}
+	if sec, err := strconv.ParseFloat(header.Get("Retry-After"), 64); err == nil && sec > 0 {
+		return time.Duration(sec * float64(time.Second)), global
+	}
+	return 0, global
+}
@@ -0,0 +1,114 @@
+package discord_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/discord"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+)
+
+func TestPost(t *testing.T) {
+	var received map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client := discord.New(map[string]string{"community": ts.URL})
+	gt.NoError(t, client.Post(context.Background(), "community", map[string]any{"content": "hello"}))
+	gt.Equal(t, received["content"], "hello")
+
+	t.Run("unknown channel", func(t *testing.T) {
+		gt.Error(t, client.Post(context.Background(), "unknown", map[string]any{}))
+	})
+}
+
+func TestRateLimitBucket(t *testing.T) {
+	newServer := func(bucket string, called *int) *httptest.Server {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*called++
+			w.Header().Set("X-RateLimit-Bucket", bucket)
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset-After", "30.5")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(ts.Close)
+		return ts
+	}
+
+	var called, otherCalled int
+	ts := newServer("abcd", &called)
+	other := newServer("efgh", &otherCalled)
+
+	client := discord.New(map[string]string{"community": ts.URL, "alias": ts.URL, "other": other.URL})
+	ctx := context.Background()
+	gt.NoError(t, client.Post(ctx, "community", map[string]any{}))
+
+	// Bucket is exhausted, so the request is not sent
+	err := client.Post(ctx, "community", map[string]any{})
+	var discordErr *types.HTTPStatusError
+	gt.True(t, errors.As(err, &discordErr))
+	gt.True(t, discordErr.Retryable())
+	gt.True(t, discordErr.RetryAfter() > 29*time.Second)
+	gt.Equal(t, called, 1)
+
+	// Channel of the same webhook shares the bucket
+	gt.Error(t, client.Post(ctx, "alias", map[string]any{}))
+	gt.Equal(t, called, 1)
+
+	// Webhook of other bucket is independent
+	gt.NoError(t, client.Post(ctx, "other", map[string]any{}))
+	gt.Equal(t, otherCalled, 1)
+}
+
+func TestGlobalRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Global", "true")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"You are being rate limited.","retry_after":20,"global":true}`))
+	}))
+	defer ts.Close()
+
+	var otherCalled int
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherCalled++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer other.Close()
+
+	client := discord.New(map[string]string{"community": ts.URL, "other": other.URL})
+	ctx := context.Background()
+	gt.Error(t, client.Post(ctx, "community", map[string]any{}))
+
+	// Global rate limit blocks all webhooks
+	err := client.Post(ctx, "other", map[string]any{})
+	var discordErr *types.HTTPStatusError
+	gt.True(t, errors.As(err, &discordErr))
+	gt.True(t, discordErr.RetryAfter() > 19*time.Second)
+	gt.Equal(t, otherCalled, 0)
+}
+
+func TestTooManyRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"You are being rate limited.","retry_after":1.5,"global":false}`))
+	}))
+	defer ts.Close()
+
+	client := discord.New(map[string]string{"community": ts.URL})
+	err := client.Post(context.Background(), "community", map[string]any{})
+
+	var discordErr *types.HTTPStatusError
+	gt.True(t, errors.As(err, &discordErr))
+	gt.Equal(t, discordErr.StatusCode, http.StatusTooManyRequests)
+	gt.Equal(t, discordErr.RetryAfter(), 1500*time.Millisecond)
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	gogithub "github.com/google/go-github/v68/github"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+const (
+	// appJWTLifetime is lifetime of JWT for GitHub App. GitHub accepts 10 minutes at most.
+	appJWTLifetime = 9 * time.Minute
+
+	// installationTokenMargin is margin to refresh installation token before it expires.
+	installationTokenMargin = 5 * time.Minute
+)
+
+// appTransport authenticates request as GitHub App by JWT. It's used only to issue installation token.
+type appTransport struct {
+	appID int64
+	key   *rsa.PrivateKey
+	base  http.RoundTripper
+}
+
+func newAppTransport(appID int64, privateKey []byte, base http.RoundTripper) (*appTransport, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, goerr.New("private key of GitHub App is not PEM", goerr.V("app_id", appID))
+	}
+
+	// GitHub provides PKCS#1 key, but PKCS#8 is also accepted.
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err8 := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err8 != nil {
+			return nil, goerr.Wrap(err, "failed to parse private key of GitHub App", goerr.V("app_id", appID))
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, goerr.New("private key of GitHub App must be RSA", goerr.V("app_id", appID))
+		}
+		key = rsaKey
+	}
+
+	return &appTransport{appID: appID, key: key, base: base}, nil
+}
+
+func (x *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := time.Now()
+	// iat is set in the past to allow clock drift. See https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+	token, err := jwt.NewBuilder().
+		Issuer(strconv.FormatInt(x.appID, 10)).
+		IssuedAt(now.Add(-time.Minute)).
+		Expiration(now.Add(appJWTLifetime)).
+		Build()
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to build JWT of GitHub App")
+	}
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, x.key))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to sign JWT of GitHub App")
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+string(signed))
+	return x.base.RoundTrip(req)
+}
+
+// installationTransport authenticates request by installation token of GitHub App. The token is cached until shortly before it expires.
+type installationTransport struct {
+	apps           *gogithub.AppsService
+	installationID int64
+	base           http.RoundTripper
+
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (x *installationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := x.getToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+	return x.base.RoundTrip(req)
+}
+
+func (x *installationTransport) getToken(ctx context.Context) (string, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	if x.token != "" && time.Now().Add(installationTokenMargin).Before(x.expiresAt) {
+		return x.token, nil
+	}
+
+	token, _, err := x.apps.CreateInstallationToken(ctx, x.installationID, nil)
+	if err != nil {
+		return "", goerr.Wrap(convertError(err), "failed to create installation token of GitHub App", goerr.V("installation_id", x.installationID))
+	}
+
+	x.token = token.GetToken()
+	x.expiresAt = token.GetExpiresAt().Time
+	return x.token, nil
+}
@@ -0,0 +1,219 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	gogithub "github.com/google/go-github/v68/github"
+	"github.com/m-mizutani/goerr/v2"
+)
+
+const (
+	listIssuesPerPage  = 100
+	listIssuesMaxPages = 5
+
+	// httpTimeout bounds each API request, including installation token exchange of GitHub App, so that a stalled API does not block delivery forever.
+	httpTimeout = 10 * time.Second
+)
+
+// Client manages issues and comments via GitHub REST API. It's authenticated by personal access token or GitHub App installation.
+type Client struct {
+	client *gogithub.Client
+}
+
+type config struct {
+	baseURL   string
+	transport http.RoundTripper
+}
+
+type Option func(*config)
+
+// WithBaseURL sets base URL of REST API, e.g. https://github.example.com/api/v3/ for GitHub Enterprise Server. Default is https://api.github.com/.
+func WithBaseURL(baseURL string) Option {
+	return func(x *config) {
+		x.baseURL = baseURL
+	}
+}
+
+// WithTransport replaces base transport of HTTP client. Default is http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(x *config) {
+		x.transport = transport
+	}
+}
+
+func newConfig(options []Option) *config {
+	cfg := &config{transport: http.DefaultTransport}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func newGitHubClient(cfg *config, transport http.RoundTripper) (*gogithub.Client, error) {
+	client := gogithub.NewClient(&http.Client{Transport: transport, Timeout: httpTimeout})
+	if cfg.baseURL != "" {
+		u, err := url.Parse(cfg.baseURL)
+		if err != nil || u.Host == "" {
+			return nil, goerr.New("invalid GitHub API base URL", goerr.V("url", cfg.baseURL))
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+		}
+		client.BaseURL = u
+	}
+	return client, nil
+}
+
+// NewWithToken creates client authenticated by personal access token.
+func NewWithToken(token string, options ...Option) (*Client, error) {
+	if token == "" {
+		return nil, goerr.New("GitHub token is empty")
+	}
+
+	cfg := newConfig(options)
+	client, err := newGitHubClient(cfg, cfg.transport)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client: client.WithAuthToken(token)}, nil
+}
+
+// NewWithApp creates client authenticated as GitHub App installation. privateKey is PEM encoded private key of the App. Installation token is issued on demand and refreshed before it expires.
+func NewWithApp(appID, installationID int64, privateKey []byte, options ...Option) (*Client, error) {
+	cfg := newConfig(options)
+
+	appAuth, err := newAppTransport(appID, privateKey, cfg.transport)
+	if err != nil {
+		return nil, err
+	}
+	appClient, err := newGitHubClient(cfg, appAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newGitHubClient(cfg, &installationTransport{
+		apps:           appClient.Apps,
+		installationID: installationID,
+		base:           cfg.transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{client: client}, nil
+}
+
+// Error is returned when GitHub API responds error status.
+type Error struct {
+	StatusCode int
+	Message    string
+	retryAfter time.Duration
+}
+
+func (x *Error) Error() string {
+	return "github API returned status " + strconv.Itoa(x.StatusCode) + ": " + x.Message
+}
+
+// Retryable returns true if the request is rate limited or failed by server error.
+func (x *Error) Retryable() bool {
+	return x.retryAfter > 0 || x.StatusCode == http.StatusTooManyRequests || x.StatusCode >= 500
+}
+
+// RetryAfter returns duration until the rate limit is reset. It's zero if unknown.
+func (x *Error) RetryAfter() time.Duration {
+	return x.retryAfter
+}
+
+// convertError converts error of go-github into Error so that the delivery can decide to retry. Other errors, e.g. network error, are returned as is.
+func convertError(err error) error {
+	var rateErr *gogithub.RateLimitError
+	if errors.As(err, &rateErr) {
+		return &Error{
+			StatusCode: statusCode(rateErr.Response),
+			Message:    rateErr.Message,
+			retryAfter: max(time.Until(rateErr.Rate.Reset.Time), time.Second),
+		}
+	}
+
+	var abuseErr *gogithub.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		retryAfter := time.Minute
+		if abuseErr.RetryAfter != nil {
+			retryAfter = *abuseErr.RetryAfter
+		}
+		return &Error{
+			StatusCode: statusCode(abuseErr.Response),
+			Message:    abuseErr.Message,
+			retryAfter: retryAfter,
+		}
+	}
+
+	var respErr *gogithub.ErrorResponse
+	if errors.As(err, &respErr) {
+		return &Error{
+			StatusCode: statusCode(respErr.Response),
+			Message:    respErr.Message,
+		}
+	}
+
+	return err
+}
+
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// CreateIssue implements interfaces.GitHub.
+func (x *Client) CreateIssue(ctx context.Context, owner, repo string, req *gogithub.IssueRequest) (*gogithub.Issue, error) {
+	issue, _, err := x.client.Issues.Create(ctx, owner, repo, req)
+	if err != nil {
+		return nil, goerr.Wrap(convertError(err), "failed to create GitHub issue", goerr.V("owner", owner), goerr.V("repo", repo))
+	}
+	return issue, nil
+}
+
+// CreateComment implements interfaces.GitHub.
+func (x *Client) CreateComment(ctx context.Context, owner, repo string, number int, body string) (*gogithub.IssueComment, error) {
+	comment, _, err := x.client.Issues.CreateComment(ctx, owner, repo, number, &gogithub.IssueComment{Body: &body})
+	if err != nil {
+		return nil, goerr.Wrap(convertError(err), "failed to create GitHub comment", goerr.V("owner", owner), goerr.V("repo", repo), goerr.V("number", number))
+	}
+	return comment, nil
+}
+
+// ListOpenIssues implements interfaces.GitHub. It returns at most 500 issues in order of update.
+func (x *Client) ListOpenIssues(ctx context.Context, owner, repo string, labels []string) ([]*gogithub.Issue, error) {
+	opts := &gogithub.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      labels,
+		Sort:        "updated",
+		ListOptions: gogithub.ListOptions{PerPage: listIssuesPerPage},
+	}
+
+	var issues []*gogithub.Issue
+	for page := 0; page < listIssuesMaxPages; page++ {
+		found, resp, err := x.client.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, goerr.Wrap(convertError(err), "failed to list GitHub issues", goerr.V("owner", owner), goerr.V("repo", repo))
+		}
+		for _, issue := range found {
+			if !issue.IsPullRequest() {
+				issues = append(issues, issue)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return issues, nil
+}
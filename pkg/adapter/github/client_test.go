@@ -0,0 +1,155 @@
+package github_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gogithub "github.com/google/go-github/v68/github"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/github"
+)
+
+func TestWithToken(t *testing.T) {
+	var received map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.Header.Get("Authorization"), "Bearer my-pat")
+		gt.Equal(t, r.Method, http.MethodPost)
+		gt.Equal(t, r.URL.Path, "/api/v3/repos/org/repo/issues")
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 12}`))
+	}))
+	defer ts.Close()
+
+	client := gt.R1(github.NewWithToken("my-pat", github.WithBaseURL(ts.URL+"/api/v3"))).NoError(t)
+	issue := gt.R1(client.CreateIssue(context.Background(), "org", "repo", &gogithub.IssueRequest{
+		Title:  gogithub.Ptr("Finding"),
+		Labels: &[]string{"security"},
+	})).NoError(t)
+	gt.Equal(t, issue.GetNumber(), 12)
+	gt.Equal(t, received["title"], "Finding")
+}
+
+func TestWithApp(t *testing.T) {
+	key := gt.R1(rsa.GenerateKey(rand.Reader, 2048)).NoError(t)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var tokenIssued int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/5678/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokenIssued++
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token := gt.R1(jwt.ParseString(auth, jwt.WithKey(jwa.RS256, &key.PublicKey))).NoError(t)
+		gt.Equal(t, token.Issuer(), "1234")
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      "installation-token",
+			"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+		})
+	})
+	mux.HandleFunc("POST /repos/org/repo/issues/3/comments", func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.Header.Get("Authorization"), "token installation-token")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client := gt.R1(github.NewWithApp(1234, 5678, privateKey, github.WithBaseURL(ts.URL))).NoError(t)
+	ctx := context.Background()
+	gt.R1(client.CreateComment(ctx, "org", "repo", 3, "hello")).NoError(t)
+	gt.R1(client.CreateComment(ctx, "org", "repo", 3, "hello again")).NoError(t)
+
+	// Installation token is reused until it expires
+	gt.Equal(t, tokenIssued, 1)
+
+	t.Run("invalid private key", func(t *testing.T) {
+		gt.R1(github.NewWithApp(1234, 5678, []byte("not a key"))).Error(t)
+	})
+}
+
+func TestListOpenIssues(t *testing.T) {
+	var pages int
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		gt.Equal(t, r.URL.Query().Get("state"), "open")
+		gt.Equal(t, r.URL.Query().Get("labels"), "xroute,security")
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+ts.URL+`/repos/org/repo/issues?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"number": 1}, {"number": 2, "pull_request": {"url": "x"}}]`))
+		case "2":
+			_, _ = w.Write([]byte(`[{"number": 3}]`))
+		}
+	}))
+	defer ts.Close()
+
+	client := gt.R1(github.NewWithToken("my-pat", github.WithBaseURL(ts.URL))).NoError(t)
+	issues := gt.R1(client.ListOpenIssues(context.Background(), "org", "repo", []string{"xroute", "security"})).NoError(t)
+	gt.Equal(t, pages, 2)
+	gt.A(t, issues).Length(2)
+	gt.Equal(t, issues[0].GetNumber(), 1)
+	gt.Equal(t, issues[1].GetNumber(), 3)
+}
+
+func TestError(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	testCases := map[string]struct {
+		status    int
+		header    map[string]string
+		retryable bool
+	}{
+		"rate limited": {
+			status: http.StatusForbidden,
+			header: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+			retryable: true,
+		},
+		"secondary rate limit": {
+			status:    http.StatusForbidden,
+			header:    map[string]string{"Retry-After": "60"},
+			retryable: true,
+		},
+		"server error": {status: http.StatusBadGateway, retryable: true},
+		"not found":    {status: http.StatusNotFound, retryable: false},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{"message": "error", "documentation_url": "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`))
+			}))
+			defer ts.Close()
+
+			client := gt.R1(github.NewWithToken("my-pat", github.WithBaseURL(ts.URL))).NoError(t)
+			_, err := client.CreateComment(context.Background(), "org", "repo", 1, "hello")
+
+			var ghErr *github.Error
+			gt.True(t, errors.As(err, &ghErr))
+			gt.Equal(t, ghErr.StatusCode, tc.status)
+			gt.Equal(t, ghErr.Retryable(), tc.retryable)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+// DefaultBaseURL is base URL of PagerDuty Events API v2.
+const DefaultBaseURL = "https://events.pagerduty.com"
+
+// httpTimeout is timeout of default HTTP client so that a stalled Events API does not block delivery forever.
+const httpTimeout = 10 * time.Second
+
+// Client sends event to PagerDuty Events API v2. See https://developer.pagerduty.com/docs/events-api-v2/overview/
+type Client struct {
+	routingKeys map[string]string
+	baseURL     string
+	httpClient  interfaces.HTTPClient
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces HTTP client. Default is http.Client with httpTimeout.
+func WithHTTPClient(client interfaces.HTTPClient) Option {
+	return func(x *Client) {
+		x.httpClient = client
+	}
+}
+
+// WithBaseURL replaces base URL of Events API. Default is DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(x *Client) {
+		x.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates PagerDuty client. routingKeys is map of name to integration key of the service.
+func New(routingKeys map[string]string, options ...Option) *Client {
+	client := &Client{
+		routingKeys: routingKeys,
+		baseURL:     DefaultBaseURL,
+		httpClient:  &http.Client{Timeout: httpTimeout},
+	}
+	for _, opt := range options {
+		opt(client)
+	}
+	return client
+}
+
+// Enqueue implements interfaces.PagerDuty.
+func (x *Client) Enqueue(ctx context.Context, routingKey string, event map[string]any) error {
+	key, ok := x.routingKeys[routingKey]
+	if !ok {
+		return goerr.New("PagerDuty routing key is not configured", goerr.V("routing_key", routingKey))
+	}
+
+	body := make(map[string]any, len(event)+1)
+	maps.Copy(body, event)
+	body["routing_key"] = key
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal PagerDuty event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.baseURL+"/v2/enqueue", bytes.NewReader(raw))
+	if err != nil {
+		return goerr.Wrap(err, "failed to create PagerDuty request", goerr.V("routing_key", routingKey))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := x.httpClient.Do(req)
+	if err != nil {
+		return goerr.Wrap(err, "failed to send PagerDuty request", goerr.V("routing_key", routingKey))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return goerr.Wrap(types.NewHTTPStatusError("pagerduty", resp.StatusCode, string(body), types.ParseRetryAfter(resp.Header)),
+			"failed to enqueue PagerDuty event", goerr.V("routing_key", routingKey))
+	}
+
+	return nil
+}
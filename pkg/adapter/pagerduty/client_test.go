@@ -0,0 +1,70 @@
+package pagerduty_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/pagerduty"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+)
+
+func TestEnqueue(t *testing.T) {
+	var received map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.Method, http.MethodPost)
+		gt.Equal(t, r.URL.Path, "/v2/enqueue")
+		gt.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"status":"success","message":"Event processed","dedup_key":"ci/main"}`))
+	}))
+	defer ts.Close()
+
+	client := pagerduty.New(map[string]string{"ci": "secret-key"}, pagerduty.WithBaseURL(ts.URL+"/"))
+	event := map[string]any{"event_action": "resolve", "dedup_key": "ci/main"}
+	gt.NoError(t, client.Enqueue(context.Background(), "ci", event))
+	gt.Equal(t, received["routing_key"], "secret-key")
+	gt.Equal(t, received["event_action"], "resolve")
+	gt.Equal(t, received["dedup_key"], "ci/main")
+
+	// Given event is not modified
+	_, ok := event["routing_key"]
+	gt.False(t, ok)
+
+	t.Run("unknown routing key", func(t *testing.T) {
+		gt.Error(t, client.Enqueue(context.Background(), "unknown", map[string]any{}))
+	})
+}
+
+func TestEnqueueError(t *testing.T) {
+	testCases := map[string]struct {
+		status    int
+		retryable bool
+	}{
+		"throttled":     {status: http.StatusTooManyRequests, retryable: true},
+		"server error":  {status: http.StatusInternalServerError, retryable: true},
+		"invalid event": {status: http.StatusBadRequest, retryable: false},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer ts.Close()
+
+			client := pagerduty.New(map[string]string{"ci": "secret-key"}, pagerduty.WithBaseURL(ts.URL))
+			err := client.Enqueue(context.Background(), "ci", map[string]any{})
+
+			var pdErr *types.HTTPStatusError
+			gt.True(t, errors.As(err, &pdErr))
+			gt.Equal(t, pdErr.StatusCode, tc.status)
+			gt.Equal(t, pdErr.Retryable(), tc.retryable)
+		})
+	}
+}
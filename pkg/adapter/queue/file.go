@@ -0,0 +1,177 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+const (
+	pendingExt  = ".json"
+	inflightExt = ".inflight"
+)
+
+// File is queue backed by local directory. Each delivery is stored as a JSON file, so queued deliveries survive restart of the process. Deliveries that were in-flight when the process exited are restored to pending by NewFile. A delivery that had been transmitted but not marked as done before the exit is transmitted again, so delivery is at-least-once.
+type File struct {
+	mutex sync.Mutex
+	dir   string
+}
+
+var _ interfaces.Queue = (*File)(nil)
+
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, goerr.Wrap(err, "failed to create queue directory", goerr.V("dir", dir))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read queue directory", goerr.V("dir", dir))
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), inflightExt) {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), inflightExt)
+		src := filepath.Join(dir, entry.Name())
+		dst := filepath.Join(dir, id+pendingExt)
+
+		// The delivery has already been requeued for retry but the process exited before removing the in-flight one. The requeued one is newer.
+		if _, err := os.Stat(dst); err == nil {
+			if err := os.Remove(src); err != nil {
+				return nil, goerr.Wrap(err, "failed to remove stale in-flight delivery", goerr.V("path", src))
+			}
+			continue
+		}
+
+		if err := os.Rename(src, dst); err != nil {
+			return nil, goerr.Wrap(err, "failed to restore in-flight delivery", goerr.V("path", src))
+		}
+	}
+
+	return &File{dir: dir}, nil
+}
+
+func (x *File) path(id, ext string) (string, error) {
+	if id == "" || filepath.Base(id) != id {
+		return "", goerr.New("invalid delivery ID", goerr.V("id", id))
+	}
+	return filepath.Join(x.dir, id+ext), nil
+}
+
+func (x *File) Push(ctx context.Context, delivery *model.Delivery) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	path, err := x.path(delivery.ID, pendingExt)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(delivery)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal delivery", goerr.V("id", delivery.ID))
+	}
+
+	// Write to temporary file and rename it to avoid reading partially written file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return goerr.Wrap(err, "failed to write delivery", goerr.V("path", tmp))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return goerr.Wrap(err, "failed to rename delivery file", goerr.V("path", path))
+	}
+
+	// Requeued delivery replaces the in-flight one
+	inflight, err := x.path(delivery.ID, inflightExt)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(inflight); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return goerr.Wrap(err, "failed to remove in-flight delivery", goerr.V("path", inflight))
+	}
+
+	return nil
+}
+
+func (x *File) Pop(ctx context.Context, now time.Time) (*model.Delivery, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	entries, err := os.ReadDir(x.dir)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read queue directory", goerr.V("dir", x.dir))
+	}
+
+	var next *model.Delivery
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), pendingExt) {
+			continue
+		}
+
+		path := filepath.Join(x.dir, entry.Name())
+		raw, err := os.ReadFile(path) // #nosec G304
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to read delivery", goerr.V("path", path))
+		}
+
+		var d model.Delivery
+		if err := json.Unmarshal(raw, &d); err != nil {
+			logging.Extract(ctx).Error("Broken delivery file is skipped", "path", path, "error", err)
+			continue
+		}
+
+		if d.NextAttemptAt.After(now) {
+			continue
+		}
+		if next == nil || isBefore(&d, next) {
+			next = &d
+		}
+	}
+
+	if next == nil {
+		return nil, nil
+	}
+
+	src, err := x.path(next.ID, pendingExt)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := x.path(next.ID, inflightExt)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return nil, goerr.Wrap(err, "failed to mark delivery as in-flight", goerr.V("path", src))
+	}
+
+	return next, nil
+}
+
+func (x *File) Done(ctx context.Context, id string) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	path, err := x.path(id, inflightExt)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return goerr.Wrap(err, "failed to remove delivery", goerr.V("path", path))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// Memory is in-memory queue. Queued deliveries are lost when the process exits.
+type Memory struct {
+	mutex    sync.Mutex
+	pending  map[string]model.Delivery
+	inflight map[string]model.Delivery
+}
+
+var _ interfaces.Queue = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{
+		pending:  map[string]model.Delivery{},
+		inflight: map[string]model.Delivery{},
+	}
+}
+
+func (x *Memory) Push(ctx context.Context, delivery *model.Delivery) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	x.pending[delivery.ID] = *delivery
+	delete(x.inflight, delivery.ID)
+	return nil
+}
+
+func (x *Memory) Pop(ctx context.Context, now time.Time) (*model.Delivery, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	var next *model.Delivery
+	for _, d := range x.pending {
+		if d.NextAttemptAt.After(now) {
+			continue
+		}
+		if next == nil || isBefore(&d, next) {
+			next = &d
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	delete(x.pending, next.ID)
+	x.inflight[next.ID] = *next
+	return next, nil
+}
+
+func (x *Memory) Done(ctx context.Context, id string) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	delete(x.inflight, id)
+	return nil
+}
+
+// Len returns number of pending and in-flight deliveries.
+func (x *Memory) Len() int {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	return len(x.pending) + len(x.inflight)
+}
+
+// isBefore returns true if a should be processed before b.
+func isBefore(a, b *model.Delivery) bool {
+	if !a.NextAttemptAt.Equal(b.NextAttemptAt) {
+		return a.NextAttemptAt.Before(b.NextAttemptAt)
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
@@ -0,0 +1,118 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+func testQueue(t *testing.T, q interfaces.Queue) {
+	ctx := context.Background()
+	now := time.Now()
+
+	gt.NoError(t, q.Push(ctx, &model.Delivery{
+		ID:            "later",
+		Slack:         &model.SlackMessage{Channel: "#later"},
+		CreatedAt:     now,
+		NextAttemptAt: now.Add(time.Hour),
+	}))
+	gt.NoError(t, q.Push(ctx, &model.Delivery{
+		ID:            "second",
+		Slack:         &model.SlackMessage{Channel: "#second"},
+		CreatedAt:     now.Add(time.Second),
+		NextAttemptAt: now,
+	}))
+	gt.NoError(t, q.Push(ctx, &model.Delivery{
+		ID:            "first",
+		Slack:         &model.SlackMessage{Channel: "#first"},
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}))
+
+	d1 := gt.R1(q.Pop(ctx, now)).NoError(t)
+	gt.V(t, d1).NotNil()
+	gt.Equal(t, d1.ID, "first")
+	gt.Equal(t, d1.Slack.Channel, "#first")
+
+	d2 := gt.R1(q.Pop(ctx, now)).NoError(t)
+	gt.V(t, d2).NotNil()
+	gt.Equal(t, d2.ID, "second")
+
+	// "later" is not ready yet
+	gt.V(t, gt.R1(q.Pop(ctx, now)).NoError(t)).Nil()
+
+	// Requeue in-flight delivery
+	d1.Attempts = 1
+	d1.NextAttemptAt = now.Add(time.Minute)
+	gt.NoError(t, q.Push(ctx, d1))
+	gt.NoError(t, q.Done(ctx, d1.ID))
+	gt.NoError(t, q.Done(ctx, d2.ID))
+
+	d3 := gt.R1(q.Pop(ctx, now.Add(2*time.Minute))).NoError(t)
+	gt.V(t, d3).NotNil()
+	gt.Equal(t, d3.ID, "first")
+	gt.Equal(t, d3.Attempts, 1)
+	gt.NoError(t, q.Done(ctx, d3.ID))
+
+	d4 := gt.R1(q.Pop(ctx, now.Add(2*time.Hour))).NoError(t)
+	gt.V(t, d4).NotNil()
+	gt.Equal(t, d4.ID, "later")
+	gt.NoError(t, q.Done(ctx, d4.ID))
+
+	gt.V(t, gt.R1(q.Pop(ctx, now.Add(2*time.Hour))).NoError(t)).Nil()
+}
+
+func TestMemory(t *testing.T) {
+	testQueue(t, queue.NewMemory())
+}
+
+func TestFile(t *testing.T) {
+	testQueue(t, gt.R1(queue.NewFile(t.TempDir())).NoError(t))
+}
+
+func TestFileRestoreInflight(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Now()
+
+	q1 := gt.R1(queue.NewFile(dir)).NoError(t)
+	gt.NoError(t, q1.Push(ctx, &model.Delivery{ID: "x", CreatedAt: now, NextAttemptAt: now}))
+	gt.V(t, gt.R1(q1.Pop(ctx, now)).NoError(t)).NotNil()
+	gt.V(t, gt.R1(q1.Pop(ctx, now)).NoError(t)).Nil()
+
+	// Simulate restart while the delivery is in-flight
+	q2 := gt.R1(queue.NewFile(dir)).NoError(t)
+	d := gt.R1(q2.Pop(ctx, now)).NoError(t)
+	gt.V(t, d).NotNil()
+	gt.Equal(t, d.ID, "x")
+}
+
+func TestFileRestoreRequeued(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Now()
+
+	q1 := gt.R1(queue.NewFile(dir)).NoError(t)
+	gt.NoError(t, q1.Push(ctx, &model.Delivery{ID: "x", CreatedAt: now, NextAttemptAt: now}))
+	d := gt.R1(q1.Pop(ctx, now)).NoError(t)
+
+	// Requeue for retry, and simulate restart before Done
+	d.Attempts = 1
+	gt.NoError(t, q1.Push(ctx, d))
+
+	q2 := gt.R1(queue.NewFile(dir)).NoError(t)
+	restored := gt.R1(q2.Pop(ctx, now)).NoError(t)
+	gt.V(t, restored).NotNil()
+	gt.Equal(t, restored.Attempts, 1)
+	gt.V(t, gt.R1(q2.Pop(ctx, now)).NoError(t)).Nil()
+}
+
+func TestFileInvalidID(t *testing.T) {
+	q := gt.R1(queue.NewFile(t.TempDir())).NoError(t)
+	gt.Error(t, q.Push(context.Background(), &model.Delivery{ID: "../escape"}))
+}
@@ -0,0 +1,321 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// TLSMode is how to secure connection to SMTP server.
+type TLSMode string
+
+const (
+	// TLSModeStartTLS upgrades connection by STARTTLS command. The server must support it.
+	TLSModeStartTLS TLSMode = "starttls"
+	// TLSModeImplicit connects with TLS from the beginning, e.g. port 465.
+	TLSModeImplicit TLSMode = "tls"
+	// TLSModeNone does not use TLS. It should be used only for local relay.
+	TLSModeNone TLSMode = "none"
+)
+
+// Client sends mail via SMTP relay.
+type Client struct {
+	addr      string
+	from      string
+	username  string
+	password  string
+	tlsMode   TLSMode
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+type Option func(*Client)
+
+// WithAuth enables SMTP AUTH PLAIN. It requires TLS unless the server is localhost.
+func WithAuth(username, password string) Option {
+	return func(x *Client) {
+		x.username = username
+		x.password = password
+	}
+}
+
+// WithTLSMode sets how to secure connection. Default is TLSModeStartTLS.
+func WithTLSMode(mode TLSMode) Option {
+	return func(x *Client) {
+		x.tlsMode = mode
+	}
+}
+
+// WithTLSConfig replaces TLS config. ServerName is set to host of addr if empty.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(x *Client) {
+		x.tlsConfig = cfg
+	}
+}
+
+// WithTimeout sets timeout of a SMTP session if ctx has no deadline. Default is 30 seconds.
+func WithTimeout(d time.Duration) Option {
+	return func(x *Client) {
+		x.timeout = d
+	}
+}
+
+// New creates SMTP client. addr is host:port of SMTP relay, and from is sender address.
+func New(addr, from string, options ...Option) (*Client, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, goerr.Wrap(err, "invalid SMTP address", goerr.V("addr", addr))
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, goerr.Wrap(err, "invalid sender address", goerr.V("from", from))
+	}
+
+	client := &Client{
+		addr:    addr,
+		from:    from,
+		tlsMode: TLSModeStartTLS,
+		timeout: 30 * time.Second,
+	}
+	for _, opt := range options {
+		opt(client)
+	}
+
+	switch client.tlsMode {
+	case TLSModeStartTLS, TLSModeImplicit, TLSModeNone:
+	default:
+		return nil, goerr.New("invalid SMTP TLS mode", goerr.V("mode", client.tlsMode))
+	}
+
+	return client, nil
+}
+
+// Error is returned when SMTP server rejects a command.
+type Error struct {
+	Code int
+	Msg  string
+}
+
+func (x *Error) Error() string {
+	return "smtp error " + strconv.Itoa(x.Code) + ": " + x.Msg
+}
+
+// Retryable returns true if the reply code is transient negative completion (4xx).
+func (x *Error) Retryable() bool {
+	return 400 <= x.Code && x.Code < 500
+}
+
+// Send implements interfaces.Email.
+func (x *Client) Send(ctx context.Context, m *model.Email) error {
+	from, err := mail.ParseAddress(x.from)
+	if err != nil {
+		return goerr.Wrap(err, "invalid sender address", goerr.V("from", x.from))
+	}
+
+	recipients, err := parseAddresses(append(append([]string{}, m.To...), m.Cc...))
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return goerr.New("no recipient of email", goerr.V("subject", m.Subject))
+	}
+
+	msg, err := buildMIME(from, m)
+	if err != nil {
+		return err
+	}
+
+	if err := x.send(ctx, from.Address, recipients, msg); err != nil {
+		var tpErr *textproto.Error
+		if errors.As(err, &tpErr) {
+			err = &Error{Code: tpErr.Code, Msg: tpErr.Msg}
+		}
+		return goerr.Wrap(err, "failed to send email", goerr.V("addr", x.addr), goerr.V("subject", m.Subject))
+	}
+
+	return nil
+}
+
+func (x *Client) send(ctx context.Context, from string, recipients []string, msg []byte) error {
+	host, _, _ := net.SplitHostPort(x.addr)
+	tlsConfig := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if x.tlsConfig != nil {
+		tlsConfig = x.tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = host
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", x.addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(x.timeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	if x.tlsMode == TLSModeImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if x.tlsMode == TLSModeStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return goerr.New("SMTP server does not support STARTTLS", goerr.V("addr", x.addr))
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if x.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", x.username, x.password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range recipients {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func parseAddresses(addrs []string) ([]string, error) {
+	parsed := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid email address", goerr.V("address", addr))
+		}
+		parsed = append(parsed, a.Address)
+	}
+	return parsed, nil
+}
+
+func formatAddresses(addrs []string) (string, error) {
+	formatted := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return "", goerr.Wrap(err, "invalid email address", goerr.V("address", addr))
+		}
+		formatted = append(formatted, a.String())
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+// buildMIME builds multipart/alternative message with plain text and HTML parts.
+func buildMIME(from *mail.Address, m *model.Email) ([]byte, error) {
+	to, err := formatAddresses(m.To)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := formatAddresses(m.Cc)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, goerr.Wrap(err, "failed to generate Message-ID")
+	}
+	domain := "localhost"
+	if i := strings.LastIndex(from.Address, "@"); i >= 0 {
+		domain = from.Address[i+1:]
+	}
+
+	header := []string{
+		"From: " + from.String(),
+		"To: " + to,
+	}
+	if cc != "" {
+		header = append(header, "Cc: "+cc)
+	}
+	header = append(header,
+		// Subject is encoded, so CR/LF in policy output can not inject header
+		"Subject: "+mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: "+time.Now().Format(time.RFC1123Z),
+		"Message-ID: <"+hex.EncodeToString(id)+"@"+domain+">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary="+mw.Boundary(),
+	)
+
+	var msg bytes.Buffer
+	msg.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create MIME part")
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.body)); err != nil {
+			return nil, goerr.Wrap(err, "failed to write MIME part")
+		}
+		if err := qw.Close(); err != nil {
+			return nil, goerr.Wrap(err, "failed to write MIME part")
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, goerr.Wrap(err, "failed to close MIME message")
+	}
+
+	msg.Write(buf.Bytes())
+	return msg.Bytes(), nil
+}
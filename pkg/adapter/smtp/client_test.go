@@ -0,0 +1,235 @@
+package smtp_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/smtp"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// fakeServer is minimal SMTP server supporting STARTTLS and AUTH PLAIN.
+type fakeServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	rcptCode  int
+
+	mutex sync.Mutex
+	auth  string
+	from  string
+	rcpt  []string
+	data  string
+	tls   bool
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, *tls.Config) {
+	// Borrow self-signed certificate of httptest for 127.0.0.1
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	listener := gt.R1(net.Listen("tcp", "127.0.0.1:0")).NoError(t)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	srv := &fakeServer{
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: ts.TLS.Certificates},
+		rcptCode:  250,
+	}
+	go srv.serve()
+
+	return srv, &tls.Config{RootCAs: pool}
+}
+
+func (x *fakeServer) addr() string {
+	return x.listener.Addr().String()
+}
+
+func (x *fakeServer) serve() {
+	for {
+		conn, err := x.listener.Accept()
+		if err != nil {
+			return
+		}
+		go x.handle(conn)
+	}
+}
+
+func (x *fakeServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+
+		x.mutex.Lock()
+		secured := x.tls
+		x.mutex.Unlock()
+
+		switch strings.ToUpper(cmd) {
+		case "EHLO":
+			if secured {
+				_ = tp.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+			} else {
+				_ = tp.PrintfLine("250-localhost\r\n250 STARTTLS")
+			}
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 ready")
+			tlsConn := tls.Server(conn, x.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			tp = textproto.NewConn(conn)
+			x.mutex.Lock()
+			x.tls = true
+			x.mutex.Unlock()
+		case "AUTH":
+			_, cred, _ := strings.Cut(arg, " ")
+			raw, _ := base64.StdEncoding.DecodeString(cred)
+			x.mutex.Lock()
+			x.auth = string(raw)
+			x.mutex.Unlock()
+			_ = tp.PrintfLine("235 ok")
+		case "MAIL":
+			x.mutex.Lock()
+			x.from = arg
+			x.mutex.Unlock()
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			if x.rcptCode != 250 {
+				_ = tp.PrintfLine("%d mailbox unavailable", x.rcptCode)
+				continue
+			}
+			x.mutex.Lock()
+			x.rcpt = append(x.rcpt, arg)
+			x.mutex.Unlock()
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			x.mutex.Lock()
+			x.data = string(data)
+			x.mutex.Unlock()
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	srv, tlsConfig := newFakeServer(t)
+
+	client := gt.R1(smtp.New(srv.addr(), "xroute <xroute@example.com>",
+		smtp.WithAuth("user", "pass"),
+		smtp.WithTLSConfig(tlsConfig),
+	)).NoError(t)
+
+	gt.NoError(t, client.Send(context.Background(), &model.Email{
+		To:      []string{"Alice <alice@example.com>"},
+		Cc:      []string{"bob@example.com"},
+		Subject: "Alert: 危険\r\nBcc: eve@example.com",
+		Text:    "hello",
+		HTML:    "<p>hello</p>",
+	}))
+
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	gt.True(t, srv.tls)
+	gt.Equal(t, srv.auth, "\x00user\x00pass")
+	gt.Equal(t, srv.from, "FROM:<xroute@example.com>")
+	gt.Equal(t, srv.rcpt, []string{"TO:<alice@example.com>", "TO:<bob@example.com>"})
+
+	msg := gt.R1(mail.ReadMessage(strings.NewReader(srv.data))).NoError(t)
+	gt.Equal(t, msg.Header.Get("To"), `"Alice" <alice@example.com>`)
+	gt.Equal(t, msg.Header.Get("Cc"), "<bob@example.com>")
+	gt.Equal(t, msg.Header.Get("Bcc"), "")
+	subject := gt.R1(new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))).NoError(t)
+	gt.Equal(t, subject, "Alert: 危険\r\nBcc: eve@example.com")
+
+	mediaType, params := gt.R2(mime.ParseMediaType(msg.Header.Get("Content-Type"))).NoError(t)
+	gt.Equal(t, mediaType, "multipart/alternative")
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		gt.NoError(t, err)
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+string(gt.R1(io.ReadAll(part)).NoError(t)))
+	}
+	gt.Equal(t, bodies, []string{
+		"text/plain; charset=utf-8: hello",
+		"text/html; charset=utf-8: <p>hello</p>",
+	})
+}
+
+func TestSendError(t *testing.T) {
+	testCases := map[string]struct {
+		rcptCode  int
+		retryable bool
+	}{
+		"temporary failure": {rcptCode: 451, retryable: true},
+		"permanent failure": {rcptCode: 550, retryable: false},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			srv, tlsConfig := newFakeServer(t)
+			srv.rcptCode = tc.rcptCode
+
+			client := gt.R1(smtp.New(srv.addr(), "xroute@example.com", smtp.WithTLSConfig(tlsConfig))).NoError(t)
+			err := client.Send(context.Background(), &model.Email{
+				To:      []string{"alice@example.com"},
+				Subject: "test",
+			})
+
+			var smtpErr *smtp.Error
+			gt.True(t, errors.As(err, &smtpErr))
+			gt.Equal(t, smtpErr.Code, tc.rcptCode)
+			gt.Equal(t, smtpErr.Retryable(), tc.retryable)
+		})
+	}
+}
+
+func TestInvalidAddress(t *testing.T) {
+	gt.R1(smtp.New("localhost", "xroute@example.com")).Error(t)
+	gt.R1(smtp.New("localhost:25", "not an address")).Error(t)
+	gt.R1(smtp.New("localhost:25", "xroute@example.com", smtp.WithTLSMode("ssl"))).Error(t)
+
+	client := gt.R1(smtp.New("localhost:25", "xroute@example.com")).NoError(t)
+	gt.Error(t, client.Send(context.Background(), &model.Email{To: []string{"broken"}}))
+	gt.Error(t, client.Send(context.Background(), &model.Email{}))
+}
@@ -0,0 +1,78 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+// httpTimeout is timeout of default HTTP client so that a stalled Teams webhook does not block delivery forever.
+const httpTimeout = 10 * time.Second
+
+// Client posts message to Microsoft Teams incoming webhook or Workflows URL.
+type Client struct {
+	webhooks   map[string]string
+	httpClient interfaces.HTTPClient
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces HTTP client. Default is http.Client with httpTimeout.
+func WithHTTPClient(client interfaces.HTTPClient) Option {
+	return func(x *Client) {
+		x.httpClient = client
+	}
+}
+
+// New creates Teams client. webhooks is map of channel name to webhook URL.
+func New(webhooks map[string]string, options ...Option) *Client {
+	client := &Client{
+		webhooks:   webhooks,
+		httpClient: &http.Client{Timeout: httpTimeout},
+	}
+	for _, opt := range options {
+		opt(client)
+	}
+	return client
+}
+
+// Post implements interfaces.Teams.
+func (x *Client) Post(ctx context.Context, channel string, payload any) error {
+	url, ok := x.webhooks[channel]
+	if !ok {
+		return goerr.New("Teams webhook is not configured for the channel", goerr.V("channel", channel))
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal Teams payload")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(raw))
+	if err != nil {
+		return goerr.Wrap(err, "failed to create Teams request", goerr.V("channel", channel))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := x.httpClient.Do(req)
+	if err != nil {
+		return goerr.Wrap(err, "failed to send Teams request", goerr.V("channel", channel))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		teamsErr := types.NewHTTPStatusError("teams webhook", resp.StatusCode, string(body), types.ParseRetryAfter(resp.Header))
+		return goerr.Wrap(teamsErr, "failed to post Teams message", goerr.V("channel", channel))
+	}
+
+	return nil
+}
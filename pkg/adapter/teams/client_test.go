@@ -0,0 +1,67 @@
+package teams_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/teams"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+)
+
+func TestPost(t *testing.T) {
+	var received map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		gt.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	client := teams.New(map[string]string{"alerts": ts.URL})
+	gt.NoError(t, client.Post(context.Background(), "alerts", map[string]any{"type": "message"}))
+	gt.Equal(t, received["type"], "message")
+
+	t.Run("unknown channel", func(t *testing.T) {
+		gt.Error(t, client.Post(context.Background(), "unknown", map[string]any{}))
+	})
+}
+
+func TestPostError(t *testing.T) {
+	testCases := map[string]struct {
+		status     int
+		retryAfter string
+		retryable  bool
+		delay      time.Duration
+	}{
+		"throttled":    {status: http.StatusTooManyRequests, retryAfter: "30", retryable: true, delay: 30 * time.Second},
+		"server error": {status: http.StatusBadGateway, retryable: true},
+		"bad request":  {status: http.StatusBadRequest, retryable: false},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.retryAfter != "" {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer ts.Close()
+
+			client := teams.New(map[string]string{"alerts": ts.URL})
+			err := client.Post(context.Background(), "alerts", map[string]any{})
+
+			var teamsErr *types.HTTPStatusError
+			gt.True(t, errors.As(err, &teamsErr))
+			gt.Equal(t, teamsErr.StatusCode, tc.status)
+			gt.Equal(t, teamsErr.Retryable(), tc.retryable)
+			gt.Equal(t, teamsErr.RetryAfter(), tc.delay)
+		})
+	}
+}
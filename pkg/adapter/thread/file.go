@@ -0,0 +1,175 @@
+package thread
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// File is thread store backed by local directory. Each thread is stored as a JSON file named by hash of channel and key.
+type File struct {
+	dir string
+	ttl time.Duration
+}
+
+// fileEntry is a saved thread, or a claim if TS of the thread is empty.
+type fileEntry struct {
+	Thread    model.SlackThread `json:"thread"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+var _ interfaces.SlackThreadStore = (*File)(nil)
+
+// NewFile creates thread store in the directory. Thread expires after ttl since it's saved.
+func NewFile(dir string, ttl time.Duration) (*File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, goerr.Wrap(err, "failed to create thread directory", goerr.V("dir", dir))
+	}
+
+	return &File{dir: dir, ttl: ttl}, nil
+}
+
+func (x *File) path(channel, key string) string {
+	h := sha256.Sum256([]byte(channel + "\x00" + key))
+	return filepath.Join(x.dir, hex.EncodeToString(h[:])+".json")
+}
+
+func (x *File) Get(ctx context.Context, channel, key string) (*model.SlackThread, error) {
+	entry, err := x.read(x.path(channel, key))
+	if err != nil || entry == nil || entry.Thread.TS == "" {
+		return nil, err
+	}
+	return &entry.Thread, nil
+}
+
+// read returns the entry of the file. It returns nil and removes the file if the entry is expired.
+func (x *File) read(path string) (*fileEntry, error) {
+	raw, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, goerr.Wrap(err, "failed to read thread", goerr.V("path", path))
+	}
+
+	var entry fileEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, goerr.Wrap(err, "failed to unmarshal thread", goerr.V("path", path))
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, goerr.Wrap(err, "failed to remove expired thread", goerr.V("path", path))
+		}
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+func (x *File) Put(ctx context.Context, channel, key string, thread *model.SlackThread) error {
+	// Expired threads are removed here to bound disk usage
+	if err := x.sweep(); err != nil {
+		return err
+	}
+
+	path := x.path(channel, key)
+	tmp, err := x.writeTemp(path, fileEntry{
+		Thread:    *thread,
+		ExpiresAt: time.Now().Add(x.ttl),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return goerr.Wrap(err, "failed to rename thread file", goerr.V("path", path))
+	}
+
+	return nil
+}
+
+func (x *File) Claim(ctx context.Context, channel, key string, ttl time.Duration) (bool, error) {
+	path := x.path(channel, key)
+
+	entry, err := x.read(path)
+	if err != nil {
+		return false, err
+	}
+	if entry != nil {
+		return false, nil
+	}
+
+	tmp, err := x.writeTemp(path, fileEntry{ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	// Link fails if the file exists, so only one process can create the claim with complete content
+	if err := os.Link(tmp, path); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return false, nil
+		}
+		return false, goerr.Wrap(err, "failed to create thread claim", goerr.V("path", path))
+	}
+	return true, nil
+}
+
+func (x *File) Release(ctx context.Context, channel, key string) error {
+	path := x.path(channel, key)
+
+	entry, err := x.read(path)
+	if err != nil || entry == nil || entry.Thread.TS != "" {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return goerr.Wrap(err, "failed to remove thread claim", goerr.V("path", path))
+	}
+	return nil
+}
+
+// writeTemp writes the entry to a temporary file next to path and returns its path. The name is unique so that processes sharing the directory do not overwrite each other.
+func (x *File) writeTemp(path string, entry fileEntry) (string, error) {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to marshal thread")
+	}
+
+	f, err := os.CreateTemp(x.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to create thread file", goerr.V("dir", x.dir))
+	}
+	if _, err := f.Write(raw); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", goerr.Wrap(err, "failed to write thread", goerr.V("path", f.Name()))
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", goerr.Wrap(err, "failed to close thread file", goerr.V("path", f.Name()))
+	}
+	return f.Name(), nil
+}
+
+// sweep removes expired threads and claims in the directory.
+func (x *File) sweep() error {
+	paths, err := filepath.Glob(filepath.Join(x.dir, "*.json"))
+	if err != nil {
+		return goerr.Wrap(err, "failed to list threads", goerr.V("dir", x.dir))
+	}
+	for _, path := range paths {
+		if _, err := x.read(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
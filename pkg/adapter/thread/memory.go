@@ -0,0 +1,96 @@
+package thread
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// Memory is in-memory thread store. Threads are lost when the process exits.
+type Memory struct {
+	ttl time.Duration
+
+	mutex   sync.Mutex
+	threads map[memoryKey]memoryEntry
+}
+
+type memoryKey struct {
+	channel string
+	key     string
+}
+
+// memoryEntry is a saved thread, or a claim if thread is nil.
+type memoryEntry struct {
+	thread    *model.SlackThread
+	expiresAt time.Time
+}
+
+var _ interfaces.SlackThreadStore = (*Memory)(nil)
+
+// NewMemory creates in-memory thread store. Thread expires after ttl since it's saved.
+func NewMemory(ttl time.Duration) *Memory {
+	return &Memory{
+		ttl:     ttl,
+		threads: map[memoryKey]memoryEntry{},
+	}
+}
+
+func (x *Memory) Get(ctx context.Context, channel, key string) (*model.SlackThread, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	entry, ok := x.threads[memoryKey{channel, key}]
+	if !ok || entry.thread == nil || time.Now().After(entry.expiresAt) {
+		return nil, nil
+	}
+	thread := *entry.thread
+	return &thread, nil
+}
+
+func (x *Memory) Put(ctx context.Context, channel, key string, thread *model.SlackThread) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	// Expired threads are removed here to bound memory usage
+	now := time.Now()
+	for k, entry := range x.threads {
+		if now.After(entry.expiresAt) {
+			delete(x.threads, k)
+		}
+	}
+
+	saved := *thread
+	x.threads[memoryKey{channel, key}] = memoryEntry{
+		thread:    &saved,
+		expiresAt: now.Add(x.ttl),
+	}
+	return nil
+}
+
+func (x *Memory) Claim(ctx context.Context, channel, key string, ttl time.Duration) (bool, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	now := time.Now()
+	k := memoryKey{channel, key}
+	if entry, ok := x.threads[k]; ok && !now.After(entry.expiresAt) {
+		return false, nil
+	}
+
+	x.threads[k] = memoryEntry{expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (x *Memory) Release(ctx context.Context, channel, key string) error {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	k := memoryKey{channel, key}
+	if entry, ok := x.threads[k]; ok && entry.thread == nil {
+		delete(x.threads, k)
+	}
+	return nil
+}
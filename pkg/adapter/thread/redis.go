@@ -0,0 +1,274 @@
+package thread
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+const (
+	redisKeyPrefix = "xroute:slack-thread:"
+	redisTimeout   = 5 * time.Second
+)
+
+// Redis is thread store backed by Redis or compatible server, e.g. Valkey and Memorystore. It speaks RESP with a single connection, and reconnects when the connection is broken.
+type Redis struct {
+	addr      string
+	username  string
+	password  string
+	db        int
+	tlsConfig *tls.Config
+	ttl       time.Duration
+
+	mutex  sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+var _ interfaces.SlackThreadStore = (*Redis)(nil)
+
+// NewRedis creates thread store with URL as redis://[[username]:password@]host[:port][/db]. rediss:// enables TLS. Thread expires after ttl since it's saved.
+func NewRedis(rawURL string, ttl time.Duration) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, goerr.New("invalid Redis URL")
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, goerr.New("scheme of Redis URL must be redis or rediss", goerr.V("url", u.Redacted()))
+	}
+	if u.Hostname() == "" {
+		return nil, goerr.New("host of Redis URL is required", goerr.V("url", u.Redacted()))
+	}
+
+	client := &Redis{
+		addr: u.Host,
+		ttl:  ttl,
+	}
+	if u.Port() == "" {
+		client.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		client.username = u.User.Username()
+		client.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil || n < 0 {
+			return nil, goerr.New("DB number of Redis URL must be integer", goerr.V("url", u.Redacted()))
+		}
+		client.db = n
+	}
+	if u.Scheme == "rediss" {
+		client.tlsConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	}
+
+	return client, nil
+}
+
+// redisKey joins channel and key by NUL so that e.g. ("a:b", "c") and ("a", "b:c") do not collide.
+func redisKey(channel, key string) string {
+	return redisKeyPrefix + channel + "\x00" + key
+}
+
+func (x *Redis) Get(ctx context.Context, channel, key string) (*model.SlackThread, error) {
+	reply, err := x.do(ctx, "GET", redisKey(channel, key))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to get thread from Redis", goerr.V("channel", channel), goerr.V("key", key))
+	}
+	if reply == nil {
+		return nil, nil
+	}
+
+	raw, ok := reply.(string)
+	if !ok {
+		return nil, goerr.New("unexpected reply of Redis GET", goerr.V("reply", reply))
+	}
+
+	var thread model.SlackThread
+	if err := json.Unmarshal([]byte(raw), &thread); err != nil {
+		return nil, goerr.Wrap(err, "failed to unmarshal thread", goerr.V("channel", channel), goerr.V("key", key))
+	}
+	// Thread without timestamp is a claim
+	if thread.TS == "" {
+		return nil, nil
+	}
+	return &thread, nil
+}
+
+func (x *Redis) Put(ctx context.Context, channel, key string, thread *model.SlackThread) error {
+	raw, err := json.Marshal(thread)
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal thread")
+	}
+
+	ttl := strconv.FormatInt(max(int64(x.ttl/time.Second), 1), 10)
+	if _, err := x.do(ctx, "SET", redisKey(channel, key), string(raw), "EX", ttl); err != nil {
+		return goerr.Wrap(err, "failed to put thread to Redis", goerr.V("channel", channel), goerr.V("key", key))
+	}
+	return nil
+}
+
+func (x *Redis) Claim(ctx context.Context, channel, key string, ttl time.Duration) (bool, error) {
+	sec := strconv.FormatInt(max(int64(ttl/time.Second), 1), 10)
+	reply, err := x.do(ctx, "SET", redisKey(channel, key), "{}", "NX", "EX", sec)
+	if err != nil {
+		return false, goerr.Wrap(err, "failed to claim thread in Redis", goerr.V("channel", channel), goerr.V("key", key))
+	}
+	// SET with NX replies nil if the key exists
+	return reply != nil, nil
+}
+
+// Release deletes the key if it's still a claim. GET and DEL are not atomic, but only the process holding the claim writes the key until the claim expires.
+func (x *Redis) Release(ctx context.Context, channel, key string) error {
+	reply, err := x.do(ctx, "GET", redisKey(channel, key))
+	if err != nil {
+		return goerr.Wrap(err, "failed to get thread from Redis", goerr.V("channel", channel), goerr.V("key", key))
+	}
+	if reply != "{}" {
+		return nil
+	}
+
+	if _, err := x.do(ctx, "DEL", redisKey(channel, key)); err != nil {
+		return goerr.Wrap(err, "failed to release thread in Redis", goerr.V("channel", channel), goerr.V("key", key))
+	}
+	return nil
+}
+
+// do sends a command and returns its reply. Reply is string, int64 or nil. The connection is closed on any error because the stream can not be trusted anymore.
+func (x *Redis) do(ctx context.Context, args ...string) (any, error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	if x.conn == nil {
+		if err := x.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := x.roundTrip(ctx, args...)
+	if err != nil {
+		var redisErr *RedisError
+		if !errors.As(err, &redisErr) {
+			x.close()
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (x *Redis) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", x.addr)
+	if err != nil {
+		return goerr.Wrap(err, "failed to connect Redis", goerr.V("addr", x.addr))
+	}
+	if x.tlsConfig != nil {
+		conn = tls.Client(conn, x.tlsConfig)
+	}
+	x.conn, x.reader = conn, bufio.NewReader(conn)
+
+	var setup [][]string
+	if x.password != "" {
+		if x.username != "" {
+			setup = append(setup, []string{"AUTH", x.username, x.password})
+		} else {
+			setup = append(setup, []string{"AUTH", x.password})
+		}
+	}
+	if x.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(x.db)})
+	}
+
+	for _, args := range setup {
+		if _, err := x.roundTrip(ctx, args...); err != nil {
+			x.close()
+			return goerr.Wrap(err, "failed to set up Redis connection", goerr.V("addr", x.addr), goerr.V("command", args[0]))
+		}
+	}
+	return nil
+}
+
+func (x *Redis) close() {
+	if x.conn != nil {
+		_ = x.conn.Close()
+	}
+	x.conn, x.reader = nil, nil
+}
+
+func (x *Redis) roundTrip(ctx context.Context, args ...string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := x.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		b.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := io.WriteString(x.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	return readRedisReply(x.reader)
+}
+
+// RedisError is error reply of Redis server.
+type RedisError struct {
+	Message string
+}
+
+func (x *RedisError) Error() string {
+	return "redis: " + x.Message
+}
+
+func readRedisReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, goerr.New("empty reply of Redis")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, &RedisError{Message: line[1:]}
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, goerr.Wrap(err, "invalid bulk string size of Redis reply", goerr.V("line", line))
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	default:
+		return nil, goerr.New("unsupported reply type of Redis", goerr.V("line", line))
+	}
+}
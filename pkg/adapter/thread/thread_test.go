@@ -0,0 +1,265 @@
+package thread_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/adapter/thread"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+func testStore(t *testing.T, store interfaces.SlackThreadStore) {
+	ctx := context.Background()
+
+	gt.V(t, gt.R1(store.Get(ctx, "#alerts", "ci/123")).NoError(t)).Nil()
+
+	gt.NoError(t, store.Put(ctx, "#alerts", "ci/123", &model.SlackThread{Channel: "C01", TS: "1700000000.000100"}))
+	got := gt.R1(store.Get(ctx, "#alerts", "ci/123")).NoError(t)
+	gt.V(t, got).NotNil()
+	gt.Equal(t, got.Channel, "C01")
+	gt.Equal(t, got.TS, "1700000000.000100")
+
+	// Same key in other channel is independent
+	gt.V(t, gt.R1(store.Get(ctx, "#other", "ci/123")).NoError(t)).Nil()
+
+	// Separator in channel or key does not make pairs collide
+	gt.NoError(t, store.Put(ctx, "#a:b", "c", &model.SlackThread{Channel: "C02", TS: "1"}))
+	gt.V(t, gt.R1(store.Get(ctx, "#a", "b:c")).NoError(t)).Nil()
+
+	gt.NoError(t, store.Put(ctx, "#alerts", "ci/123", &model.SlackThread{Channel: "C01", TS: "1700000000.000200"}))
+	got = gt.R1(store.Get(ctx, "#alerts", "ci/123")).NoError(t)
+	gt.Equal(t, got.TS, "1700000000.000200")
+}
+
+func testExpire(t *testing.T, store interfaces.SlackThreadStore) {
+	ctx := context.Background()
+	gt.NoError(t, store.Put(ctx, "#alerts", "ci/123", &model.SlackThread{Channel: "C01", TS: "1"}))
+	time.Sleep(20 * time.Millisecond)
+	gt.V(t, gt.R1(store.Get(ctx, "#alerts", "ci/123")).NoError(t)).Nil()
+}
+
+func testClaim(t *testing.T, store interfaces.SlackThreadStore) {
+	ctx := context.Background()
+
+	gt.True(t, gt.R1(store.Claim(ctx, "#alerts", "ci/789", time.Minute)).NoError(t))
+	gt.False(t, gt.R1(store.Claim(ctx, "#alerts", "ci/789", time.Minute)).NoError(t))
+	// Claim is not a thread
+	gt.V(t, gt.R1(store.Get(ctx, "#alerts", "ci/789")).NoError(t)).Nil()
+
+	// Released key can be claimed again
+	gt.NoError(t, store.Release(ctx, "#alerts", "ci/789"))
+	gt.True(t, gt.R1(store.Claim(ctx, "#alerts", "ci/789", time.Minute)).NoError(t))
+
+	// Saved thread replaces the claim and is not claimed again
+	gt.NoError(t, store.Put(ctx, "#alerts", "ci/789", &model.SlackThread{Channel: "C01", TS: "1"}))
+	gt.False(t, gt.R1(store.Claim(ctx, "#alerts", "ci/789", time.Minute)).NoError(t))
+	gt.NoError(t, store.Release(ctx, "#alerts", "ci/789"))
+	gt.Equal(t, gt.R1(store.Get(ctx, "#alerts", "ci/789")).NoError(t).TS, "1")
+}
+
+func testClaimConcurrently(t *testing.T, store interfaces.SlackThreadStore) {
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var claimed int
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := store.Claim(ctx, "#alerts", "ci/456", time.Minute)
+			gt.NoError(t, err)
+			if ok {
+				mutex.Lock()
+				claimed++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	gt.Equal(t, claimed, 1)
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, thread.NewMemory(time.Hour))
+	testExpire(t, thread.NewMemory(time.Millisecond))
+	testClaim(t, thread.NewMemory(time.Hour))
+	testClaimConcurrently(t, thread.NewMemory(time.Hour))
+}
+
+func TestFile(t *testing.T) {
+	testStore(t, gt.R1(thread.NewFile(t.TempDir(), time.Hour)).NoError(t))
+	testExpire(t, gt.R1(thread.NewFile(t.TempDir(), time.Millisecond)).NoError(t))
+	testClaim(t, gt.R1(thread.NewFile(t.TempDir(), time.Hour)).NoError(t))
+	testClaimConcurrently(t, gt.R1(thread.NewFile(t.TempDir(), time.Hour)).NoError(t))
+}
+
+func TestFileSweep(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := gt.R1(thread.NewFile(dir, 10*time.Millisecond)).NoError(t)
+
+	gt.NoError(t, store.Put(ctx, "#alerts", "ci/1", &model.SlackThread{Channel: "C01", TS: "1"}))
+	gt.True(t, gt.R1(store.Claim(ctx, "#alerts", "ci/2", 10*time.Millisecond)).NoError(t))
+	time.Sleep(20 * time.Millisecond)
+
+	// Expired thread and claim are removed without Get
+	gt.NoError(t, store.Put(ctx, "#alerts", "ci/3", &model.SlackThread{Channel: "C01", TS: "3"}))
+	entries := gt.R1(os.ReadDir(dir)).NoError(t)
+	gt.A(t, entries).Length(1)
+}
+
+// fakeRedis is minimal RESP server supporting AUTH, SELECT, GET, SET and DEL.
+type fakeRedis struct {
+	password string
+
+	mutex    sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	listener := gt.R1(net.Listen("tcp", "127.0.0.1:0")).NoError(t)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	srv := &fakeRedis{password: password, data: map[string]string{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go srv.handle(conn)
+		}
+	}()
+
+	return srv, listener.Addr().String()
+}
+
+func (x *fakeRedis) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	authed := x.password == ""
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		x.mutex.Lock()
+		x.commands = append(x.commands, strings.Join(args, " "))
+		x.mutex.Unlock()
+
+		cmd := strings.ToUpper(args[0])
+		if !authed && cmd != "AUTH" {
+			_, _ = io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+
+		switch cmd {
+		case "AUTH":
+			if args[len(args)-1] != x.password {
+				_, _ = io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			authed = true
+			_, _ = io.WriteString(conn, "+OK\r\n")
+		case "SELECT":
+			_, _ = io.WriteString(conn, "+OK\r\n")
+		case "SET":
+			x.mutex.Lock()
+			_, exists := x.data[args[1]]
+			if exists && slices.Contains(args[3:], "NX") {
+				x.mutex.Unlock()
+				_, _ = io.WriteString(conn, "$-1\r\n")
+				continue
+			}
+			x.data[args[1]] = args[2]
+			x.mutex.Unlock()
+			_, _ = io.WriteString(conn, "+OK\r\n")
+		case "DEL":
+			x.mutex.Lock()
+			delete(x.data, args[1])
+			x.mutex.Unlock()
+			_, _ = io.WriteString(conn, ":1\r\n")
+		case "GET":
+			x.mutex.Lock()
+			v, ok := x.data[args[1]]
+			x.mutex.Unlock()
+			if !ok {
+				_, _ = io.WriteString(conn, "$-1\r\n")
+				continue
+			}
+			_, _ = io.WriteString(conn, "$"+strconv.Itoa(len(v))+"\r\n"+v+"\r\n")
+		default:
+			_, _ = io.WriteString(conn, "-ERR unknown command\r\n")
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	srv, addr := newFakeRedis(t, "secret")
+
+	store := gt.R1(thread.NewRedis("redis://:secret@"+addr+"/2", 24*time.Hour)).NoError(t)
+	testStore(t, store)
+	testClaim(t, store)
+
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	gt.Equal(t, srv.commands[0], "AUTH secret")
+	gt.Equal(t, srv.commands[1], "SELECT 2")
+	gt.A(t, srv.commands).Any(func(v string) bool {
+		return strings.HasPrefix(v, "SET xroute:slack-thread:#alerts\x00ci/123 ") && strings.HasSuffix(v, " EX 86400")
+	})
+	gt.A(t, srv.commands).Any(func(v string) bool {
+		return v == "SET xroute:slack-thread:#alerts\x00ci/789 {} NX EX 60"
+	})
+}
+
+func TestRedisError(t *testing.T) {
+	_, addr := newFakeRedis(t, "secret")
+
+	store := gt.R1(thread.NewRedis("redis://:wrong@"+addr, time.Hour)).NoError(t)
+	_, err := store.Get(context.Background(), "#alerts", "ci/123")
+	gt.Error(t, err)
+
+	for _, u := range []string{"http://localhost", "redis://", "redis://localhost/db"} {
+		gt.R1(thread.NewRedis(u, time.Hour)).Error(t)
+	}
+}
@@ -13,6 +13,9 @@ func Run(ctx context.Context, args []string) error {
 		Usage: "Manipulate and transmit Webhook messages by Rego policies",
 		Commands: []*cli.Command{
 			cmdServe(),
+			cmdReplay(),
+			cmdTest(),
+			cmdEval(),
 		},
 	}
 
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"log/slog"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter/deadletter"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/urfave/cli/v3"
+)
+
+type DeadLetter struct {
+	dir string
+}
+
+func (x *DeadLetter) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "dead-letter-dir",
+			Usage:       "Directory to store given up deliveries. If empty, they are kept in memory and can be replayed only by admin API, not by replay command",
+			Sources:     cli.EnvVars("XROUTE_DEAD_LETTER_DIR"),
+			Destination: &x.dir,
+		},
+	}
+}
+
+func (x DeadLetter) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("dir", x.dir))
+}
+
+// InMemory returns true if given up deliveries are kept in memory because the directory is not set.
+func (x DeadLetter) InMemory() bool {
+	return x.dir == ""
+}
+
+// New creates dead-letter store. If the directory is not set, in-memory store is created.
+func (x DeadLetter) New() (interfaces.DeadLetterStore, error) {
+	if x.dir == "" {
+		return deadletter.NewMemory(), nil
+	}
+
+	store, err := deadletter.NewFile(x.dir)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create dead-letter store")
+	}
+	return store, nil
+}
+
+// NewFile creates dead-letter store backed by the directory. It returns error if the directory is not set.
+func (x DeadLetter) NewFile() (interfaces.DeadLetterStore, error) {
+	if x.dir == "" {
+		return nil, goerr.New("dead-letter-dir is not set")
+	}
+	return x.New()
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"log/slog"
+
+	"github.com/m-mizutani/xroute/pkg/adapter/discord"
+	"github.com/urfave/cli/v3"
+)
+
+type Discord struct {
+	webhooks []string
+}
+
+func (x *Discord) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "discord-webhook",
+			Usage:       "Discord webhook URL as NAME=URL. NAME is used as channel in policy. If empty, Discord integration is disabled",
+			Sources:     cli.EnvVars("XROUTE_DISCORD_WEBHOOK"),
+			Destination: &x.webhooks,
+		},
+	}
+}
+
+func (x Discord) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("channels", namedValueNames(x.webhooks)))
+}
+
+// New creates Discord client. It returns nil if no webhook is configured.
+func (x Discord) New() (*discord.Client, error) {
+	if len(x.webhooks) == 0 {
+		return nil, nil
+	}
+
+	webhooks, err := parseNamedURLs("discord-webhook", x.webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return discord.New(webhooks), nil
+}
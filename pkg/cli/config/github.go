@@ -0,0 +1,95 @@
+package config
+
+import (
+	"log/slog"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter/github"
+	"github.com/urfave/cli/v3"
+)
+
+type GitHub struct {
+	token             string
+	appID             int64
+	appInstallationID int64
+	appPrivateKey     string
+	apiURL            string
+}
+
+func (x *GitHub) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "github-token",
+			Usage:       "Personal access token to create GitHub issue and comment. Exclusive with GitHub App",
+			Sources:     cli.EnvVars("XROUTE_GITHUB_TOKEN"),
+			Destination: &x.token,
+		},
+		&cli.IntFlag{
+			Name:        "github-app-id",
+			Usage:       "GitHub App ID to create GitHub issue and comment",
+			Sources:     cli.EnvVars("XROUTE_GITHUB_APP_ID"),
+			Destination: &x.appID,
+		},
+		&cli.IntFlag{
+			Name:        "github-app-installation-id",
+			Usage:       "Installation ID of GitHub App",
+			Sources:     cli.EnvVars("XROUTE_GITHUB_APP_INSTALLATION_ID"),
+			Destination: &x.appInstallationID,
+		},
+		&cli.StringFlag{
+			Name:        "github-app-private-key",
+			Usage:       "PEM encoded private key of GitHub App",
+			Sources:     cli.EnvVars("XROUTE_GITHUB_APP_PRIVATE_KEY"),
+			Destination: &x.appPrivateKey,
+		},
+		&cli.StringFlag{
+			Name:        "github-api-url",
+			Usage:       "Base URL of GitHub REST API, e.g. https://github.example.com/api/v3/ for GitHub Enterprise Server",
+			Sources:     cli.EnvVars("XROUTE_GITHUB_API_URL"),
+			Destination: &x.apiURL,
+		},
+	}
+}
+
+func (x GitHub) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("len(token)", len(x.token)),
+		slog.Int64("app_id", x.appID),
+		slog.Int64("app_installation_id", x.appInstallationID),
+		slog.Int("len(app_private_key)", len(x.appPrivateKey)),
+		slog.String("api_url", x.apiURL),
+	)
+}
+
+// New creates GitHub client for issue destination. It returns nil if neither token nor GitHub App is configured.
+func (x GitHub) New() (*github.Client, error) {
+	var options []github.Option
+	if x.apiURL != "" {
+		options = append(options, github.WithBaseURL(x.apiURL))
+	}
+
+	switch {
+	case x.token != "" && x.appID != 0:
+		return nil, goerr.New("github-token and github-app-id can not be set at the same time")
+
+	case x.token != "":
+		client, err := github.NewWithToken(x.token, options...)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create GitHub client")
+		}
+		return client, nil
+
+	case x.appID != 0:
+		if x.appInstallationID == 0 || x.appPrivateKey == "" {
+			return nil, goerr.New("github-app-installation-id and github-app-private-key are required for GitHub App", goerr.V("app_id", x.appID))
+		}
+		client, err := github.NewWithApp(x.appID, x.appInstallationID, []byte(x.appPrivateKey), options...)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create GitHub client")
+		}
+		return client, nil
+
+	default:
+		return nil, nil
+	}
+}
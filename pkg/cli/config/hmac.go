@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/urfave/cli/v3"
+)
+
+type HMAC struct {
+	path string
+}
+
+// hmacProfileFile is a profile in the file. Secret can be given by environment variable to keep it out of the file.
+type hmacProfileFile struct {
+	Secret          string `json:"secret"`
+	SecretEnv       string `json:"secret_env"`
+	Header          string `json:"header"`
+	SignatureKey    string `json:"signature_key"`
+	Prefix          string `json:"prefix"`
+	Algorithm       string `json:"algorithm"`
+	Encoding        string `json:"encoding"`
+	Template        string `json:"template"`
+	TimestampHeader string `json:"timestamp_header"`
+	TimestampKey    string `json:"timestamp_key"`
+	MaxSkew         string `json:"max_skew"`
+}
+
+func (x *HMAC) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "hmac-profiles",
+			Usage:       "JSON file of HMAC signature schemes by profile name for /msg/hmac/{profile}/{schema}",
+			Sources:     cli.EnvVars("XROUTE_HMAC_PROFILES"),
+			Destination: &x.path,
+		},
+	}
+}
+
+func (x HMAC) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("profiles", x.path))
+}
+
+// New loads HMAC profiles from the file. It returns nil if the file is not configured.
+func (x HMAC) New() (map[string]model.HMACProfile, error) {
+	if x.path == "" {
+		return nil, nil
+	}
+
+	raw, err := os.ReadFile(filepath.Clean(x.path))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read hmac-profiles", goerr.V("path", x.path))
+	}
+	var files map[string]hmacProfileFile
+	if err := json.Unmarshal(raw, &files); err != nil {
+		return nil, goerr.Wrap(err, "failed to parse hmac-profiles", goerr.V("path", x.path))
+	}
+
+	profiles := make(map[string]model.HMACProfile, len(files))
+	for name, file := range files {
+		profile := model.HMACProfile{
+			Secret:          file.Secret,
+			Header:          file.Header,
+			SignatureKey:    file.SignatureKey,
+			Prefix:          file.Prefix,
+			Algorithm:       strings.ToLower(file.Algorithm),
+			Encoding:        strings.ToLower(file.Encoding),
+			Template:        file.Template,
+			TimestampHeader: file.TimestampHeader,
+			TimestampKey:    file.TimestampKey,
+		}
+		if file.SecretEnv != "" {
+			profile.Secret = os.Getenv(file.SecretEnv)
+		}
+		if file.MaxSkew != "" {
+			d, err := time.ParseDuration(file.MaxSkew)
+			if err != nil {
+				return nil, goerr.Wrap(err, "invalid max_skew of HMAC profile", goerr.V("profile", name))
+			}
+			profile.MaxSkew = d
+		}
+		if err := profile.Validate(); err != nil {
+			return nil, goerr.Wrap(err, "invalid HMAC profile", goerr.V("profile", name))
+		}
+
+		profiles[name] = profile
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// namedValueNames returns sorted names of NAME=VALUE flag values for logging. Values are not returned because they work as credentials, e.g. webhook URLs and routing keys.
+func namedValueNames(values []string) []string {
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		name, _, _ := strings.Cut(v, "=")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseNamedValues parses NAME=VALUE flag values into map. flag and format, e.g. "teams-webhook" and "NAME=URL", are used in error message.
+func parseNamedValues(flag, format string, values []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" || value == "" {
+			return nil, goerr.New(flag+" must be "+format, goerr.V("name", name))
+		}
+		if _, ok := parsed[name]; ok {
+			return nil, goerr.New(flag+" name is duplicated", goerr.V("name", name))
+		}
+		parsed[name] = value
+	}
+	return parsed, nil
+}
+
+// parseNamedURLs parses NAME=URL flag values into map. URL must be https because it's used to post messages with credential in it.
+func parseNamedURLs(flag string, values []string) (map[string]string, error) {
+	parsed, err := parseNamedValues(flag, "NAME=URL", values)
+	if err != nil {
+		return nil, err
+	}
+	for name, rawURL := range parsed {
+		if u, err := url.Parse(rawURL); err != nil || u.Scheme != "https" {
+			return nil, goerr.New("URL of "+flag+" must be https", goerr.V("name", name))
+		}
+	}
+	return parsed, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseNamedValues(t *testing.T) {
+	tests := []struct {
+		values []string
+		valid  bool
+	}{
+		{[]string{"a=1", "b=2=3"}, true},
+		{[]string{"a"}, false},
+		{[]string{"=1"}, false},
+		{[]string{"a="}, false},
+		{[]string{"a=1", "a=2"}, false},
+	}
+
+	for _, tt := range tests {
+		parsed, err := parseNamedValues("test-flag", "NAME=VALUE", tt.values)
+		if tt.valid && err != nil {
+			t.Errorf("expected no error for %v, got %v", tt.values, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("expected error for %v, got none", tt.values)
+		}
+		if tt.valid && parsed["b"] != "2=3" {
+			t.Errorf("expected '2=3', got '%s'", parsed["b"])
+		}
+	}
+}
+
+func TestParseNamedURLs(t *testing.T) {
+	if _, err := parseNamedURLs("test-webhook", []string{"a=https://example.com/hook"}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := parseNamedURLs("test-webhook", []string{"a=http://example.com/hook"}); err == nil {
+		t.Error("expected error for http URL, got none")
+	}
+}
+
+func TestNamedValueNames(t *testing.T) {
+	names := namedValueNames([]string{"b=secret", "a=secret"})
+	if !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", names)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"log/slog"
+	"net/url"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter/pagerduty"
+	"github.com/urfave/cli/v3"
+)
+
+type PagerDuty struct {
+	routingKeys []string
+	baseURL     string
+}
+
+func (x *PagerDuty) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "pagerduty-routing-key",
+			Usage:       "PagerDuty integration key as NAME=KEY. NAME is used as routing_key in policy. If empty, PagerDuty integration is disabled",
+			Sources:     cli.EnvVars("XROUTE_PAGERDUTY_ROUTING_KEY"),
+			Destination: &x.routingKeys,
+		},
+		&cli.StringFlag{
+			Name:        "pagerduty-url",
+			Usage:       "Base URL of PagerDuty Events API v2",
+			Value:       pagerduty.DefaultBaseURL,
+			Sources:     cli.EnvVars("XROUTE_PAGERDUTY_URL"),
+			Destination: &x.baseURL,
+		},
+	}
+}
+
+func (x PagerDuty) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("routing_keys", namedValueNames(x.routingKeys)),
+		slog.String("url", x.baseURL),
+	)
+}
+
+// New creates PagerDuty client. It returns nil if no routing key is configured.
+func (x PagerDuty) New() (*pagerduty.Client, error) {
+	if len(x.routingKeys) == 0 {
+		return nil, nil
+	}
+
+	if u, err := url.Parse(x.baseURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, goerr.New("pagerduty-url must be http(s) URL", goerr.V("url", x.baseURL))
+	}
+
+	routingKeys, err := parseNamedValues("pagerduty-routing-key", "NAME=KEY", x.routingKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagerduty.New(routingKeys, pagerduty.WithBaseURL(x.baseURL)), nil
+}
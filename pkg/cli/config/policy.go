@@ -1,15 +1,30 @@
 package config
 
 import (
+	"context"
 	"log/slog"
+	"os"
+	"time"
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter/bundle"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/usecase"
 	"github.com/urfave/cli/v3"
 )
 
 type Policy struct {
-	path string
+	path  string
+	watch bool
+
+	bundleURL        string
+	bundleToken      string
+	bundlePublicKey  string
+	bundleKeyID      string
+	bundleSigningAlg string
+	bundleCacheDir   string
+	bundleInterval   time.Duration
 }
 
 func (x *Policy) Flags() []cli.Flag {
@@ -21,17 +36,147 @@ func (x *Policy) Flags() []cli.Flag {
 			Sources:     cli.EnvVars("XROUTE_POLICY"),
 			Destination: &x.path,
 		},
+		&cli.BoolFlag{
+			Name:        "policy-watch",
+			Usage:       "Reload policy when files under the policy path are changed",
+			Sources:     cli.EnvVars("XROUTE_POLICY_WATCH"),
+			Destination: &x.watch,
+		},
+		&cli.StringFlag{
+			Name:        "policy-bundle-url",
+			Usage:       "URL of OPA bundle, http(s)://... or oci://<registry>/<repository>[:<tag>]. Exclusive with --policy",
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_URL"),
+			Destination: &x.bundleURL,
+		},
+		&cli.StringFlag{
+			Name:        "policy-bundle-token",
+			Usage:       "Bearer token for the bundle server or OCI registry",
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_TOKEN"),
+			Destination: &x.bundleToken,
+		},
+		&cli.StringFlag{
+			Name:        "policy-bundle-public-key",
+			Usage:       "Path to PEM file of public key to verify bundle signature. If set, unsigned bundle is rejected",
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_PUBLIC_KEY"),
+			Destination: &x.bundlePublicKey,
+		},
+		&cli.StringFlag{
+			Name:        "policy-bundle-key-id",
+			Usage:       "Key ID of bundle signature",
+			Value:       "default",
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_KEY_ID"),
+			Destination: &x.bundleKeyID,
+		},
+		&cli.StringFlag{
+			Name:        "policy-bundle-signing-alg",
+			Usage:       "Algorithm of bundle signature",
+			Value:       "RS256",
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_SIGNING_ALG"),
+			Destination: &x.bundleSigningAlg,
+		},
+		&cli.StringFlag{
+			Name:        "policy-bundle-cache-dir",
+			Usage:       "Directory to save the last good bundle. It's used when the bundle can not be downloaded at startup",
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_CACHE_DIR"),
+			Destination: &x.bundleCacheDir,
+		},
+		&cli.DurationFlag{
+			Name:        "policy-bundle-interval",
+			Usage:       "Interval to check update of the bundle",
+			Value:       time.Minute,
+			Sources:     cli.EnvVars("XROUTE_POLICY_BUNDLE_INTERVAL"),
+			Destination: &x.bundleInterval,
+		},
 	}
 }
 
 func (x Policy) LogValue() slog.Value {
-	return slog.GroupValue(slog.String("path", x.path))
+	return slog.GroupValue(
+		slog.String("path", x.path),
+		slog.Bool("watch", x.watch),
+		slog.String("bundle_url", x.bundleURL),
+		slog.Bool("bundle_token", x.bundleToken != ""),
+		slog.String("bundle_public_key", x.bundlePublicKey),
+		slog.String("bundle_cache_dir", x.bundleCacheDir),
+		slog.Duration("bundle_interval", x.bundleInterval),
+	)
+}
+
+// New loads policy from files or bundle.
+func (x Policy) New(ctx context.Context) (interfaces.Policy, error) {
+	client, err := x.NewBundle()
+	if err != nil {
+		return nil, err
+	}
+	return x.Load(ctx, client)
+}
+
+// Load loads the initial policy. If bundleClient is given, the bundle is fetched, or the cached bundle is used if it can not be fetched. Otherwise, the policy is loaded from files.
+func (x Policy) Load(ctx context.Context, bundleClient *bundle.Client) (interfaces.Policy, error) {
+	if bundleClient != nil {
+		return bundleClient.Load(ctx)
+	}
+	return x.Loader(nil)(ctx)
 }
 
-func (x Policy) New() (*opac.Client, error) {
-	if x.path == "" {
-		return nil, goerr.New("policy-path is not set")
+// NewBundle creates bundle client. It returns nil if bundle URL is not set.
+func (x Policy) NewBundle() (*bundle.Client, error) {
+	if x.bundleURL == "" {
+		return nil, nil
+	}
+	if x.path != "" {
+		return nil, goerr.New("policy and policy-bundle-url can not be set at the same time")
+	}
+
+	options := []bundle.Option{
+		bundle.WithToken(x.bundleToken),
+		bundle.WithCacheDir(x.bundleCacheDir),
 	}
+	if x.bundlePublicKey != "" {
+		key, err := os.ReadFile(x.bundlePublicKey)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to read bundle public key", goerr.V("path", x.bundlePublicKey))
+		}
+		options = append(options, bundle.WithPublicKey(x.bundleKeyID, x.bundleSigningAlg, string(key)))
+	}
+
+	client, err := bundle.New(x.bundleURL, options...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create bundle client")
+	}
+	return client, nil
+}
+
+// Loader returns function to reload policy. If bundleClient is given, the policy is built from the last bundle fetched by bundleClient.Fetch, so it does not download the bundle by itself. Otherwise, it's loaded from files.
+func (x Policy) Loader(bundleClient *bundle.Client) usecase.PolicyLoader {
+	if bundleClient != nil {
+		return bundleClient.Policy
+	}
+
+	return func(ctx context.Context) (interfaces.Policy, error) {
+		if x.path == "" {
+			return nil, goerr.New("policy or policy-bundle-url is not set")
+		}
+
+		client, err := opac.New(opac.Files(x.path))
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+}
+
+// Path returns path to policy files or directory.
+func (x Policy) Path() string {
+	return x.path
+}
+
+// Watch returns true if policy files should be reloaded on file change.
+func (x Policy) Watch() bool {
+	return x.watch && x.path != ""
+}
 
-	return opac.New(opac.Files(x.path))
+// BundleInterval returns interval to check update of the bundle.
+func (x Policy) BundleInterval() time.Duration {
+	return x.bundleInterval
 }
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/urfave/cli/v3"
+)
+
+type Queue struct {
+	backend     string
+	dir         string
+	maxAttempts int64
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+func (x *Queue) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "queue",
+			Usage:       "Delivery queue backend (memory, file, none). If none, messages are transmitted synchronously in HTTP request",
+			Value:       "memory",
+			Sources:     cli.EnvVars("XROUTE_QUEUE"),
+			Destination: &x.backend,
+		},
+		&cli.StringFlag{
+			Name:        "queue-dir",
+			Usage:       "Directory to store queued deliveries. Required if queue is file",
+			Sources:     cli.EnvVars("XROUTE_QUEUE_DIR"),
+			Destination: &x.dir,
+		},
+		&cli.IntFlag{
+			Name:        "delivery-max-attempts",
+			Usage:       "Maximum number of attempts to transmit a message",
+			Value:       5,
+			Sources:     cli.EnvVars("XROUTE_DELIVERY_MAX_ATTEMPTS"),
+			Destination: &x.maxAttempts,
+		},
+		&cli.DurationFlag{
+			Name:        "delivery-backoff",
+			Usage:       "Initial interval of exponential backoff between delivery attempts",
+			Value:       time.Second,
+			Sources:     cli.EnvVars("XROUTE_DELIVERY_BACKOFF"),
+			Destination: &x.backoff,
+		},
+		&cli.DurationFlag{
+			Name:        "delivery-max-backoff",
+			Usage:       "Maximum interval of exponential backoff between delivery attempts",
+			Value:       5 * time.Minute,
+			Sources:     cli.EnvVars("XROUTE_DELIVERY_MAX_BACKOFF"),
+			Destination: &x.maxBackoff,
+		},
+	}
+}
+
+func (x Queue) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("backend", x.backend),
+		slog.String("dir", x.dir),
+		slog.Int64("max_attempts", x.maxAttempts),
+		slog.Duration("backoff", x.backoff),
+		slog.Duration("max_backoff", x.maxBackoff),
+	)
+}
+
+// New creates delivery queue. It returns nil if queue is disabled.
+func (x Queue) New() (interfaces.Queue, error) {
+	switch x.backend {
+	case "memory":
+		return queue.NewMemory(), nil
+
+	case "file":
+		if x.dir == "" {
+			return nil, goerr.New("queue-dir is required for file queue")
+		}
+		q, err := queue.NewFile(x.dir)
+		if err != nil {
+			return nil, err
+		}
+		return q, nil
+
+	case "none":
+		return nil, nil
+
+	default:
+		return nil, goerr.New("Invalid queue backend", goerr.V("queue", x.backend))
+	}
+}
+
+// Options returns options of delivery for usecase.
+func (x Queue) Options() []usecase.Option {
+	return []usecase.Option{
+		usecase.WithMaxAttempts(int(x.maxAttempts)),
+		usecase.WithBackoff(x.backoff, x.maxBackoff),
+	}
+}
@@ -1,14 +1,30 @@
 package config
 
 import (
+	"encoding/json"
 	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter/thread"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/usecase"
 	"github.com/slack-go/slack"
 	"github.com/urfave/cli/v3"
 )
 
 type Slack struct {
 	token string
+
+	threadStore string
+	threadTTL   time.Duration
+
+	userMapping  string
+	userCacheTTL time.Duration
 }
 
 func (x *Slack) Flags() []cli.Flag {
@@ -21,11 +37,49 @@ func (x *Slack) Flags() []cli.Flag {
 			Sources:     cli.EnvVars("XROUTE_SLACK_OAUTH_TOKEN"),
 			Destination: &x.token,
 		},
+		&cli.StringFlag{
+			Name:        "slack-thread-store",
+			Usage:       "Store of Slack threads correlated by thread_key, memory, file://<dir> or redis(s)://[[user]:password@]host[:port][/db]",
+			Value:       "memory",
+			Sources:     cli.EnvVars("XROUTE_SLACK_THREAD_STORE"),
+			Destination: &x.threadStore,
+		},
+		&cli.DurationFlag{
+			Name:        "slack-thread-ttl",
+			Usage:       "Duration to keep Slack thread. A message with expired thread_key starts new thread",
+			Value:       7 * 24 * time.Hour,
+			Sources:     cli.EnvVars("XROUTE_SLACK_THREAD_TTL"),
+			Destination: &x.threadTTL,
+		},
+		&cli.StringFlag{
+			Name:        "slack-user-mapping",
+			Usage:       "JSON file of Slack user by GitHub login, e.g. {\"octocat\": \"U0123ABCD\"}. The value is Slack user ID or email. It's used to resolve mentions",
+			Sources:     cli.EnvVars("XROUTE_SLACK_USER_MAPPING"),
+			Destination: &x.userMapping,
+		},
+		&cli.DurationFlag{
+			Name:        "slack-user-cache-ttl",
+			Usage:       "Duration to cache Slack user looked up by email",
+			Value:       time.Hour,
+			Sources:     cli.EnvVars("XROUTE_SLACK_USER_CACHE_TTL"),
+			Destination: &x.userCacheTTL,
+		},
 	}
 }
 
 func (x Slack) LogValue() slog.Value {
-	return slog.GroupValue(slog.Int("len(token)", len(x.token)))
+	store := x.threadStore
+	if u, err := url.Parse(store); err == nil {
+		store = u.Redacted()
+	}
+
+	return slog.GroupValue(
+		slog.Int("len(token)", len(x.token)),
+		slog.String("thread_store", store),
+		slog.Duration("thread_ttl", x.threadTTL),
+		slog.String("user_mapping", x.userMapping),
+		slog.Duration("user_cache_ttl", x.userCacheTTL),
+	)
 }
 
 func (x Slack) New() *slack.Client {
@@ -34,3 +88,49 @@ func (x Slack) New() *slack.Client {
 	}
 	return slack.New(x.token)
 }
+
+// NewThreadStore creates store of Slack threads.
+func (x Slack) NewThreadStore() (interfaces.SlackThreadStore, error) {
+	switch {
+	case x.threadStore == "" || x.threadStore == "memory":
+		return thread.NewMemory(x.threadTTL), nil
+
+	case strings.HasPrefix(x.threadStore, "file://"):
+		store, err := thread.NewFile(strings.TrimPrefix(x.threadStore, "file://"), x.threadTTL)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create slack thread store")
+		}
+		return store, nil
+
+	case strings.HasPrefix(x.threadStore, "redis://"), strings.HasPrefix(x.threadStore, "rediss://"):
+		store, err := thread.NewRedis(x.threadStore, x.threadTTL)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to create slack thread store")
+		}
+		return store, nil
+
+	default:
+		return nil, goerr.New("unsupported slack-thread-store, memory, file:// or redis:// is available")
+	}
+}
+
+// Options returns usecase options to resolve Slack users.
+func (x Slack) Options() ([]usecase.Option, error) {
+	options := []usecase.Option{
+		usecase.WithSlackUserCacheTTL(x.userCacheTTL),
+	}
+
+	if x.userMapping != "" {
+		raw, err := os.ReadFile(filepath.Clean(x.userMapping))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to read slack-user-mapping", goerr.V("path", x.userMapping))
+		}
+		var mapping map[string]string
+		if err := json.Unmarshal(raw, &mapping); err != nil {
+			return nil, goerr.Wrap(err, "slack-user-mapping must be JSON object of string", goerr.V("path", x.userMapping))
+		}
+		options = append(options, usecase.WithSlackUserMapping(mapping))
+	}
+
+	return options, nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter/smtp"
+	"github.com/urfave/cli/v3"
+)
+
+type SMTP struct {
+	addr     string
+	from     string
+	username string
+	password string
+	tlsMode  string
+	timeout  time.Duration
+}
+
+func (x *SMTP) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "smtp-addr",
+			Usage:       "Address of SMTP relay as HOST:PORT. If empty, email integration is disabled",
+			Sources:     cli.EnvVars("XROUTE_SMTP_ADDR"),
+			Destination: &x.addr,
+		},
+		&cli.StringFlag{
+			Name:        "smtp-from",
+			Usage:       "Sender address of email, e.g. \"xroute <xroute@example.com>\"",
+			Sources:     cli.EnvVars("XROUTE_SMTP_FROM"),
+			Destination: &x.from,
+		},
+		&cli.StringFlag{
+			Name:        "smtp-username",
+			Usage:       "Username of SMTP AUTH. If empty, authentication is skipped",
+			Sources:     cli.EnvVars("XROUTE_SMTP_USERNAME"),
+			Destination: &x.username,
+		},
+		&cli.StringFlag{
+			Name:        "smtp-password",
+			Usage:       "Password of SMTP AUTH",
+			Sources:     cli.EnvVars("XROUTE_SMTP_PASSWORD"),
+			Destination: &x.password,
+		},
+		&cli.StringFlag{
+			Name:        "smtp-tls",
+			Usage:       "TLS mode of SMTP connection, starttls, tls (implicit TLS) or none",
+			Value:       string(smtp.TLSModeStartTLS),
+			Sources:     cli.EnvVars("XROUTE_SMTP_TLS"),
+			Destination: &x.tlsMode,
+		},
+		&cli.DurationFlag{
+			Name:        "smtp-timeout",
+			Usage:       "Timeout of a SMTP session",
+			Value:       30 * time.Second,
+			Sources:     cli.EnvVars("XROUTE_SMTP_TIMEOUT"),
+			Destination: &x.timeout,
+		},
+	}
+}
+
+func (x SMTP) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("addr", x.addr),
+		slog.String("from", x.from),
+		slog.String("username", x.username),
+		slog.Int("len(password)", len(x.password)),
+		slog.String("tls", x.tlsMode),
+		slog.Duration("timeout", x.timeout),
+	)
+}
+
+// New creates SMTP client. It returns nil if SMTP address is not configured.
+func (x SMTP) New() (*smtp.Client, error) {
+	if x.addr == "" {
+		return nil, nil
+	}
+	if x.from == "" {
+		return nil, goerr.New("smtp-from is required if smtp-addr is set")
+	}
+
+	options := []smtp.Option{
+		smtp.WithTLSMode(smtp.TLSMode(x.tlsMode)),
+		smtp.WithTimeout(x.timeout),
+	}
+	if x.username != "" {
+		options = append(options, smtp.WithAuth(x.username, x.password))
+	}
+
+	client, err := smtp.New(x.addr, x.from, options...)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create SMTP client")
+	}
+	return client, nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"log/slog"
+
+	"github.com/m-mizutani/xroute/pkg/adapter/teams"
+	"github.com/urfave/cli/v3"
+)
+
+type Teams struct {
+	webhooks []string
+}
+
+func (x *Teams) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "teams-webhook",
+			Usage:       "Microsoft Teams incoming webhook or Workflows URL as NAME=URL. NAME is used as channel in policy. If empty, Teams integration is disabled",
+			Sources:     cli.EnvVars("XROUTE_TEAMS_WEBHOOK"),
+			Destination: &x.webhooks,
+		},
+	}
+}
+
+func (x Teams) LogValue() slog.Value {
+	return slog.GroupValue(slog.Any("channels", namedValueNames(x.webhooks)))
+}
+
+// New creates Teams client. It returns nil if no webhook is configured.
+func (x Teams) New() (*teams.Client, error) {
+	if len(x.webhooks) == 0 {
+		return nil, nil
+	}
+
+	webhooks, err := parseNamedURLs("teams-webhook", x.webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return teams.New(webhooks), nil
+}
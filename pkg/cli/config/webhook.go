@@ -0,0 +1,91 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/urfave/cli/v3"
+)
+
+type Webhook struct {
+	credentials    []string
+	credentialURLs []string
+	timeout        time.Duration
+}
+
+func (x *Webhook) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "webhook-credential",
+			Usage:       "Credential of webhook destination as NAME=HEADER:VALUE, e.g. jira=Authorization:Bearer xxx. Policy refers it by NAME",
+			Sources:     cli.EnvVars("XROUTE_WEBHOOK_CREDENTIAL"),
+			Destination: &x.credentials,
+		},
+		&cli.StringSliceFlag{
+			Name:        "webhook-credential-url",
+			Usage:       "https URL that the credential is allowed to be sent to as NAME=URL, e.g. jira=https://example.atlassian.net/rest/. Required for each credential",
+			Sources:     cli.EnvVars("XROUTE_WEBHOOK_CREDENTIAL_URL"),
+			Destination: &x.credentialURLs,
+		},
+		&cli.DurationFlag{
+			Name:        "webhook-timeout",
+			Usage:       "Timeout of a webhook request",
+			Value:       10 * time.Second,
+			Sources:     cli.EnvVars("XROUTE_WEBHOOK_TIMEOUT"),
+			Destination: &x.timeout,
+		},
+	}
+}
+
+func (x Webhook) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("credentials", namedValueNames(x.credentials)),
+		slog.Any("credential_urls", x.credentialURLs),
+		slog.Duration("timeout", x.timeout),
+	)
+}
+
+// Options returns usecase options of webhook destination.
+func (x Webhook) Options() ([]usecase.Option, error) {
+	values, err := parseNamedValues("webhook-credential", "NAME=HEADER:VALUE", x.credentials)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := parseNamedURLs("webhook-credential-url", x.credentialURLs)
+	if err != nil {
+		return nil, err
+	}
+	for name := range urls {
+		if _, ok := values[name]; !ok {
+			return nil, goerr.New("webhook-credential-url is given for unknown credential", goerr.V("name", name))
+		}
+	}
+
+	credentials := make(map[string]model.WebhookCredential, len(values))
+	for name, cred := range values {
+		header, value, ok := strings.Cut(cred, ":")
+		if !ok || header == "" {
+			return nil, goerr.New("webhook-credential must be NAME=HEADER:VALUE", goerr.V("name", name))
+		}
+
+		rawURL, ok := urls[name]
+		if !ok {
+			return nil, goerr.New("webhook-credential-url is required for the credential", goerr.V("name", name))
+		}
+
+		credentials[name] = model.WebhookCredential{
+			Header: strings.TrimSpace(header),
+			Value:  strings.TrimSpace(value),
+			URL:    rawURL,
+		}
+	}
+
+	return []usecase.Option{
+		usecase.WithWebhookCredentials(credentials),
+		usecase.WithWebhookTimeout(x.timeout),
+	}, nil
+}
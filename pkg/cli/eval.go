@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/cli/config"
+	http_server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+	"github.com/urfave/cli/v3"
+)
+
+// evalSources maps message source to HTTP request of the corresponding handler. github.actions and aws.sns are not included because their handlers verify OIDC token and signing certificate over network, that can not be done with a payload file.
+var evalSources = map[string]func(schema string) httpCapture{
+	"raw": func(schema string) httpCapture {
+		return httpCapture{Path: "/msg/raw/" + schema}
+	},
+	"pubsub": func(schema string) httpCapture {
+		return httpCapture{Path: "/msg/pubsub/" + schema}
+	},
+	"github.webhook": func(schema string) httpCapture {
+		return httpCapture{
+			Path:   "/msg/github/webhook",
+			Header: map[string]string{"X-GitHub-Event": schema},
+		}
+	},
+	"alertmanager": func(schema string) httpCapture {
+		return httpCapture{Path: "/msg/alertmanager/" + schema}
+	},
+	"cloudevents": func(schema string) httpCapture {
+		return httpCapture{
+			Path:   "/msg/cloudevents",
+			Header: map[string]string{"Content-Type": "application/cloudevents+json"},
+		}
+	},
+	"gitlab.webhook": func(schema string) httpCapture {
+		return httpCapture{
+			Path:   "/msg/gitlab/webhook",
+			Header: map[string]string{"X-Gitlab-Event": schema},
+		}
+	},
+	"slack.events": func(schema string) httpCapture {
+		return httpCapture{Path: "/msg/slack/events"}
+	},
+	// Schema of hmac is {profile}/{schema}. Signature is verified by the profile, so it's valid only if the header of signature is given.
+	"hmac": func(schema string) httpCapture {
+		return httpCapture{Path: "/msg/hmac/" + schema}
+	},
+}
+
+func evalSourceNames() string {
+	var names []string
+	for name := range evalSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func cmdEval() *cli.Command {
+	var (
+		source  string
+		schema  string
+		input   string
+		headers []string
+
+		logger config.Logger
+		policy config.Policy
+		hmac   config.HMAC
+	)
+
+	flags := joinFlags([]cli.Flag{
+		&cli.StringFlag{
+			Name:        "source",
+			Aliases:     []string{"s"},
+			Usage:       "Message source (" + evalSourceNames() + ")",
+			Value:       "raw",
+			Destination: &source,
+		},
+		&cli.StringFlag{
+			Name:        "schema",
+			Usage:       "Message schema. It's event type for github.webhook and gitlab.webhook, and {profile}/{schema} for hmac",
+			Destination: &schema,
+		},
+		&cli.StringFlag{
+			Name:        "input",
+			Aliases:     []string{"i"},
+			Usage:       "Path to payload file. If empty, payload is read from stdin",
+			Destination: &input,
+		},
+		&cli.StringSliceFlag{
+			Name:        "header",
+			Aliases:     []string{"H"},
+			Usage:       "HTTP header of the request, e.g. 'Content-Type: application/json'",
+			Destination: &headers,
+		},
+	},
+		logger.Flags(),
+		policy.Flags(),
+		hmac.Flags(),
+	)
+
+	return &cli.Command{
+		Name:  "eval",
+		Usage: "Build message from payload in the same way as HTTP server and print output of the policy without transmitting it",
+		Flags: flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			newLogger, logCloser, err := logger.New()
+			if err != nil {
+				return goerr.Wrap(err, "failed to create logger")
+			}
+			defer logCloser()
+			logging.SetDefault(newLogger)
+
+			newRequest, ok := evalSources[source]
+			if !ok {
+				return goerr.New("unsupported source", goerr.V("source", source))
+			}
+			req := newRequest(schema)
+
+			if req.Header == nil {
+				req.Header = map[string]string{}
+			}
+			for _, hdr := range headers {
+				kv := strings.SplitN(hdr, ":", 2)
+				if len(kv) != 2 {
+					return goerr.New("invalid header format, it must be 'Key: Value'", goerr.V("header", hdr))
+				}
+				req.Header[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+			}
+
+			var r io.Reader = os.Stdin
+			if input != "" {
+				fd, err := os.Open(filepath.Clean(input))
+				if err != nil {
+					return goerr.Wrap(err, "failed to open input", goerr.V("path", input))
+				}
+				defer safe.Close(ctx, fd)
+				r = fd
+			}
+			payload, err := io.ReadAll(r)
+			if err != nil {
+				return goerr.Wrap(err, "failed to read payload")
+			}
+			if _, ok := req.Header["Content-Type"]; !ok && json.Valid(payload) {
+				req.Header["Content-Type"] = "application/json"
+			}
+
+			body, err := json.Marshal(string(payload))
+			if err != nil {
+				return goerr.Wrap(err, "failed to encode payload")
+			}
+			req.Body = body
+
+			profiles, err := hmac.New()
+			if err != nil {
+				return err
+			}
+			if source == "hmac" && profiles == nil {
+				return goerr.New("hmac-profiles is required for hmac source")
+			}
+
+			messages, err := buildMessages(ctx, req, http_server.WithHMACProfiles(profiles))
+			if err != nil {
+				return err
+			}
+			if len(messages) == 0 {
+				return goerr.New("no message is built from the payload", goerr.V("source", source))
+			}
+
+			client, err := policy.New(ctx)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create policy client")
+			}
+			uc := usecase.New(adapter.New(adapter.WithPolicy(client)))
+
+			encoder := json.NewEncoder(cmd.Root().Writer)
+			encoder.SetIndent("", "  ")
+			for _, msg := range messages {
+				output, err := uc.Evaluate(ctx, msg)
+				if err != nil {
+					return err
+				}
+				if err := encoder.Encode(output); err != nil {
+					return goerr.Wrap(err, "failed to write output")
+				}
+			}
+
+			return nil
+		},
+	}
+}
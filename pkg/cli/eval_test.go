@@ -0,0 +1,124 @@
+package cli_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/cli"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// captureStdout returns output of f to os.Stdout
+func captureStdout(t *testing.T, f func()) []byte {
+	r, w, err := os.Pipe()
+	gt.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	gt.NoError(t, w.Close())
+	return gt.R1(io.ReadAll(r)).NoError(t)
+}
+
+func TestCmdEval(t *testing.T) {
+	payload := filepath.Join(t.TempDir(), "payload.json")
+	gt.NoError(t, os.WriteFile(payload, []byte(`{"action":"opened","issue":{"number":3}}`), 0600))
+
+	out := captureStdout(t, func() {
+		gt.NoError(t, cli.Run(context.Background(), []string{
+			"xroute", "eval",
+			"--log-level", "error",
+			"--policy", "testdata/policy",
+			"--source", "github.webhook",
+			"--schema", "issues",
+			"--input", payload,
+		}))
+	})
+
+	var output model.PolicyTransmitOutput
+	gt.NoError(t, json.Unmarshal(out, &output))
+	gt.A(t, output.Slack).Length(1).At(0, func(t testing.TB, v model.SlackMessage) {
+		gt.Equal(t, v.Channel, "#github-notify")
+		gt.S(t, v.Body).Contains(`"number":3`)
+	})
+}
+
+func TestCmdEvalHMAC(t *testing.T) {
+	dir := t.TempDir()
+	profiles := filepath.Join(dir, "profiles.json")
+	gt.NoError(t, os.WriteFile(profiles, []byte(`{"linear":{"secret":"s3cr3t","header":"Linear-Signature","algorithm":"sha256","encoding":"hex"}}`), 0600))
+	gt.NoError(t, os.Mkdir(filepath.Join(dir, "policy"), 0700))
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "policy", "route.rego"), []byte(`package route
+
+import rego.v1
+
+slack contains {"channel": "#linear", "title": input.schema} if {
+	input.source == "hmac"
+	input.auth.hmac.valid
+}
+`), 0600))
+
+	payload := filepath.Join(dir, "payload.json")
+	body := `{"action":"create"}`
+	gt.NoError(t, os.WriteFile(payload, []byte(body), 0600))
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	_, _ = mac.Write([]byte(body))
+
+	eval := func(args ...string) model.PolicyTransmitOutput {
+		out := captureStdout(t, func() {
+			gt.NoError(t, cli.Run(context.Background(), append([]string{
+				"xroute", "eval",
+				"--log-level", "error",
+				"--policy", filepath.Join(dir, "policy"),
+				"--hmac-profiles", profiles,
+				"--source", "hmac",
+				"--schema", "linear/issue",
+				"--input", payload,
+			}, args...)))
+		})
+		var output model.PolicyTransmitOutput
+		gt.NoError(t, json.Unmarshal(out, &output))
+		return output
+	}
+
+	// Signature is verified by the profile
+	output := eval("-H", "Linear-Signature: "+hex.EncodeToString(mac.Sum(nil)))
+	gt.A(t, output.Slack).Length(1).At(0, func(t testing.TB, v model.SlackMessage) {
+		gt.Equal(t, v.Title, "issue")
+	})
+
+	output = eval()
+	gt.A(t, output.Slack).Length(0)
+
+	// Profiles are required for hmac
+	gt.Error(t, cli.Run(context.Background(), []string{
+		"xroute", "eval",
+		"--log-level", "error",
+		"--policy", filepath.Join(dir, "policy"),
+		"--source", "hmac",
+		"--schema", "linear/issue",
+		"--input", payload,
+	}))
+}
+
+func TestCmdEvalUnknownSource(t *testing.T) {
+	// Sources verified over network are not supported
+	for _, source := range []string{"unknown", "github.actions", "aws.sns"} {
+		gt.Error(t, cli.Run(context.Background(), []string{
+			"xroute", "eval",
+			"--log-level", "error",
+			"--policy", "testdata/policy",
+			"--source", source,
+		}))
+	}
+}
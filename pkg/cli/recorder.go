@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	http_server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+// messageRecorder is UseCases that records messages built by HTTP server instead of routing them. It's used to build model.Message from HTTP request in the same way as the server.
+type messageRecorder struct {
+	*usecase.UseCases
+	messages []model.Message
+}
+
+func (x *messageRecorder) Authorize(ctx context.Context, input model.PolicyAuthzInput) error {
+	return nil
+}
+
+func (x *messageRecorder) Route(ctx context.Context, msg model.Message) error {
+	x.messages = append(x.messages, msg)
+	return nil
+}
+
+// httpCapture is a recorded HTTP request.
+type httpCapture struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Header map[string]string `json:"header"`
+
+	// Body is HTTP body. If it's JSON string, the string is used as raw body. Otherwise, the JSON value itself is used as body.
+	Body json.RawMessage `json:"body"`
+}
+
+func (x httpCapture) body() []byte {
+	var s string
+	if err := json.Unmarshal(x.Body, &s); err == nil {
+		return []byte(s)
+	}
+	return x.Body
+}
+
+// onlinePaths are handlers that verify the request over network, i.e. OIDC token of GitHub Actions against JWKS of GitHub and signing certificate of SNS. The captured request can not be verified offline, and its timestamp or token expires anyway.
+var onlinePaths = []string{
+	"/msg/github/actions",
+	"/msg/sns/",
+}
+
+// buildMessages sends the request to HTTP server and returns messages that the server routes. Logs of the server are discarded because they are not part of the command output, and the rejection is returned as error.
+func buildMessages(ctx context.Context, req httpCapture, options ...http_server.Option) ([]model.Message, error) {
+	for _, path := range onlinePaths {
+		if strings.HasPrefix(req.Path, path) {
+			return nil, goerr.New("request to the path needs verification over network, use model.Message in JSON with auth instead", goerr.V("path", req.Path))
+		}
+	}
+
+	ctx = logging.Inject(ctx, logging.Nop())
+	recorder := &messageRecorder{UseCases: usecase.New(adapter.New())}
+	srv := http_server.New(recorder, options...)
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	r := httptest.NewRequestWithContext(ctx, method, req.Path, bytes.NewReader(req.body()))
+	for k, v := range req.Header {
+		r.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		return nil, goerr.New("HTTP server rejected the request",
+			goerr.V("path", req.Path),
+			goerr.V("status", w.Code),
+			goerr.V("response", w.Body.String()),
+		)
+	}
+
+	return recorder.messages, nil
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/cli/config"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/urfave/cli/v3"
+)
+
+func cmdReplay() *cli.Command {
+	var (
+		all     bool
+		reroute bool
+
+		logger     config.Logger
+		policy     config.Policy
+		slack      config.Slack
+		teams      config.Teams
+		discord    config.Discord
+		smtp       config.SMTP
+		pagerDuty  config.PagerDuty
+		github     config.GitHub
+		webhook    config.Webhook
+		deadLetter config.DeadLetter
+	)
+
+	flags := joinFlags([]cli.Flag{
+		&cli.BoolFlag{
+			Name:        "all",
+			Usage:       "Replay all dead-letters",
+			Destination: &all,
+		},
+		&cli.BoolFlag{
+			Name:        "reroute",
+			Usage:       "Evaluate the policy again with the original message and transmit outputs to the failed destination, instead of transmitting the stored delivery. Use it after the policy is fixed",
+			Destination: &reroute,
+		},
+	},
+		logger.Flags(),
+		policy.Flags(),
+		slack.Flags(),
+		teams.Flags(),
+		discord.Flags(),
+		smtp.Flags(),
+		pagerDuty.Flags(),
+		github.Flags(),
+		webhook.Flags(),
+		deadLetter.Flags(),
+	)
+
+	return &cli.Command{
+		Name:      "replay",
+		Usage:     "Transmit given up deliveries again",
+		ArgsUsage: "[ID...]",
+		Flags:     flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			newLogger, logCloser, err := logger.New()
+			if err != nil {
+				return goerr.Wrap(err, "failed to create logger")
+			}
+			defer logCloser()
+			logging.SetDefault(newLogger)
+
+			store, err := deadLetter.NewFile()
+			if err != nil {
+				return err
+			}
+
+			adapterOptions := []adapter.Option{
+				adapter.WithDeadLetter(store),
+			}
+			if reroute {
+				client, err := policy.New(ctx)
+				if err != nil {
+					return goerr.Wrap(err, "failed to create policy client")
+				}
+				adapterOptions = append(adapterOptions, adapter.WithPolicy(client))
+			}
+			if client := slack.New(); client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithSlack(client))
+			}
+			if store, err := slack.NewThreadStore(); err != nil {
+				return err
+			} else {
+				adapterOptions = append(adapterOptions, adapter.WithSlackThreadStore(store))
+			}
+			if client, err := teams.New(); err != nil {
+				return goerr.Wrap(err, "failed to create teams client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithTeams(client))
+			}
+			if client, err := discord.New(); err != nil {
+				return goerr.Wrap(err, "failed to create discord client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithDiscord(client))
+			}
+			if client, err := smtp.New(); err != nil {
+				return goerr.Wrap(err, "failed to create SMTP client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithEmail(client))
+			}
+			if client, err := pagerDuty.New(); err != nil {
+				return goerr.Wrap(err, "failed to create pagerduty client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithPagerDuty(client))
+			}
+			if client, err := github.New(); err != nil {
+				return goerr.Wrap(err, "failed to create github client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithGitHub(client))
+			}
+
+			webhookOptions, err := webhook.Options()
+			if err != nil {
+				return err
+			}
+
+			slackOptions, err := slack.Options()
+			if err != nil {
+				return err
+			}
+
+			// Deliveries are transmitted synchronously because no queue is configured
+			uc := usecase.New(adapter.New(adapterOptions...), append(webhookOptions, slackOptions...)...)
+
+			ids := cmd.Args().Slice()
+			if all {
+				entries, err := uc.ListDeadLetters(ctx)
+				if err != nil {
+					return err
+				}
+				ids = ids[:0]
+				for _, entry := range entries {
+					ids = append(ids, entry.ID)
+				}
+			}
+			if len(ids) == 0 {
+				return goerr.New("no dead-letter ID is specified, use --all to replay all")
+			}
+
+			replay := uc.ReplayDeadLetter
+			if reroute {
+				replay = uc.RerouteDeadLetter
+			}
+
+			var failed int
+			for _, id := range ids {
+				if err := replay(ctx, id); err != nil {
+					newLogger.Error("Failed to replay", "id", id, "error", err)
+					failed++
+					continue
+				}
+				newLogger.Info("Replayed", "id", id)
+			}
+
+			if failed > 0 {
+				return goerr.New("some dead-letters are not replayed", goerr.V("failed", failed), goerr.V("total", len(ids)))
+			}
+			return nil
+		},
+	}
+}
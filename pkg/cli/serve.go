@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/m-mizutani/goerr/v2"
@@ -13,6 +14,7 @@ import (
 	http_server "github.com/m-mizutani/xroute/pkg/controller/http"
 	"github.com/m-mizutani/xroute/pkg/usecase"
 	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/m-mizutani/xroute/pkg/utils/watcher"
 	"github.com/urfave/cli/v3"
 )
 
@@ -20,10 +22,24 @@ func cmdServe() *cli.Command {
 	var (
 		addr                string
 		githubWebhookSecret string
+		gitlabWebhookToken  string
+		gitlabSigningToken  string
+		slackSigningSecret  string
+		adminToken          string
+		dryRun              bool
 
-		logger config.Logger
-		policy config.Policy
-		slack  config.Slack
+		logger     config.Logger
+		policy     config.Policy
+		slack      config.Slack
+		teams      config.Teams
+		discord    config.Discord
+		smtp       config.SMTP
+		pagerDuty  config.PagerDuty
+		github     config.GitHub
+		webhook    config.Webhook
+		hmac       config.HMAC
+		queue      config.Queue
+		deadLetter config.DeadLetter
 	)
 
 	flags := joinFlags([]cli.Flag{
@@ -42,10 +58,49 @@ func cmdServe() *cli.Command {
 			Sources:     cli.EnvVars("XROUTE_GITHUB_WEBHOOK_SECRET"),
 			Destination: &githubWebhookSecret,
 		},
+		&cli.StringFlag{
+			Name:        "gitlab-webhook-token",
+			Usage:       "Secret token of GitLab webhook, compared with X-Gitlab-Token header",
+			Sources:     cli.EnvVars("XROUTE_GITLAB_WEBHOOK_TOKEN"),
+			Destination: &gitlabWebhookToken,
+		},
+		&cli.StringFlag{
+			Name:        "gitlab-signing-token",
+			Usage:       "Signing token of GitLab webhook (whsec_...) to verify webhook-signature header",
+			Sources:     cli.EnvVars("XROUTE_GITLAB_SIGNING_TOKEN"),
+			Destination: &gitlabSigningToken,
+		},
+		&cli.StringFlag{
+			Name:        "slack-signing-secret",
+			Usage:       "Signing secret of Slack app to verify requests to /msg/slack/events. If set, /slack/interactions endpoint is also enabled to route clicks of buttons",
+			Sources:     cli.EnvVars("XROUTE_SLACK_SIGNING_SECRET"),
+			Destination: &slackSigningSecret,
+		},
+		&cli.StringFlag{
+			Name:        "admin-token",
+			Usage:       "Bearer token for admin API (/admin). If empty, admin API is disabled",
+			Sources:     cli.EnvVars("XROUTE_ADMIN_TOKEN"),
+			Destination: &adminToken,
+		},
+		&cli.BoolFlag{
+			Name:        "dry-run",
+			Usage:       "Log rendered payloads and return them in HTTP response instead of transmitting",
+			Sources:     cli.EnvVars("XROUTE_DRY_RUN"),
+			Destination: &dryRun,
+		},
 	},
 		logger.Flags(),
 		policy.Flags(),
 		slack.Flags(),
+		teams.Flags(),
+		discord.Flags(),
+		smtp.Flags(),
+		pagerDuty.Flags(),
+		github.Flags(),
+		webhook.Flags(),
+		hmac.Flags(),
+		queue.Flags(),
+		deadLetter.Flags(),
 	)
 
 	return &cli.Command{
@@ -65,30 +120,209 @@ func cmdServe() *cli.Command {
 			newLogger.Info("Starting server",
 				"addr", addr,
 				"github-webhook-secret", len(githubWebhookSecret) > 0,
+				"gitlab-webhook-token", len(gitlabWebhookToken) > 0,
+				"gitlab-signing-token", len(gitlabSigningToken) > 0,
+				"slack-signing-secret", len(slackSigningSecret) > 0,
+				"admin-token", len(adminToken) > 0,
+				"dry-run", dryRun,
 				"logger", logger,
 				"policy", policy,
 				"slack", slack,
+				"teams", teams,
+				"discord", discord,
+				"smtp", smtp,
+				"pagerduty", pagerDuty,
+				"github", github,
+				"webhook", webhook,
+				"hmac", hmac,
+				"queue", queue,
+				"dead-letter", deadLetter,
 			)
 
 			var adapterOptions []adapter.Option
 			if client := slack.New(); client != nil {
 				adapterOptions = append(adapterOptions, adapter.WithSlack(client))
 			}
+			if store, err := slack.NewThreadStore(); err != nil {
+				return err
+			} else {
+				adapterOptions = append(adapterOptions, adapter.WithSlackThreadStore(store))
+			}
+			if client, err := teams.New(); err != nil {
+				return goerr.Wrap(err, "failed to create teams client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithTeams(client))
+			}
+			if client, err := discord.New(); err != nil {
+				return goerr.Wrap(err, "failed to create discord client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithDiscord(client))
+			}
+			if client, err := smtp.New(); err != nil {
+				return goerr.Wrap(err, "failed to create SMTP client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithEmail(client))
+			}
+			if client, err := pagerDuty.New(); err != nil {
+				return goerr.Wrap(err, "failed to create pagerduty client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithPagerDuty(client))
+			}
+			if client, err := github.New(); err != nil {
+				return goerr.Wrap(err, "failed to create github client")
+			} else if client != nil {
+				adapterOptions = append(adapterOptions, adapter.WithGitHub(client))
+			}
 
-			if client, err := policy.New(); err != nil {
+			bundleClient, err := policy.NewBundle()
+			if err != nil {
+				return err
+			}
+			loadPolicy := policy.Loader(bundleClient)
+			if client, err := policy.Load(ctx, bundleClient); err != nil {
 				return goerr.Wrap(err, "failed to create policy client")
 			} else {
 				adapterOptions = append(adapterOptions, adapter.WithPolicy(client))
 			}
+			policyLoadedAt := time.Now()
+
+			q, err := queue.New()
+			if err != nil {
+				return goerr.Wrap(err, "failed to create delivery queue")
+			}
+			if q != nil {
+				adapterOptions = append(adapterOptions, adapter.WithQueue(q))
+			}
+
+			if store, err := deadLetter.New(); err != nil {
+				return err
+			} else {
+				adapterOptions = append(adapterOptions, adapter.WithDeadLetter(store))
+			}
+			if q != nil && deadLetter.InMemory() {
+				newLogger.Warn("Dead-letters are kept in memory. They are lost on restart and replay command can not read them, set --dead-letter-dir to replay them by the command")
+			}
 
 			adapters := adapter.New(adapterOptions...)
-			uc := usecase.New(adapters)
+			webhookOptions, err := webhook.Options()
+			if err != nil {
+				return err
+			}
+			slackOptions, err := slack.Options()
+			if err != nil {
+				return err
+			}
+			ucOptions := append(queue.Options(), webhookOptions...)
+			ucOptions = append(ucOptions, slackOptions...)
+			if dryRun {
+				ucOptions = append(ucOptions, usecase.WithDryRun())
+			}
+			ucOptions = append(ucOptions,
+				usecase.WithPolicyLoader(loadPolicy),
+				usecase.WithPolicyLoadedAt(policyLoadedAt),
+			)
+			uc := usecase.New(adapters, ucOptions...)
+
+			// Reload policy on SIGHUP, on file change if enabled, and on update of the bundle
+			reloadCtx, stopReload := context.WithCancel(ctx)
+			defer stopReload()
+
+			// fetchBundle downloads the bundle, and returns true if it's replaced. The policy loader only builds the policy from the fetched bundle.
+			fetchBundle := func() bool {
+				changed, err := bundleClient.Fetch(reloadCtx)
+				if err != nil {
+					newLogger.Warn("Failed to fetch policy bundle, keep the current policy", "error", err)
+					return false
+				}
+				return changed
+			}
+
+			hupCh := make(chan os.Signal, 1)
+			signal.Notify(hupCh, syscall.SIGHUP)
+			defer signal.Stop(hupCh)
+			go func() {
+				for {
+					select {
+					case <-reloadCtx.Done():
+						return
+					case <-hupCh:
+						if bundleClient != nil {
+							fetchBundle()
+						}
+						_ = uc.ReloadPolicy(reloadCtx, "signal")
+					}
+				}
+			}()
+
+			if policy.Watch() {
+				go func() {
+					if err := watcher.Watch(reloadCtx, policy.Path(), 500*time.Millisecond, func() {
+						_ = uc.ReloadPolicy(reloadCtx, "file")
+					}); err != nil {
+						newLogger.Error("Policy watcher stopped", "error", err)
+					}
+				}()
+			}
+
+			if bundleClient != nil {
+				go func() {
+					ticker := time.NewTicker(policy.BundleInterval())
+					defer ticker.Stop()
+					for {
+						select {
+						case <-reloadCtx.Done():
+							return
+						case <-ticker.C:
+							if fetchBundle() {
+								_ = uc.ReloadPolicy(reloadCtx, "bundle")
+							}
+						}
+					}
+				}()
+			}
+
+			// Start delivery worker
+			workerCtx, stopWorker := context.WithCancel(ctx)
+			workerDone := make(chan struct{})
+			defer func() {
+				stopWorker()
+				<-workerDone
+			}()
+			go func() {
+				defer close(workerDone)
+				if q == nil {
+					return
+				}
+				if err := uc.RunDelivery(workerCtx); err != nil {
+					newLogger.Error("Delivery worker stopped", "error", err)
+				}
+			}()
 
 			// Start HTTP server
 			var serverOptions []http_server.Option
 			if len(githubWebhookSecret) > 0 {
 				serverOptions = append(serverOptions, http_server.WithGitHubWebhookSecret(githubWebhookSecret))
 			}
+			if len(gitlabWebhookToken) > 0 {
+				serverOptions = append(serverOptions, http_server.WithGitLabWebhookToken(gitlabWebhookToken))
+			}
+			if len(gitlabSigningToken) > 0 {
+				serverOptions = append(serverOptions, http_server.WithGitLabSigningToken(gitlabSigningToken))
+			}
+			if len(slackSigningSecret) > 0 {
+				serverOptions = append(serverOptions, http_server.WithSlackSigningSecret(slackSigningSecret))
+			}
+			if profiles, err := hmac.New(); err != nil {
+				return err
+			} else if profiles != nil {
+				serverOptions = append(serverOptions, http_server.WithHMACProfiles(profiles))
+			}
+			if len(adminToken) > 0 {
+				serverOptions = append(serverOptions, http_server.WithAdminToken(adminToken))
+			}
+			if dryRun {
+				serverOptions = append(serverOptions, http_server.WithDryRun())
+			}
 
 			s := &http.Server{
 				Addr:              addr,
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-test/deep"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/cli/config"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+	"github.com/urfave/cli/v3"
+)
+
+const goldenSuffix = ".golden.json"
+
+func cmdTest() *cli.Command {
+	var (
+		update bool
+
+		logger config.Logger
+		policy config.Policy
+	)
+
+	flags := joinFlags([]cli.Flag{
+		&cli.BoolFlag{
+			Name:        "update",
+			Aliases:     []string{"u"},
+			Usage:       "Update golden files with the current policy output instead of comparing",
+			Destination: &update,
+		},
+	},
+		logger.Flags(),
+		policy.Flags(),
+	)
+
+	return &cli.Command{
+		Name:  "test",
+		Usage: "Evaluate policy with fixture files and compare the outputs with golden files",
+		Description: `Fixture file is one of:
+  - *.json: model.Message in JSON
+  - *.jsonl: HTTP requests, one JSON object per line with "method", "path", "header" and "body". Requests to /msg/github/actions and /msg/sns are not supported because they are verified over network
+Expected outputs of data.route are stored in <fixture name>.golden.json as an array, one element per message.`,
+		ArgsUsage: "FILE_OR_DIR...",
+		Flags:     flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			newLogger, logCloser, err := logger.New()
+			if err != nil {
+				return goerr.Wrap(err, "failed to create logger")
+			}
+			defer logCloser()
+			logging.SetDefault(newLogger)
+
+			client, err := policy.New(ctx)
+			if err != nil {
+				return goerr.Wrap(err, "failed to create policy client")
+			}
+			uc := usecase.New(adapter.New(adapter.WithPolicy(client)))
+
+			if cmd.Args().Len() == 0 {
+				return goerr.New("no fixture file or directory is specified")
+			}
+
+			fixtures, err := findFixtures(cmd.Args().Slice())
+			if err != nil {
+				return err
+			}
+
+			w := cmd.Root().Writer
+			var failed int
+			for _, fixture := range fixtures {
+				ok, err := testFixture(ctx, w, uc, fixture, update)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				return goerr.New("policy test failed", goerr.V("failed", failed), goerr.V("total", len(fixtures)))
+			}
+			return nil
+		},
+	}
+}
+
+func isFixture(path string) bool {
+	if strings.HasSuffix(path, goldenSuffix) {
+		return false
+	}
+	ext := filepath.Ext(path)
+	return ext == ".json" || ext == ".jsonl"
+}
+
+func goldenPath(fixture string) string {
+	return strings.TrimSuffix(fixture, filepath.Ext(fixture)) + goldenSuffix
+}
+
+func findFixtures(paths []string) ([]string, error) {
+	var fixtures []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isFixture(p) {
+				fixtures = append(fixtures, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to find fixture files", goerr.V("path", path))
+		}
+	}
+
+	sort.Strings(fixtures)
+	return fixtures, nil
+}
+
+// loadFixture builds messages from the fixture file.
+func loadFixture(ctx context.Context, path string) ([]model.Message, error) {
+	fd, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to open fixture", goerr.V("path", path))
+	}
+	defer safe.Close(ctx, fd)
+
+	if filepath.Ext(path) == ".json" {
+		var msg model.Message
+		if err := json.NewDecoder(fd).Decode(&msg); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode message", goerr.V("path", path))
+		}
+		return []model.Message{msg}, nil
+	}
+
+	var messages []model.Message
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var req httpCapture
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			return nil, goerr.Wrap(err, "failed to decode HTTP request", goerr.V("path", path), goerr.V("line", n))
+		}
+
+		msgs, err := buildMessages(ctx, req)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to build message", goerr.V("path", path), goerr.V("line", n))
+		}
+		messages = append(messages, msgs...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, goerr.Wrap(err, "failed to read fixture", goerr.V("path", path))
+	}
+
+	return messages, nil
+}
+
+// testFixture evaluates policy with messages in the fixture and compares the outputs with the golden file. It returns false if they do not match.
+func testFixture(ctx context.Context, w io.Writer, uc *usecase.UseCases, fixture string, update bool) (bool, error) {
+	messages, err := loadFixture(ctx, fixture)
+	if err != nil {
+		return false, err
+	}
+
+	outputs := make([]*model.PolicyTransmitOutput, len(messages))
+	for i, msg := range messages {
+		output, err := uc.Evaluate(ctx, msg)
+		if err != nil {
+			return false, goerr.Wrap(err, "failed to evaluate policy", goerr.V("fixture", fixture), goerr.V("index", i))
+		}
+		outputs[i] = output
+	}
+
+	golden := goldenPath(fixture)
+	if update {
+		raw, err := json.MarshalIndent(outputs, "", "  ")
+		if err != nil {
+			return false, goerr.Wrap(err, "failed to marshal outputs")
+		}
+		if err := os.WriteFile(golden, append(raw, '\n'), 0600); err != nil {
+			return false, goerr.Wrap(err, "failed to write golden file", goerr.V("path", golden))
+		}
+		fmt.Fprintf(w, "UPDATE %s\n", golden)
+		return true, nil
+	}
+
+	raw, err := os.ReadFile(filepath.Clean(golden))
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Fprintf(w, "FAIL   %s: golden file %s is not found, run with --update to create it\n", fixture, golden)
+			return false, nil
+		}
+		return false, goerr.Wrap(err, "failed to read golden file", goerr.V("path", golden))
+	}
+
+	var expected []any
+	if err := json.Unmarshal(raw, &expected); err != nil {
+		return false, goerr.Wrap(err, "failed to decode golden file", goerr.V("path", golden))
+	}
+
+	// Normalize outputs in the same JSON representation as golden file
+	var actual []any
+	if raw, err := json.Marshal(outputs); err != nil {
+		return false, goerr.Wrap(err, "failed to marshal outputs")
+	} else if err := json.Unmarshal(raw, &actual); err != nil {
+		return false, goerr.Wrap(err, "failed to unmarshal outputs")
+	}
+
+	if diff := deep.Equal(expected, actual); diff != nil {
+		fmt.Fprintf(w, "FAIL   %s\n", fixture)
+		for _, d := range diff {
+			fmt.Fprintf(w, "         %s\n", d)
+		}
+		return false, nil
+	}
+
+	fmt.Fprintf(w, "PASS   %s\n", fixture)
+	return true, nil
+}
@@ -0,0 +1,57 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/cli"
+)
+
+func TestCmdTest(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "message.json"), []byte(`{"schema":"test","data":"Hello"}`), 0600))
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "requests.jsonl"), []byte(
+		`{"path":"/msg/github/webhook","header":{"X-GitHub-Event":"issues","Content-Type":"application/json"},"body":{"action":"opened"}}`+"\n"+
+			`{"path":"/msg/raw/text","body":"plain text"}`+"\n",
+	), 0600))
+
+	args := []string{"xroute", "test", "--log-level", "error", "--policy", "testdata/policy"}
+
+	// Golden files do not exist yet
+	gt.Error(t, cli.Run(ctx, append(args, dir)))
+
+	gt.NoError(t, cli.Run(ctx, append(args, "--update", dir)))
+	golden := gt.R1(os.ReadFile(filepath.Join(dir, "requests.golden.json"))).NoError(t)
+	gt.S(t, string(golden)).Contains(`"body": "plain text"`)
+
+	gt.NoError(t, cli.Run(ctx, append(args, dir)))
+
+	// Logs of replayed HTTP requests are not mixed into the result
+	out := captureStdout(t, func() {
+		gt.NoError(t, cli.Run(ctx, []string{"xroute", "test", "--log-level", "info", "--policy", "testdata/policy", dir}))
+	})
+	gt.S(t, string(out)).Contains("PASS")
+	gt.S(t, string(out)).NotContains("HTTP Request")
+
+	// Policy output does not match with modified golden file
+	modified := strings.Replace(string(golden), "plain text", "modified", 1)
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "requests.golden.json"), []byte(modified), 0600))
+	gt.Error(t, cli.Run(ctx, append(args, dir)))
+}
+
+func TestCmdTestOnlineCapture(t *testing.T) {
+	dir := t.TempDir()
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "requests.jsonl"), []byte(
+		`{"path":"/msg/github/actions","header":{"Authorization":"Bearer token"},"body":{}}`+"\n",
+	), 0600))
+
+	err := cli.Run(context.Background(), []string{"xroute", "test", "--log-level", "error", "--policy", "testdata/policy", "--update", dir})
+	gt.Error(t, err)
+	gt.S(t, err.Error()).Contains("verification over network")
+}
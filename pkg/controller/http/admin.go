@@ -0,0 +1,104 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+// authAdmin requires "Authorization: Bearer <token>" header for admin API.
+func authAdmin(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hdr := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+			if len(hdr) != 2 || strings.ToLower(hdr[0]) != "bearer" ||
+				subtle.ConstantTimeCompare([]byte(hdr[1]), []byte(token)) != 1 {
+				handleError(r.Context(), w, goerr.New("Invalid admin token", goerr.T(types.ErrTagUnauthorized)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func adminRoutes(r chi.Router, uc interfaces.UseCases) {
+	r.Route("/dead-letters", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			entries, err := uc.ListDeadLetters(r.Context())
+			if err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeJSON(w, r, entries)
+		})
+
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			entry, err := uc.GetDeadLetter(r.Context(), r.PathValue("id"))
+			if err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeJSON(w, r, entry)
+		})
+
+		r.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if err := uc.DeleteDeadLetter(r.Context(), r.PathValue("id")); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			safe.Write(r.Context(), w, []byte("OK"))
+		})
+
+		r.Post("/{id}/replay", func(w http.ResponseWriter, r *http.Request) {
+			if err := uc.ReplayDeadLetter(r.Context(), r.PathValue("id")); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			safe.Write(r.Context(), w, []byte("OK"))
+		})
+	})
+
+	r.Route("/policy", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			status, err := uc.PolicyStatus(r.Context())
+			if err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeJSON(w, r, status)
+		})
+
+		r.Post("/reload", func(w http.ResponseWriter, r *http.Request) {
+			if err := uc.ReloadPolicy(r.Context(), "api"); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+
+			status, err := uc.PolicyStatus(r.Context())
+			if err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeJSON(w, r, status)
+		})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		handleError(r.Context(), w, goerr.Wrap(err, "Failed to marshal response"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	safe.Write(r.Context(), w, raw)
+}
@@ -0,0 +1,117 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+func TestAdminDeadLetters(t *testing.T) {
+	uc := &mock.UseCasesMock{
+		ListDeadLettersFunc: func(ctx context.Context) ([]*model.DeadLetter, error) {
+			return []*model.DeadLetter{
+				{ID: "dl-1", Error: "invalid_auth"},
+			}, nil
+		},
+		ReplayDeadLetterFunc: func(ctx context.Context, id string) error {
+			return nil
+		},
+	}
+	srv := server.New(uc, server.WithAdminToken("my-token"))
+
+	t.Run("list", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/dead-letters", nil)
+		r.Header.Set("Authorization", "Bearer my-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+
+		gt.Equal(t, w.Code, http.StatusOK)
+		var entries []*model.DeadLetter
+		gt.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		gt.A(t, entries).Length(1)
+		gt.Equal(t, entries[0].ID, "dl-1")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/admin/dead-letters/dl-1/replay", nil)
+		r.Header.Set("Authorization", "Bearer my-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+
+		gt.Equal(t, w.Code, http.StatusOK)
+		gt.A(t, uc.ReplayDeadLetterCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx context.Context
+			ID  string
+		}) {
+			gt.Equal(t, v.ID, "dl-1")
+		})
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/dead-letters", nil)
+		r.Header.Set("Authorization", "Bearer wrong-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+
+		gt.Equal(t, w.Code, http.StatusUnauthorized)
+	})
+}
+
+func TestAdminDisabled(t *testing.T) {
+	srv := server.New(&mock.UseCasesMock{})
+
+	r := httptest.NewRequest("GET", "/admin/dead-letters", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	gt.Equal(t, w.Code, http.StatusNotFound)
+}
+
+func TestAdminPolicy(t *testing.T) {
+	uc := &mock.UseCasesMock{
+		ReloadPolicyFunc: func(ctx context.Context, trigger string) error {
+			return nil
+		},
+		PolicyStatusFunc: func(ctx context.Context) (*model.PolicyStatus, error) {
+			return &model.PolicyStatus{
+				LastReload: &model.PolicyReloadResult{Trigger: "api", Success: true},
+			}, nil
+		},
+	}
+	srv := server.New(uc, server.WithAdminToken("my-token"))
+
+	t.Run("status", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/policy", nil)
+		r.Header.Set("Authorization", "Bearer my-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+
+		gt.Equal(t, w.Code, http.StatusOK)
+		var status model.PolicyStatus
+		gt.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		gt.NotEqual(t, status.LastReload, nil)
+		gt.True(t, status.LastReload.Success)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/admin/policy/reload", nil)
+		r.Header.Set("Authorization", "Bearer my-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+
+		gt.Equal(t, w.Code, http.StatusOK)
+		gt.A(t, uc.ReloadPolicyCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx     context.Context
+			Trigger string
+		}) {
+			gt.Equal(t, v.Trigger, "api")
+		})
+	})
+}
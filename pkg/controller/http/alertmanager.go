@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+)
+
+// handleAlertmanager routes notification of Prometheus Alertmanager webhook. If "split" query is true, the notification is split into messages per alert, and data of each message is model.AlertmanagerSplitAlert. Otherwise, data is model.AlertmanagerWebhook. Split alert is identified by fingerprint, status and start time, so the same state of an alert is routed only once even if Alertmanager retries the notification.
+func handleAlertmanager(r *http.Request, uc interfaces.UseCases, routed *routedKeys) error {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return goerr.Wrap(err, "Unable to read request body")
+	}
+
+	var split bool
+	if v := r.URL.Query().Get("split"); v != "" {
+		split, err = strconv.ParseBool(v)
+		if err != nil {
+			return goerr.Wrap(err, "split query must be boolean", goerr.V("split", v), goerr.T(types.ErrTagBadRequest))
+		}
+	}
+
+	var webhook model.AlertmanagerWebhook
+	if err := json.Unmarshal(raw, &webhook); err != nil {
+		return goerr.Wrap(err, "Failed to unmarshal Alertmanager webhook", goerr.V("body", string(raw)), goerr.T(types.ErrTagBadRequest))
+	}
+	if webhook.Version != "4" {
+		return goerr.New("Unsupported version of Alertmanager webhook", goerr.V("version", webhook.Version), goerr.T(types.ErrTagBadRequest))
+	}
+
+	base := model.Message{
+		Source: "alertmanager",
+		Schema: r.PathValue("schema"),
+		Header: cloneHeader(r.Header),
+		Body:   raw,
+	}
+
+	if !split {
+		base.Data = webhook
+		if err := routeMessage(r, uc, base); err != nil {
+			return goerr.Wrap(err, "Failed to route message")
+		}
+		return nil
+	}
+
+	batch := make([]batchMessage, len(webhook.Alerts))
+	for i, alert := range webhook.Alerts {
+		msg := base
+		msg.Data = model.AlertmanagerSplitAlert{
+			AlertmanagerAlert: alert,
+			GroupKey:          webhook.GroupKey,
+			Receiver:          webhook.Receiver,
+			GroupLabels:       webhook.GroupLabels,
+			ExternalURL:       webhook.ExternalURL,
+		}
+		batch[i] = batchMessage{
+			Key:     alert.Fingerprint + "\x00" + alert.Status + "\x00" + alert.StartsAt.Format(time.RFC3339Nano),
+			Message: msg,
+		}
+	}
+
+	return routeBatch(r, uc, routed, batch)
+}
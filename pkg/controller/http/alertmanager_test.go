@@ -0,0 +1,207 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+const alertmanagerPayload = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"HighLatency\"}",
+	"truncatedAlerts": 0,
+	"status": "firing",
+	"receiver": "xroute",
+	"groupLabels": {"alertname": "HighLatency"},
+	"commonLabels": {"alertname": "HighLatency", "severity": "critical"},
+	"commonAnnotations": {"summary": "High latency"},
+	"externalURL": "http://alertmanager.example.com",
+	"alerts": [
+		{
+			"status": "firing",
+			"labels": {"alertname": "HighLatency", "severity": "critical", "instance": "api-1"},
+			"annotations": {"summary": "High latency", "description": "p99 is 3s"},
+			"startsAt": "2024-04-05T17:31:00Z",
+			"endsAt": "0001-01-01T00:00:00Z",
+			"generatorURL": "http://prometheus.example.com/graph",
+			"fingerprint": "a1b2c3d4e5f60718"
+		},
+		{
+			"status": "resolved",
+			"labels": {"alertname": "HighLatency", "severity": "critical", "instance": "api-2"},
+			"annotations": {"summary": "High latency"},
+			"startsAt": "2024-04-05T17:00:00Z",
+			"endsAt": "2024-04-05T17:30:00Z",
+			"generatorURL": "http://prometheus.example.com/graph",
+			"fingerprint": "0f1e2d3c4b5a6978"
+		}
+	]
+}`
+
+func TestAlertmanager(t *testing.T) {
+	newUseCases := func() *mock.UseCasesMock {
+		return &mock.UseCasesMock{
+			AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+				return nil
+			},
+			RouteFunc: func(ctx context.Context, msg model.Message) error {
+				return nil
+			},
+		}
+	}
+
+	t.Run("grouped notification", func(t *testing.T) {
+		uc := newUseCases()
+		srv := server.New(uc)
+
+		r := httptest.NewRequest("POST", "/msg/alertmanager/prod", strings.NewReader(alertmanagerPayload))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		gt.Equal(t, w.Code, http.StatusOK)
+
+		gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx context.Context
+			Msg model.Message
+		}) {
+			gt.Equal(t, v.Msg.Source, "alertmanager")
+			gt.Equal(t, v.Msg.Schema, "prod")
+			data := gt.Cast[model.AlertmanagerWebhook](t, v.Msg.Data)
+			gt.Equal(t, data.Receiver, "xroute")
+			gt.A(t, data.Alerts).Length(2)
+			gt.Equal(t, data.Alerts[0].Labels["instance"], "api-1")
+		})
+	})
+
+	t.Run("split per alert", func(t *testing.T) {
+		uc := newUseCases()
+		srv := server.New(uc)
+
+		r := httptest.NewRequest("POST", "/msg/alertmanager/prod?split=true", strings.NewReader(alertmanagerPayload))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		gt.Equal(t, w.Code, http.StatusOK)
+
+		calls := uc.RouteCalls()
+		gt.A(t, calls).Length(2)
+
+		first := gt.Cast[model.AlertmanagerSplitAlert](t, calls[0].Msg.Data)
+		gt.Equal(t, first.Status, "firing")
+		gt.Equal(t, first.Fingerprint, "a1b2c3d4e5f60718")
+		gt.Equal(t, first.Labels["instance"], "api-1")
+		gt.Equal(t, first.Annotations["description"], "p99 is 3s")
+		gt.Equal(t, first.GroupLabels["alertname"], "HighLatency")
+		gt.Equal(t, first.Receiver, "xroute")
+
+		second := gt.Cast[model.AlertmanagerSplitAlert](t, calls[1].Msg.Data)
+		gt.Equal(t, second.Status, "resolved")
+		gt.Equal(t, second.EndsAt.Format("15:04"), "17:30")
+
+		// Fields of the alert are at top level of data in policy input
+		raw, err := json.Marshal(first)
+		gt.NoError(t, err)
+		var input map[string]any
+		gt.NoError(t, json.Unmarshal(raw, &input))
+		gt.Equal(t, input["fingerprint"], any("a1b2c3d4e5f60718"))
+		gt.Equal(t, input["labels"], any(map[string]any{"alertname": "HighLatency", "severity": "critical", "instance": "api-1"}))
+	})
+
+	t.Run("split alerts are authorized before routing any", func(t *testing.T) {
+		var authorized int
+		uc := newUseCases()
+		uc.AuthorizeFunc = func(ctx context.Context, input model.PolicyAuthzInput) error {
+			authorized++
+			if authorized == 2 {
+				return goerr.New("denied", goerr.T(types.ErrTagForbidden))
+			}
+			return nil
+		}
+		srv := server.New(uc)
+
+		r := httptest.NewRequest("POST", "/msg/alertmanager/prod?split=true", strings.NewReader(alertmanagerPayload))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		gt.Equal(t, w.Code, http.StatusForbidden)
+		gt.A(t, uc.RouteCalls()).Length(0)
+	})
+
+	t.Run("retried notification routes only failed alert", func(t *testing.T) {
+		failed := true
+		uc := newUseCases()
+		uc.RouteFunc = func(ctx context.Context, msg model.Message) error {
+			alert := msg.Data.(model.AlertmanagerSplitAlert)
+			if alert.Fingerprint == "a1b2c3d4e5f60718" && failed {
+				return errors.New("policy error")
+			}
+			return nil
+		}
+		srv := server.New(uc)
+		send := func(path string) int {
+			r := httptest.NewRequest("POST", path, strings.NewReader(alertmanagerPayload))
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+			return w.Code
+		}
+
+		// Failure of the first alert does not stop the second
+		gt.Equal(t, send("/msg/alertmanager/prod?split=true"), http.StatusInternalServerError)
+		calls := uc.RouteCalls()
+		gt.A(t, calls).Length(2)
+
+		failed = false
+		gt.Equal(t, send("/msg/alertmanager/prod?split=true"), http.StatusOK)
+		calls = uc.RouteCalls()
+		gt.A(t, calls).Length(3)
+		gt.Equal(t, gt.Cast[model.AlertmanagerSplitAlert](t, calls[2].Msg.Data).Fingerprint, "a1b2c3d4e5f60718")
+
+		gt.Equal(t, send("/msg/alertmanager/prod?split=true"), http.StatusOK)
+		gt.A(t, uc.RouteCalls()).Length(3)
+
+		// Notification to other schema is routed independently
+		gt.Equal(t, send("/msg/alertmanager/staging?split=true"), http.StatusOK)
+		gt.A(t, uc.RouteCalls()).Length(5)
+	})
+}
+
+func TestAlertmanagerInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		path string
+		body string
+	}{
+		"unsupported version": {
+			path: "/msg/alertmanager/prod",
+			body: `{"version": "3", "alerts": []}`,
+		},
+		"invalid JSON": {
+			path: "/msg/alertmanager/prod",
+			body: `{"version": "4", "alerts": {}}`,
+		},
+		"invalid split": {
+			path: "/msg/alertmanager/prod?split=yes",
+			body: alertmanagerPayload,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{}
+			srv := server.New(uc)
+
+			r := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+			gt.Equal(t, w.Code, http.StatusBadRequest)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+func TestAuthorizeBeforeRoute(t *testing.T) {
+	testCases := map[string]struct {
+		authErr error
+		code    int
+		routed  int
+	}{
+		"allowed": {
+			authErr: nil,
+			code:    http.StatusOK,
+			routed:  1,
+		},
+		"denied": {
+			authErr: goerr.New("denied", goerr.T(types.ErrTagForbidden)),
+			code:    http.StatusForbidden,
+			routed:  0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			uc := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					return tc.authErr
+				},
+				RouteFunc: func(ctx context.Context, msg model.Message) error {
+					return nil
+				},
+			}
+			srv := server.New(uc)
+
+			r := httptest.NewRequest("POST", "/msg/pubsub/json_schema", bytes.NewReader(pubsubJSON))
+			r.Header.Set("X-Custom", "blue")
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+
+			gt.Equal(t, w.Code, tc.code)
+			gt.A(t, uc.RouteCalls()).Length(tc.routed)
+			gt.A(t, uc.AuthorizeCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx   context.Context
+				Input model.PolicyAuthzInput
+			}) {
+				gt.Equal(t, v.Input.Method, "POST")
+				gt.Equal(t, v.Input.Path, "/msg/pubsub/json_schema")
+				gt.Equal(t, v.Input.Header["X-Custom"], "blue")
+			})
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/dryrun"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+// batchMessage is a message of a request that has multiple messages. Key identifies the message to route it only once, e.g. id of CloudEvent.
+type batchMessage struct {
+	Key     string
+	Message model.Message
+}
+
+// routeBatch routes messages of a request. All messages are authorized before routing any of them, so that denied message does not leave others routed. Messages with the same key are routed once, and failure of a message does not stop routing others. The request fails if any message fails, and then messages already routed are skipped when the sender retries the request, because their keys are remembered by routed.
+func routeBatch(r *http.Request, uc interfaces.UseCases, routed *routedKeys, messages []batchMessage) error {
+	for _, m := range messages {
+		if err := authorizeMessage(r, uc, m.Message); err != nil {
+			return goerr.Wrap(err, "Failed to authorize message", goerr.V("key", m.Key))
+		}
+	}
+
+	// Dry-run does not transmit messages, so they should be rendered again for the same request
+	if dryrun.Extract(r.Context()) != nil {
+		routed = nil
+	}
+
+	logger := logging.Extract(r.Context())
+	seen := map[string]struct{}{}
+	var failed []string
+	var firstErr error
+
+	for _, m := range messages {
+		key := r.URL.Path + "\x00" + m.Key
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if routed.has(key) {
+			logger.Info("Message has been routed already, skip it", "key", m.Key)
+			continue
+		}
+
+		if err := uc.Route(r.Context(), m.Message); err != nil {
+			logger.Error("Failed to route message", "error", err, "key", m.Key)
+			failed = append(failed, m.Key)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		routed.add(key)
+	}
+
+	if firstErr != nil {
+		return goerr.Wrap(firstErr, "Failed to route messages", goerr.V("failed", failed), goerr.V("total", len(messages)))
+	}
+	return nil
+}
+
+// routedKeysTTL is how long keys of routed messages are remembered. It should be longer than retry interval of senders.
+const routedKeysTTL = time.Hour
+
+// routedKeys remembers keys of routed messages in memory. It's not shared between server instances.
+type routedKeys struct {
+	mutex sync.Mutex
+	keys  map[string]time.Time
+}
+
+func newRoutedKeys() *routedKeys {
+	return &routedKeys{keys: map[string]time.Time{}}
+}
+
+func (x *routedKeys) has(key string) bool {
+	if x == nil {
+		return false
+	}
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	expiresAt, ok := x.keys[key]
+	return ok && time.Now().Before(expiresAt)
+}
+
+func (x *routedKeys) add(key string) {
+	if x == nil {
+		return
+	}
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	// Expired keys are removed here to bound memory usage
+	now := time.Now()
+	for k, expiresAt := range x.keys {
+		if !now.Before(expiresAt) {
+			delete(x.keys, k)
+		}
+	}
+	x.keys[key] = now.Add(routedKeysTTL)
+}
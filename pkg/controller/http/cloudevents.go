@@ -0,0 +1,255 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+const (
+	cloudEventsContentType      = "application/cloudevents+json"
+	cloudEventsBatchContentType = "application/cloudevents-batch+json"
+	cloudEventsHeaderPrefix     = "Ce-"
+)
+
+// cloudEventAttributes are attributes defined in the spec. Others are extensions.
+var cloudEventAttributes = map[string]struct{}{
+	"id":              {},
+	"source":          {},
+	"specversion":     {},
+	"type":            {},
+	"subject":         {},
+	"time":            {},
+	"datacontenttype": {},
+	"dataschema":      {},
+	"data":            {},
+	"data_base64":     {},
+}
+
+// handleCloudEvents routes CloudEvents in structured, batch or binary mode. Type of the event is used as schema, and source of the event is used as source of the message. Events are identified by source and id, so an event is routed only once even if the sender retries it.
+func handleCloudEvents(r *http.Request, uc interfaces.UseCases, routed *routedKeys) error {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return goerr.Wrap(err, "Unable to read request body")
+	}
+
+	var messages []model.Message
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case cloudEventsContentType:
+		var event map[string]any
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return goerr.Wrap(err, "Failed to unmarshal CloudEvent", goerr.V("body", string(raw)), goerr.T(types.ErrTagBadRequest))
+		}
+		msg, err := parseStructuredCloudEvent(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, *msg)
+
+	case cloudEventsBatchContentType:
+		var events []map[string]any
+		if err := json.Unmarshal(raw, &events); err != nil {
+			return goerr.Wrap(err, "Failed to unmarshal CloudEvents batch", goerr.V("body", string(raw)), goerr.T(types.ErrTagBadRequest))
+		}
+		for i, event := range events {
+			msg, err := parseStructuredCloudEvent(event)
+			if err != nil {
+				return goerr.Wrap(err, "Invalid CloudEvent in batch", goerr.V("index", i))
+			}
+			messages = append(messages, *msg)
+		}
+
+	default:
+		msg, err := parseBinaryCloudEvent(r.Header, raw)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, *msg)
+	}
+
+	batch := make([]batchMessage, len(messages))
+	for i, msg := range messages {
+		msg.Header = cloneHeader(r.Header)
+		batch[i] = batchMessage{
+			Key:     msg.CloudEvent.Source + "\x00" + msg.CloudEvent.ID,
+			Message: msg,
+		}
+	}
+
+	return routeBatch(r, uc, routed, batch)
+}
+
+// handleCloudEventsValidation responds to abuse protection request of CloudEvents HTTP webhook, e.g. subscription validation of Azure Event Grid.
+func handleCloudEventsValidation(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("WebHook-Request-Origin")
+	if origin == "" {
+		http.Error(w, "WebHook-Request-Origin header is required", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("WebHook-Allowed-Origin", origin)
+	w.Header().Set("WebHook-Allowed-Rate", "*")
+	safe.Write(r.Context(), w, []byte("OK"))
+}
+
+func parseStructuredCloudEvent(event map[string]any) (*model.Message, error) {
+	str := func(key string) string {
+		v, _ := event[key].(string)
+		return v
+	}
+
+	ce := &model.CloudEvent{
+		ID:              str("id"),
+		Source:          str("source"),
+		SpecVersion:     str("specversion"),
+		Type:            str("type"),
+		Subject:         str("subject"),
+		DataContentType: str("datacontenttype"),
+		DataSchema:      str("dataschema"),
+	}
+	if err := setCloudEventTime(ce, str("time")); err != nil {
+		return nil, err
+	}
+	for key, value := range event {
+		if _, ok := cloudEventAttributes[key]; !ok {
+			if ce.Extensions == nil {
+				ce.Extensions = map[string]any{}
+			}
+			ce.Extensions[key] = value
+		}
+	}
+	if err := validateCloudEvent(ce); err != nil {
+		return nil, err
+	}
+
+	// data is already decoded as JSON in structured mode, unless it's string of other content type.
+	data := event["data"]
+	if encoded, ok := event["data_base64"].(string); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, goerr.Wrap(err, "Invalid data_base64 of CloudEvent", goerr.V("id", ce.ID), goerr.T(types.ErrTagBadRequest))
+		}
+		if data, err = decodeCloudEventData(ce.DataContentType, decoded); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.Message{
+		Source:     ce.Source,
+		Schema:     ce.Type,
+		Body:       event,
+		Data:       data,
+		CloudEvent: ce,
+	}, nil
+}
+
+func parseBinaryCloudEvent(header http.Header, raw []byte) (*model.Message, error) {
+	ce := &model.CloudEvent{
+		DataContentType: header.Get("Content-Type"),
+	}
+	var timestamp string
+
+	for key, values := range header {
+		name, ok := strings.CutPrefix(http.CanonicalHeaderKey(key), cloudEventsHeaderPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		name = strings.ToLower(name)
+		value := values[0]
+
+		switch name {
+		case "id":
+			ce.ID = value
+		case "source":
+			ce.Source = value
+		case "specversion":
+			ce.SpecVersion = value
+		case "type":
+			ce.Type = value
+		case "subject":
+			ce.Subject = value
+		case "time":
+			timestamp = value
+		case "dataschema":
+			ce.DataSchema = value
+		default:
+			if ce.Extensions == nil {
+				ce.Extensions = map[string]any{}
+			}
+			ce.Extensions[name] = value
+		}
+	}
+
+	if ce.SpecVersion == "" {
+		return nil, goerr.New("Content-Type is not CloudEvents and ce-specversion header is missing", goerr.T(types.ErrTagBadRequest))
+	}
+	if err := setCloudEventTime(ce, timestamp); err != nil {
+		return nil, err
+	}
+	if err := validateCloudEvent(ce); err != nil {
+		return nil, err
+	}
+
+	data, err := decodeCloudEventData(ce.DataContentType, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Message{
+		Source:     ce.Source,
+		Schema:     ce.Type,
+		Body:       raw,
+		Data:       data,
+		CloudEvent: ce,
+	}, nil
+}
+
+func setCloudEventTime(ce *model.CloudEvent, value string) error {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return goerr.Wrap(err, "Invalid time of CloudEvent", goerr.V("time", value), goerr.T(types.ErrTagBadRequest))
+	}
+	ce.Time = &t
+	return nil
+}
+
+func validateCloudEvent(ce *model.CloudEvent) error {
+	if ce.SpecVersion != "1.0" {
+		return goerr.New("Unsupported specversion of CloudEvent", goerr.V("specversion", ce.SpecVersion), goerr.T(types.ErrTagBadRequest))
+	}
+	if ce.ID == "" || ce.Source == "" || ce.Type == "" {
+		return goerr.New("id, source and type of CloudEvent are required", goerr.V("event", ce), goerr.T(types.ErrTagBadRequest))
+	}
+	return nil
+}
+
+// decodeCloudEventData decodes data as JSON if the content type is JSON. Otherwise, it's returned as string.
+func decodeCloudEventData(contentType string, raw []byte) (any, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return string(raw), nil
+	}
+
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, goerr.Wrap(err, "Failed to unmarshal data of CloudEvent", goerr.V("data", string(raw)), goerr.T(types.ErrTagBadRequest))
+	}
+	return data, nil
+}
@@ -0,0 +1,250 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+func newCloudEventsUseCases() *mock.UseCasesMock {
+	return &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			return nil
+		},
+		RouteFunc: func(ctx context.Context, msg model.Message) error {
+			return nil
+		},
+	}
+}
+
+func TestCloudEventsStructured(t *testing.T) {
+	uc := newCloudEventsUseCases()
+	srv := server.New(uc)
+
+	body := `{
+		"specversion": "1.0",
+		"id": "A234-1234-1234",
+		"source": "/mycontext/orders",
+		"type": "com.example.order.created",
+		"subject": "order-1",
+		"time": "2024-04-05T17:31:00Z",
+		"datacontenttype": "application/json",
+		"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"data": {"order_id": 1}
+	}`
+	r := httptest.NewRequest("POST", "/msg/cloudevents", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/cloudevents+json; charset=utf-8")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	gt.Equal(t, w.Code, http.StatusOK)
+
+	gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+		Ctx context.Context
+		Msg model.Message
+	}) {
+		gt.Equal(t, v.Msg.Source, "/mycontext/orders")
+		gt.Equal(t, v.Msg.Schema, "com.example.order.created")
+		gt.Equal(t, v.Msg.CloudEvent.ID, "A234-1234-1234")
+		gt.Equal(t, v.Msg.CloudEvent.Subject, "order-1")
+		gt.Equal(t, *v.Msg.CloudEvent.Time, time.Date(2024, 4, 5, 17, 31, 0, 0, time.UTC))
+		gt.Equal(t, v.Msg.CloudEvent.Extensions["traceparent"], any("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"))
+
+		data := gt.Cast[map[string]any](t, v.Msg.Data)
+		gt.Equal(t, data["order_id"], any(float64(1)))
+	})
+}
+
+func TestCloudEventsBatch(t *testing.T) {
+	uc := newCloudEventsUseCases()
+	srv := server.New(uc)
+
+	body := `[
+		{"specversion": "1.0", "id": "1", "source": "/a", "type": "com.example.a", "data": "hello"},
+		{"specversion": "1.0", "id": "2", "source": "/b", "type": "com.example.b", "datacontenttype": "application/json", "data_base64": "eyJrIjoidiJ9"}
+	]`
+	r := httptest.NewRequest("POST", "/msg/cloudevents", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/cloudevents-batch+json")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	gt.Equal(t, w.Code, http.StatusOK)
+
+	calls := uc.RouteCalls()
+	gt.A(t, calls).Length(2)
+	gt.Equal(t, calls[0].Msg.Schema, "com.example.a")
+	gt.Equal(t, calls[0].Msg.Data, any("hello"))
+	gt.Equal(t, calls[1].Msg.Source, "/b")
+	gt.Equal(t, calls[1].Msg.Data, any(map[string]any{"k": "v"}))
+}
+
+func TestCloudEventsBatchDenied(t *testing.T) {
+	// Authorization of the second event is denied
+	var authorized int
+	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			authorized++
+			if authorized == 2 {
+				return goerr.New("denied", goerr.T(types.ErrTagForbidden))
+			}
+			return nil
+		},
+		RouteFunc: func(ctx context.Context, msg model.Message) error {
+			return nil
+		},
+	}
+	srv := server.New(uc)
+
+	body := `[
+		{"specversion": "1.0", "id": "1", "source": "/a", "type": "com.example.a"},
+		{"specversion": "1.0", "id": "2", "source": "/a", "type": "com.example.a"}
+	]`
+	r := httptest.NewRequest("POST", "/msg/cloudevents", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/cloudevents-batch+json")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	gt.Equal(t, w.Code, http.StatusForbidden)
+	gt.A(t, uc.AuthorizeCalls()).Length(2)
+	gt.A(t, uc.RouteCalls()).Length(0)
+}
+
+func TestCloudEventsBatchRetry(t *testing.T) {
+	failed := true
+	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			return nil
+		},
+		RouteFunc: func(ctx context.Context, msg model.Message) error {
+			if msg.CloudEvent.ID == "2" && failed {
+				return errors.New("policy error")
+			}
+			return nil
+		},
+	}
+	srv := server.New(uc)
+
+	send := func() int {
+		body := `[
+			{"specversion": "1.0", "id": "1", "source": "/a", "type": "com.example.a"},
+			{"specversion": "1.0", "id": "2", "source": "/a", "type": "com.example.a"},
+			{"specversion": "1.0", "id": "1", "source": "/a", "type": "com.example.a"},
+			{"specversion": "1.0", "id": "1", "source": "/b", "type": "com.example.a"}
+		]`
+		r := httptest.NewRequest("POST", "/msg/cloudevents", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/cloudevents-batch+json")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Failure of an event does not stop others, and duplicated id of the same source is routed once
+	gt.Equal(t, send(), http.StatusInternalServerError)
+	calls := uc.RouteCalls()
+	gt.A(t, calls).Length(3)
+	gt.Equal(t, calls[2].Msg.Source, "/b")
+
+	// Retry routes only the failed event
+	failed = false
+	gt.Equal(t, send(), http.StatusOK)
+	calls = uc.RouteCalls()
+	gt.A(t, calls).Length(4)
+	gt.Equal(t, calls[3].Msg.CloudEvent.ID, "2")
+
+	// Events routed already are not routed again
+	gt.Equal(t, send(), http.StatusOK)
+	gt.A(t, uc.RouteCalls()).Length(4)
+}
+
+func TestCloudEventsBinary(t *testing.T) {
+	uc := newCloudEventsUseCases()
+	srv := server.New(uc)
+
+	r := httptest.NewRequest("POST", "/msg/cloudevents", strings.NewReader(`{"order_id": 1}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("ce-specversion", "1.0")
+	r.Header.Set("ce-id", "A234-1234-1234")
+	r.Header.Set("ce-source", "/mycontext/orders")
+	r.Header.Set("ce-type", "com.example.order.created")
+	r.Header.Set("ce-time", "2024-04-05T17:31:00.123Z")
+	r.Header.Set("ce-partitionkey", "tenant-1")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	gt.Equal(t, w.Code, http.StatusOK)
+
+	gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+		Ctx context.Context
+		Msg model.Message
+	}) {
+		gt.Equal(t, v.Msg.Source, "/mycontext/orders")
+		gt.Equal(t, v.Msg.Schema, "com.example.order.created")
+		gt.Equal(t, v.Msg.CloudEvent.DataContentType, "application/json")
+		gt.Equal(t, v.Msg.CloudEvent.Time.UnixMilli(), time.Date(2024, 4, 5, 17, 31, 0, 123000000, time.UTC).UnixMilli())
+		gt.Equal(t, v.Msg.CloudEvent.Extensions["partitionkey"], any("tenant-1"))
+
+		data := gt.Cast[map[string]any](t, v.Msg.Data)
+		gt.Equal(t, data["order_id"], any(float64(1)))
+	})
+}
+
+func TestCloudEventsInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		contentType string
+		header      map[string]string
+		body        string
+	}{
+		"binary without specversion": {
+			contentType: "application/json",
+			body:        `{}`,
+		},
+		"unsupported specversion": {
+			contentType: "application/cloudevents+json",
+			body:        `{"specversion": "0.3", "id": "1", "source": "/a", "type": "t"}`,
+		},
+		"missing type": {
+			contentType: "application/cloudevents+json",
+			body:        `{"specversion": "1.0", "id": "1", "source": "/a"}`,
+		},
+		"invalid time": {
+			contentType: "application/cloudevents+json",
+			body:        `{"specversion": "1.0", "id": "1", "source": "/a", "type": "t", "time": "yesterday"}`,
+		},
+		"invalid event in batch": {
+			contentType: "application/cloudevents-batch+json",
+			body:        `[{"specversion": "1.0", "id": "1", "source": "/a", "type": "t"}, {"specversion": "1.0"}]`,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{}
+			srv := server.New(uc)
+
+			r := httptest.NewRequest("POST", "/msg/cloudevents", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+			gt.Equal(t, w.Code, http.StatusBadRequest)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
+
+func TestCloudEventsValidation(t *testing.T) {
+	srv := server.New(&mock.UseCasesMock{})
+
+	r := httptest.NewRequest("OPTIONS", "/msg/cloudevents", nil)
+	r.Header.Set("WebHook-Request-Origin", "eventgrid.azure.net")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	gt.Equal(t, w.Code, http.StatusOK)
+	gt.Equal(t, w.Header().Get("WebHook-Allowed-Origin"), "eventgrid.azure.net")
+}
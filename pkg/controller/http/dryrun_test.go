@@ -0,0 +1,64 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/utils/dryrun"
+)
+
+func TestDryRunResponse(t *testing.T) {
+	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			return nil
+		},
+		RouteFunc: func(ctx context.Context, msg model.Message) error {
+			dryrun.Extract(ctx).Add(model.DryRunDelivery{
+				Destination: "slack",
+				Payload:     map[string]any{"channel": "#general"},
+			})
+			return nil
+		},
+	}
+
+	testCases := map[string]struct {
+		options []server.Option
+		check   func(t *testing.T, body []byte)
+	}{
+		"dry-run": {
+			options: []server.Option{server.WithDryRun()},
+			check: func(t *testing.T, body []byte) {
+				var deliveries []model.DryRunDelivery
+				gt.NoError(t, json.Unmarshal(body, &deliveries))
+				gt.A(t, deliveries).Length(1)
+				gt.Equal(t, deliveries[0].Destination, "slack")
+			},
+		},
+		"normal": {
+			check: func(t *testing.T, body []byte) {
+				gt.Equal(t, string(body), "OK")
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			srv := server.New(uc, tc.options...)
+
+			r := httptest.NewRequest("POST", "/msg/pubsub/json_schema", bytes.NewReader(pubsubJSON))
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+
+			gt.Equal(t, w.Code, http.StatusOK)
+			tc.check(t, w.Body.Bytes())
+		})
+	}
+}
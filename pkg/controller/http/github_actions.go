@@ -90,7 +90,7 @@ func handleGitHubActions(r *http.Request, uc interfaces.UseCases) error {
 		},
 	}
 
-	if err := uc.Route(ctx, msg); err != nil {
+	if err := routeMessage(r, uc, msg); err != nil {
 		return goerr.Wrap(err, "failed to route message", goerr.V("msg", msg))
 	}
 
@@ -59,7 +59,7 @@ func handleGitHubWebhook(r *http.Request, uc interfaces.UseCases, secret string)
 		msg.Auth.GitHub.Webhook.TargetType = v
 	}
 
-	if err := uc.Route(r.Context(), msg); err != nil {
+	if err := routeMessage(r, uc, msg); err != nil {
 		return goerr.Wrap(err, "Failed to route message")
 	}
 
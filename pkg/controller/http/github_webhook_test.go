@@ -85,6 +85,9 @@ func TestHandleGitHubWebhook(t *testing.T) {
 			}
 
 			mockUC := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					return nil
+				},
 				RouteFunc: func(ctx context.Context, msg model.Message) error {
 					return nil
 				},
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+)
+
+// gitlabSignatureTolerance is acceptable difference between webhook-timestamp header and current time.
+const gitlabSignatureTolerance = 5 * time.Minute
+
+// handleGitLabWebhook routes GitLab webhook. The webhook is validated by secret token in X-Gitlab-Token header and/or signing token, if they are configured.
+func handleGitLabWebhook(r *http.Request, uc interfaces.UseCases, secretToken, signingToken string) error {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		return goerr.Wrap(err, "Unable to read request body")
+	}
+
+	if secretToken != "" {
+		token := r.Header.Get("X-Gitlab-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secretToken)) != 1 {
+			return goerr.New("Invalid X-Gitlab-Token", goerr.T(types.ErrTagUnauthorized))
+		}
+	}
+	if signingToken != "" {
+		if err := verifyGitLabSignature(r.Header, payload, signingToken, time.Now()); err != nil {
+			return goerr.Wrap(err, "Failed to verify GitLab webhook signature", goerr.T(types.ErrTagUnauthorized))
+		}
+	}
+
+	var data any
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return goerr.Wrap(err, "Failed to unmarshal GitLab webhook payload", goerr.V("payload", string(payload)), goerr.T(types.ErrTagBadRequest))
+	}
+
+	msg := model.Message{
+		Source: "gitlab.webhook",
+		Schema: r.Header.Get("X-Gitlab-Event"),
+		Data:   data,
+		Body:   payload,
+		Auth: model.AuthContext{
+			GitLab: &model.AuthContextGitLab{
+				Instance:    r.Header.Get("X-Gitlab-Instance"),
+				EventUUID:   r.Header.Get("X-Gitlab-Event-UUID"),
+				WebhookUUID: r.Header.Get("X-Gitlab-Webhook-UUID"),
+				Valid:       secretToken != "" || signingToken != "",
+			},
+		},
+	}
+
+	if err := routeMessage(r, uc, msg); err != nil {
+		return goerr.Wrap(err, "Failed to route message")
+	}
+
+	return nil
+}
+
+// verifyGitLabSignature verifies signature of GitLab webhook signing token, that follows Standard Webhooks. The signature is HMAC-SHA256 of "{webhook-id}.{webhook-timestamp}.{body}" with base64 decoded key of "whsec_" prefixed token.
+func verifyGitLabSignature(header http.Header, payload []byte, signingToken string, now time.Time) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(signingToken, "whsec_"))
+	if err != nil {
+		return goerr.Wrap(err, "GitLab signing token must be base64 encoded")
+	}
+
+	id := header.Get("webhook-id")
+	timestamp := header.Get("webhook-timestamp")
+	signatures := header.Get("webhook-signature")
+	if id == "" || timestamp == "" || signatures == "" {
+		return goerr.New("webhook-id, webhook-timestamp and webhook-signature headers are required")
+	}
+
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return goerr.Wrap(err, "Invalid webhook-timestamp", goerr.V("timestamp", timestamp))
+	}
+	if diff := now.Sub(time.Unix(sec, 0)); diff > gitlabSignatureTolerance || diff < -gitlabSignatureTolerance {
+		return goerr.New("webhook-timestamp is too old or too new", goerr.V("timestamp", timestamp))
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(id + "." + timestamp + "."))
+	_, _ = mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	// Multiple signatures are separated by space while rotating the token
+	for _, sig := range strings.Fields(signatures) {
+		version, value, ok := strings.Cut(sig, ",")
+		if !ok || version != "v1" {
+			continue
+		}
+		actual, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(actual, expected) {
+			return nil
+		}
+	}
+
+	return goerr.New("No valid signature in webhook-signature")
+}
@@ -0,0 +1,184 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+const (
+	gitlabPayload      = `{"object_kind":"push","ref":"refs/heads/main","project":{"path_with_namespace":"group/project"}}`
+	gitlabSigningToken = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+)
+
+func signGitLabWebhook(r *http.Request, token, body string, ts time.Time) {
+	key, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(token, "whsec_"))
+	id := "msg_2LJ8sWgY6cH8z9"
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(id + "." + timestamp + "." + body))
+	r.Header.Set("webhook-id", id)
+	r.Header.Set("webhook-timestamp", timestamp)
+	// The first one is signature of the rotated old token
+	r.Header.Set("webhook-signature", "v1,aW52YWxpZA== v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+func newGitLabRequest(header map[string]string) *http.Request {
+	r := httptest.NewRequest("POST", "/msg/gitlab/webhook", strings.NewReader(gitlabPayload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Gitlab-Event", "Push Hook")
+	r.Header.Set("X-Gitlab-Instance", "https://gitlab.example.com")
+	r.Header.Set("X-Gitlab-Event-UUID", "13792a34-cac6-4fda-95a8-c58e00a3954e")
+	r.Header.Set("X-Gitlab-Webhook-UUID", "6e7b2c1d-8a2f-4a3e-9b0c-5d4f3e2a1b0c")
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestGitLabWebhook(t *testing.T) {
+	testCases := map[string]struct {
+		options []server.Option
+		req     func() *http.Request
+		valid   bool
+	}{
+		"secret token": {
+			options: []server.Option{server.WithGitLabWebhookToken("my-token")},
+			req: func() *http.Request {
+				return newGitLabRequest(map[string]string{"X-Gitlab-Token": "my-token"})
+			},
+			valid: true,
+		},
+		"signing token": {
+			options: []server.Option{server.WithGitLabSigningToken(gitlabSigningToken)},
+			req: func() *http.Request {
+				r := newGitLabRequest(nil)
+				signGitLabWebhook(r, gitlabSigningToken, gitlabPayload, time.Now())
+				return r
+			},
+			valid: true,
+		},
+		"no token configured": {
+			req: func() *http.Request {
+				return newGitLabRequest(nil)
+			},
+			valid: false,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					return nil
+				},
+				RouteFunc: func(ctx context.Context, msg model.Message) error {
+					return nil
+				},
+			}
+			srv := server.New(uc, tc.options...)
+
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, tc.req())
+			gt.Equal(t, w.Code, http.StatusOK)
+
+			gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx context.Context
+				Msg model.Message
+			}) {
+				gt.Equal(t, v.Msg.Source, "gitlab.webhook")
+				gt.Equal(t, v.Msg.Schema, "Push Hook")
+				data := gt.Cast[map[string]any](t, v.Msg.Data)
+				gt.Equal(t, data["object_kind"], "push")
+
+				gt.Equal(t, v.Msg.Auth.GitLab.Instance, "https://gitlab.example.com")
+				gt.Equal(t, v.Msg.Auth.GitLab.EventUUID, "13792a34-cac6-4fda-95a8-c58e00a3954e")
+				gt.Equal(t, v.Msg.Auth.GitLab.WebhookUUID, "6e7b2c1d-8a2f-4a3e-9b0c-5d4f3e2a1b0c")
+				gt.Equal(t, v.Msg.Auth.GitLab.Valid, tc.valid)
+			})
+		})
+	}
+}
+
+func TestGitLabWebhookRejected(t *testing.T) {
+	testCases := map[string]struct {
+		options []server.Option
+		req     func() *http.Request
+		code    int
+	}{
+		"wrong secret token": {
+			options: []server.Option{server.WithGitLabWebhookToken("my-token")},
+			req: func() *http.Request {
+				return newGitLabRequest(map[string]string{"X-Gitlab-Token": "other"})
+			},
+			code: http.StatusUnauthorized,
+		},
+		"missing secret token": {
+			options: []server.Option{server.WithGitLabWebhookToken("my-token")},
+			req: func() *http.Request {
+				return newGitLabRequest(nil)
+			},
+			code: http.StatusUnauthorized,
+		},
+		"wrong signing token": {
+			options: []server.Option{server.WithGitLabSigningToken(gitlabSigningToken)},
+			req: func() *http.Request {
+				r := newGitLabRequest(nil)
+				signGitLabWebhook(r, "whsec_b3RoZXIta2V5", gitlabPayload, time.Now())
+				return r
+			},
+			code: http.StatusUnauthorized,
+		},
+		"tampered body": {
+			options: []server.Option{server.WithGitLabSigningToken(gitlabSigningToken)},
+			req: func() *http.Request {
+				r := newGitLabRequest(nil)
+				signGitLabWebhook(r, gitlabSigningToken, `{"object_kind":"tag_push"}`, time.Now())
+				return r
+			},
+			code: http.StatusUnauthorized,
+		},
+		"old timestamp": {
+			options: []server.Option{server.WithGitLabSigningToken(gitlabSigningToken)},
+			req: func() *http.Request {
+				r := newGitLabRequest(nil)
+				signGitLabWebhook(r, gitlabSigningToken, gitlabPayload, time.Now().Add(-time.Hour))
+				return r
+			},
+			code: http.StatusUnauthorized,
+		},
+		"invalid JSON": {
+			req: func() *http.Request {
+				r := newGitLabRequest(nil)
+				r.Body = http.NoBody
+				return r
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{}
+			srv := server.New(uc, tc.options...)
+
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, tc.req())
+			gt.Equal(t, w.Code, tc.code)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
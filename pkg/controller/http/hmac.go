@@ -0,0 +1,178 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 some services still sign webhook with HMAC-SHA1
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+)
+
+// handleHMACMessage routes message signed with HMAC. The signature is verified by the profile in path, and the result is set to Auth.HMAC instead of rejecting the request.
+func handleHMACMessage(r *http.Request, uc interfaces.UseCases, profiles map[string]model.HMACProfile) error {
+	name := r.PathValue("profile")
+	profile, ok := profiles[name]
+	if !ok {
+		return goerr.New("HMAC profile not found", goerr.V("profile", name), goerr.T(types.ErrTagNotFound))
+	}
+
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return goerr.Wrap(err, "Unable to read request body")
+	}
+
+	msg := model.Message{
+		Source: "hmac",
+		Schema: r.PathValue("schema"),
+		Header: cloneHeader(r.Header),
+		Body:   raw,
+		Data:   string(raw),
+		Auth: model.AuthContext{
+			HMAC: verifyHMAC(profile, r.Header, raw, time.Now()),
+		},
+	}
+	msg.Auth.HMAC.Profile = name
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType == "application/json" {
+		var data any
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return goerr.Wrap(err, "Failed to unmarshal JSON", goerr.V("data", string(raw)), goerr.T(types.ErrTagBadRequest))
+		}
+		msg.Data = data
+	}
+
+	if err := routeMessage(r, uc, msg); err != nil {
+		return goerr.Wrap(err, "Failed to route message")
+	}
+
+	return nil
+}
+
+var hmacTemplateVar = regexp.MustCompile(`\{(body|timestamp|header:[^}]+)\}`)
+
+// verifyHMAC verifies signature of the request by the profile. Reason is set if the signature is not valid.
+func verifyHMAC(profile model.HMACProfile, header http.Header, body []byte, now time.Time) *model.AuthContextHMAC {
+	result := &model.AuthContextHMAC{}
+	invalid := func(reason string) *model.AuthContextHMAC {
+		result.Reason = reason
+		return result
+	}
+
+	signatures := extractHMACValues(header.Get(profile.Header), profile.SignatureKey)
+	if len(signatures) == 0 {
+		return invalid("signature not found")
+	}
+
+	var timestamp string
+	if profile.TimestampHeader != "" || profile.TimestampKey != "" {
+		name := profile.TimestampHeader
+		if name == "" {
+			name = profile.Header
+		}
+		values := extractHMACValues(header.Get(name), profile.TimestampKey)
+		if len(values) == 0 {
+			return invalid("timestamp not found")
+		}
+		timestamp = values[0]
+
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return invalid("timestamp is not integer")
+		}
+		// Timestamp in milliseconds, e.g. Linear
+		if ts > 1e12 {
+			ts /= 1000
+		}
+		result.Timestamp = ts
+
+		if profile.MaxSkew > 0 {
+			if diff := now.Sub(time.Unix(ts, 0)); diff > profile.MaxSkew || diff < -profile.MaxSkew {
+				return invalid("timestamp is out of max skew")
+			}
+		}
+	}
+
+	template := profile.Template
+	if template == "" {
+		template = "{body}"
+	}
+	content := hmacTemplateVar.ReplaceAllStringFunc(template, func(v string) string {
+		switch name := v[1 : len(v)-1]; {
+		case name == "body":
+			return string(body)
+		case name == "timestamp":
+			return timestamp
+		default:
+			return header.Get(strings.TrimPrefix(name, "header:"))
+		}
+	})
+
+	var newHash func() hash.Hash
+	switch profile.Algorithm {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return invalid("unsupported algorithm")
+	}
+	mac := hmac.New(newHash, []byte(profile.Secret))
+	_, _ = mac.Write([]byte(content))
+	expected := mac.Sum(nil)
+
+	for _, sig := range signatures {
+		sig = strings.TrimPrefix(sig, profile.Prefix)
+
+		var actual []byte
+		var err error
+		switch profile.Encoding {
+		case "hex":
+			actual, err = hex.DecodeString(sig)
+		case "base64":
+			actual, err = base64.StdEncoding.DecodeString(sig)
+		}
+		if err == nil && hmac.Equal(actual, expected) {
+			result.Valid = true
+			return result
+		}
+	}
+
+	return invalid("signature mismatch")
+}
+
+// extractHMACValues returns values of the key in comma separated key=value pairs. If key is empty, the whole header value is returned.
+func extractHMACValues(value, key string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if key == "" {
+		return []string{value}
+	}
+
+	var values []string
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && k == key {
+			values = append(values, v)
+		}
+	}
+	return values
+}
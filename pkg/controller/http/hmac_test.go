@@ -0,0 +1,180 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 HMAC-SHA1 profile is tested
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+const hmacBody = `{"id":"evt_1","type":"invoice.paid"}`
+
+func hmacSign(newHash func() hash.Hash, secret, content string) []byte {
+	mac := hmac.New(newHash, []byte(secret))
+	_, _ = mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
+
+var hmacProfiles = map[string]model.HMACProfile{
+	"stripe": {
+		Secret:       "whsec_stripe",
+		Header:       "Stripe-Signature",
+		SignatureKey: "v1",
+		TimestampKey: "t",
+		Algorithm:    "sha256",
+		Encoding:     "hex",
+		Template:     "{timestamp}.{body}",
+		MaxSkew:      5 * time.Minute,
+	},
+	"shopify": {
+		Secret:    "shopify-secret",
+		Header:    "X-Shopify-Hmac-Sha256",
+		Algorithm: "sha256",
+		Encoding:  "base64",
+	},
+	"sentry": {
+		Secret:          "sentry-secret",
+		Header:          "Sentry-Hook-Signature",
+		Algorithm:       "sha512",
+		Encoding:        "hex",
+		TimestampHeader: "Sentry-Hook-Timestamp",
+		Template:        "{header:Sentry-Hook-Resource}:{body}",
+	},
+	"legacy": {
+		Secret:    "legacy-secret",
+		Header:    "X-Hub-Signature",
+		Prefix:    "sha1=",
+		Algorithm: "sha1",
+		Encoding:  "hex",
+	},
+}
+
+func TestHMACMessage(t *testing.T) {
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	old := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+
+	testCases := map[string]struct {
+		profile string
+		header  map[string]string
+		valid   bool
+		reason  string
+	}{
+		"stripe": {
+			profile: "stripe",
+			header: map[string]string{
+				"Stripe-Signature": "t=" + ts + ",v1=" + hex.EncodeToString(hmacSign(sha256.New, "whsec_old", ts+"."+hmacBody)) +
+					",v1=" + hex.EncodeToString(hmacSign(sha256.New, "whsec_stripe", ts+"."+hmacBody)),
+			},
+			valid: true,
+		},
+		"stripe with old timestamp": {
+			profile: "stripe",
+			header: map[string]string{
+				"Stripe-Signature": "t=" + old + ",v1=" + hex.EncodeToString(hmacSign(sha256.New, "whsec_stripe", old+"."+hmacBody)),
+			},
+			valid:  false,
+			reason: "timestamp is out of max skew",
+		},
+		"shopify": {
+			profile: "shopify",
+			header: map[string]string{
+				"X-Shopify-Hmac-Sha256": base64.StdEncoding.EncodeToString(hmacSign(sha256.New, "shopify-secret", hmacBody)),
+			},
+			valid: true,
+		},
+		"sentry with millisecond timestamp and header in template": {
+			profile: "sentry",
+			header: map[string]string{
+				"Sentry-Hook-Resource":  "issue",
+				"Sentry-Hook-Timestamp": strconv.FormatInt(now.UnixMilli(), 10),
+				"Sentry-Hook-Signature": hex.EncodeToString(hmacSign(sha512.New, "sentry-secret", "issue:"+hmacBody)),
+			},
+			valid: true,
+		},
+		"legacy with prefix": {
+			profile: "legacy",
+			header: map[string]string{
+				"X-Hub-Signature": "sha1=" + hex.EncodeToString(hmacSign(sha1.New, "legacy-secret", hmacBody)),
+			},
+			valid: true,
+		},
+		"wrong secret": {
+			profile: "shopify",
+			header: map[string]string{
+				"X-Shopify-Hmac-Sha256": base64.StdEncoding.EncodeToString(hmacSign(sha256.New, "other", hmacBody)),
+			},
+			valid:  false,
+			reason: "signature mismatch",
+		},
+		"no signature": {
+			profile: "shopify",
+			valid:   false,
+			reason:  "signature not found",
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					gt.Equal(t, input.Auth.HMAC.Valid, tc.valid)
+					return nil
+				},
+				RouteFunc: func(ctx context.Context, msg model.Message) error {
+					return nil
+				},
+			}
+			srv := server.New(uc, server.WithHMACProfiles(hmacProfiles))
+
+			r := httptest.NewRequest("POST", "/msg/hmac/"+tc.profile+"/billing", strings.NewReader(hmacBody))
+			r.Header.Set("Content-Type", "application/json; charset=utf-8")
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+			gt.Equal(t, w.Code, http.StatusOK)
+
+			gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx context.Context
+				Msg model.Message
+			}) {
+				gt.Equal(t, v.Msg.Source, "hmac")
+				gt.Equal(t, v.Msg.Schema, "billing")
+				gt.Equal(t, v.Msg.Auth.HMAC.Profile, tc.profile)
+				gt.Equal(t, v.Msg.Auth.HMAC.Valid, tc.valid)
+				gt.Equal(t, v.Msg.Auth.HMAC.Reason, tc.reason)
+
+				data := gt.Cast[map[string]any](t, v.Msg.Data)
+				gt.Equal(t, data["type"], "invoice.paid")
+			})
+		})
+	}
+}
+
+func TestHMACMessageUnknownProfile(t *testing.T) {
+	uc := &mock.UseCasesMock{}
+	srv := server.New(uc, server.WithHMACProfiles(hmacProfiles))
+
+	r := httptest.NewRequest("POST", "/msg/hmac/unknown/billing", strings.NewReader(hmacBody))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+	gt.Equal(t, w.Code, http.StatusNotFound)
+	gt.A(t, uc.RouteCalls()).Length(0)
+}
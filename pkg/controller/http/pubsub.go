@@ -88,7 +88,7 @@ func handlePubSubMessage(r *http.Request, uc interfaces.UseCases) error {
 		}
 	}
 
-	if err := uc.Route(ctx, msg); err != nil {
+	if err := routeMessage(r, uc, msg); err != nil {
 		return err
 	}
 
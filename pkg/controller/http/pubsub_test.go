@@ -26,6 +26,9 @@ var pubsubJSON []byte
 
 func TestPubSubJSON(t *testing.T) {
 	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			return nil
+		},
 		RouteFunc: func(ctx context.Context, msg model.Message) error {
 			return nil
 		},
@@ -52,6 +55,9 @@ var pubsubText []byte
 
 func TestPubSubText(t *testing.T) {
 	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			return nil
+		},
 		RouteFunc: func(ctx context.Context, msg model.Message) error {
 			return nil
 		},
@@ -84,6 +90,9 @@ func TestPubSubAuth(t *testing.T) {
 	}
 
 	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			return nil
+		},
 		RouteFunc: func(ctx context.Context, msg model.Message) error {
 			return nil
 		},
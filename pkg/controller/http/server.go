@@ -7,7 +7,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
 	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/dryrun"
 	"github.com/m-mizutani/xroute/pkg/utils/logging"
 	"github.com/m-mizutani/xroute/pkg/utils/safe"
 )
@@ -15,6 +17,14 @@ import (
 type Server struct {
 	router              *chi.Mux
 	githubWebhookSecret string
+	gitlabWebhookToken  string
+	gitlabSigningToken  string
+	slackSigningSecret  string
+	hmacProfiles        map[string]model.HMACProfile
+	snsURLValidator     SNSURLValidator
+	adminToken          string
+	dryRun              bool
+	routed              *routedKeys
 }
 
 type Option func(*Server)
@@ -25,10 +35,61 @@ func WithGitHubWebhookSecret(secret string) Option {
 	}
 }
 
+// WithGitLabWebhookToken sets secret token of GitLab webhook, that is compared with X-Gitlab-Token header.
+func WithGitLabWebhookToken(token string) Option {
+	return func(s *Server) {
+		s.gitlabWebhookToken = token
+	}
+}
+
+// WithGitLabSigningToken sets signing token of GitLab webhook, that is "whsec_" prefixed key to verify webhook-signature header.
+func WithGitLabSigningToken(token string) Option {
+	return func(s *Server) {
+		s.gitlabSigningToken = token
+	}
+}
+
+// WithSlackSigningSecret enables /slack/interactions endpoint that receives payload of Slack interactive components, and verifies requests of it and /msg/slack/events with the signing secret.
+func WithSlackSigningSecret(secret string) Option {
+	return func(s *Server) {
+		s.slackSigningSecret = secret
+	}
+}
+
+// WithHMACProfiles sets signature schemes of /msg/hmac/{profile}/{schema} by profile name.
+func WithHMACProfiles(profiles map[string]model.HMACProfile) Option {
+	return func(s *Server) {
+		s.hmacProfiles = profiles
+	}
+}
+
+// WithSNSURLValidator replaces validator of SigningCertURL and SubscribeURL in SNS message. Default is DefaultSNSURLValidator.
+func WithSNSURLValidator(validator SNSURLValidator) Option {
+	return func(s *Server) {
+		s.snsURLValidator = validator
+	}
+}
+
+// WithAdminToken enables admin API under /admin. The API requires the token as Bearer token in Authorization header.
+func WithAdminToken(token string) Option {
+	return func(s *Server) {
+		s.adminToken = token
+	}
+}
+
+// WithDryRun makes /msg handlers respond payloads rendered in dry-run mode instead of "OK". UseCases also must be in dry-run mode.
+func WithDryRun() Option {
+	return func(s *Server) {
+		s.dryRun = true
+	}
+}
+
 func New(uc interfaces.UseCases, options ...Option) *Server {
 	r := chi.NewRouter()
 	server := &Server{
-		router: r,
+		router:          r,
+		snsURLValidator: DefaultSNSURLValidator,
+		routed:          newRoutedKeys(),
 	}
 
 	for _, opt := range options {
@@ -42,12 +103,16 @@ func New(uc interfaces.UseCases, options ...Option) *Server {
 	})
 
 	r.Route("/msg", func(r chi.Router) {
+		if server.dryRun {
+			r.Use(injectDryRunRecorder)
+		}
+
 		r.Post("/raw/{schema}", func(w http.ResponseWriter, r *http.Request) {
 			if err := handleRawMessage(r, uc); err != nil {
 				handleError(r.Context(), w, err)
 				return
 			}
-			safe.Write(r.Context(), w, []byte("OK"))
+			writeResult(w, r)
 		})
 
 		r.Post("/pubsub/{schema}", func(w http.ResponseWriter, r *http.Request) {
@@ -55,7 +120,62 @@ func New(uc interfaces.UseCases, options ...Option) *Server {
 				handleError(r.Context(), w, err)
 				return
 			}
-			safe.Write(r.Context(), w, []byte("OK"))
+			writeResult(w, r)
+		})
+
+		r.Post("/sns/{schema}", func(w http.ResponseWriter, r *http.Request) {
+			if err := handleSNSMessage(r, uc, server.snsURLValidator); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeResult(w, r)
+		})
+
+		r.Post("/hmac/{profile}/{schema}", func(w http.ResponseWriter, r *http.Request) {
+			if err := handleHMACMessage(r, uc, server.hmacProfiles); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeResult(w, r)
+		})
+
+		r.Post("/alertmanager/{schema}", func(w http.ResponseWriter, r *http.Request) {
+			if err := handleAlertmanager(r, uc, server.routed); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeResult(w, r)
+		})
+
+		r.Post("/cloudevents", func(w http.ResponseWriter, r *http.Request) {
+			if err := handleCloudEvents(r, uc, server.routed); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeResult(w, r)
+		})
+		r.Options("/cloudevents", handleCloudEventsValidation)
+
+		r.Post("/gitlab/webhook", func(w http.ResponseWriter, r *http.Request) {
+			if err := handleGitLabWebhook(r, uc, server.gitlabWebhookToken, server.gitlabSigningToken); err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			writeResult(w, r)
+		})
+
+		r.Post("/slack/events", func(w http.ResponseWriter, r *http.Request) {
+			challenge, err := handleSlackEvents(r, uc, server.slackSigningSecret)
+			if err != nil {
+				handleError(r.Context(), w, err)
+				return
+			}
+			if challenge != "" {
+				w.Header().Set("Content-Type", "text/plain")
+				safe.Write(r.Context(), w, []byte(challenge))
+				return
+			}
+			writeResult(w, r)
 		})
 
 		r.Route("/github", func(r chi.Router) {
@@ -64,7 +184,7 @@ func New(uc interfaces.UseCases, options ...Option) *Server {
 					handleError(r.Context(), w, err)
 					return
 				}
-				safe.Write(r.Context(), w, []byte("OK"))
+				writeResult(w, r)
 			})
 
 			r.Post("/actions", func(w http.ResponseWriter, r *http.Request) {
@@ -72,14 +192,53 @@ func New(uc interfaces.UseCases, options ...Option) *Server {
 					handleError(r.Context(), w, err)
 					return
 				}
-				safe.Write(r.Context(), w, []byte("OK"))
+				writeResult(w, r)
 			})
 		})
 	})
 
+	if server.slackSigningSecret != "" {
+		r.Route("/slack", func(r chi.Router) {
+			if server.dryRun {
+				r.Use(injectDryRunRecorder)
+			}
+
+			r.Post("/interactions", func(w http.ResponseWriter, r *http.Request) {
+				if err := handleSlackInteraction(r, uc, server.slackSigningSecret); err != nil {
+					handleError(r.Context(), w, err)
+					return
+				}
+				writeResult(w, r)
+			})
+		})
+	}
+
+	if server.adminToken != "" {
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authAdmin(server.adminToken))
+			adminRoutes(r, uc)
+		})
+	}
+
 	return server
 }
 
+func injectDryRunRecorder(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := dryrun.Inject(r.Context(), dryrun.New())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// writeResult writes response of successfully handled message.
+func writeResult(w http.ResponseWriter, r *http.Request) {
+	if recorder := dryrun.Extract(r.Context()); recorder != nil {
+		writeJSON(w, r, recorder.Deliveries())
+		return
+	}
+	safe.Write(r.Context(), w, []byte("OK"))
+}
+
 func handleError(ctx context.Context, w http.ResponseWriter, err error) {
 	logging.Extract(ctx).Error("Failed to handle request", "err", err)
 	code := http.StatusInternalServerError
@@ -88,6 +247,10 @@ func handleError(ctx context.Context, w http.ResponseWriter, err error) {
 		code = http.StatusBadRequest
 	case goerr.HasTag(err, types.ErrTagUnauthorized):
 		code = http.StatusUnauthorized
+	case goerr.HasTag(err, types.ErrTagForbidden):
+		code = http.StatusForbidden
+	case goerr.HasTag(err, types.ErrTagNotFound):
+		code = http.StatusNotFound
 	}
 	http.Error(w, err.Error(), code)
 }
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+// slackEventEnvelope is outer event of Slack Events API. Only fields required to build message are decoded.
+type slackEventEnvelope struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	TeamID    string `json:"team_id"`
+	APIAppID  string `json:"api_app_id"`
+	Event     struct {
+		Type string `json:"type"`
+		User string `json:"user"`
+	} `json:"event"`
+	Authorizations []struct {
+		UserID string `json:"user_id"`
+		IsBot  bool   `json:"is_bot"`
+	} `json:"authorizations"`
+}
+
+// handleSlackEvents routes event of Slack Events API. It returns challenge to be responded for url_verification request. If signing secret is empty, the request is not verified and Auth.Slack.Verified is false.
+func handleSlackEvents(r *http.Request, uc interfaces.UseCases, signingSecret string) (string, error) {
+	ctx := r.Context()
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", goerr.Wrap(err, "Unable to read request body")
+	}
+
+	var verified bool
+	if signingSecret != "" {
+		if err := verifySlackSignature(r.Header, raw, signingSecret); err != nil {
+			return "", err
+		}
+		verified = true
+	}
+
+	var envelope slackEventEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return "", goerr.Wrap(err, "Failed to unmarshal Slack event", goerr.V("body", string(raw)), goerr.T(types.ErrTagBadRequest))
+	}
+
+	switch envelope.Type {
+	case "url_verification":
+		if envelope.Challenge == "" {
+			return "", goerr.New("challenge of Slack url_verification is empty", goerr.T(types.ErrTagBadRequest))
+		}
+		return envelope.Challenge, nil
+
+	case "event_callback":
+		// Go on to route the event
+
+	default:
+		logging.Extract(ctx).Warn("Ignore unsupported type of Slack event", "type", envelope.Type)
+		return "", nil
+	}
+
+	// Messages posted by xroute itself are ignored, otherwise a policy routing channel messages to the same channel loops forever.
+	for _, authz := range envelope.Authorizations {
+		if authz.IsBot && envelope.Event.User != "" && authz.UserID == envelope.Event.User {
+			logging.Extract(ctx).Debug("Ignore Slack event caused by the app itself", "type", envelope.Event.Type, "user", envelope.Event.User)
+			return "", nil
+		}
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "", goerr.Wrap(err, "Failed to unmarshal Slack event", goerr.V("body", string(raw)), goerr.T(types.ErrTagBadRequest))
+	}
+
+	msg := model.Message{
+		Source: "slack.events",
+		Schema: envelope.Event.Type,
+		Header: cloneHeader(r.Header),
+		Body:   raw,
+		Data:   data,
+		Auth: model.AuthContext{
+			Slack: &model.AuthContextSlack{
+				TeamID:   envelope.TeamID,
+				AppID:    envelope.APIAppID,
+				Verified: verified,
+			},
+		},
+	}
+
+	if err := routeMessage(r, uc, msg); err != nil {
+		return "", goerr.Wrap(err, "Failed to route message")
+	}
+
+	return "", nil
+}
@@ -0,0 +1,140 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+const slackEventPayload = `{
+	"type": "event_callback",
+	"team_id": "T0123",
+	"api_app_id": "A0123",
+	"event_id": "Ev0123",
+	"event": {"type": "app_mention", "user": "U0123", "text": "<@UBOT> deploy", "channel": "C0123", "ts": "1700000000.000100"},
+	"authorizations": [{"team_id": "T0123", "user_id": "UBOT", "is_bot": true}]
+}`
+
+func newSlackEventRequest(body, secret string, ts time.Time) *http.Request {
+	r := httptest.NewRequest("POST", "/msg/slack/events", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write([]byte("v0:" + timestamp + ":" + body))
+		r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return r
+}
+
+func TestSlackEvents(t *testing.T) {
+	testCases := map[string]struct {
+		options  []server.Option
+		secret   string
+		verified bool
+	}{
+		"verified by signing secret": {
+			options:  []server.Option{server.WithSlackSigningSecret("my-secret")},
+			secret:   "my-secret",
+			verified: true,
+		},
+		"not verified without signing secret": {
+			verified: false,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					gt.Equal(t, input.Auth.Slack.Verified, tc.verified)
+					return nil
+				},
+				RouteFunc: func(ctx context.Context, msg model.Message) error {
+					return nil
+				},
+			}
+			srv := server.New(uc, tc.options...)
+
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, newSlackEventRequest(slackEventPayload, tc.secret, time.Now()))
+			gt.Equal(t, w.Code, http.StatusOK)
+
+			gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx context.Context
+				Msg model.Message
+			}) {
+				gt.Equal(t, v.Msg.Source, "slack.events")
+				gt.Equal(t, v.Msg.Schema, "app_mention")
+				gt.Equal(t, v.Msg.Auth.Slack.TeamID, "T0123")
+				gt.Equal(t, v.Msg.Auth.Slack.AppID, "A0123")
+				gt.Equal(t, v.Msg.Auth.Slack.Verified, tc.verified)
+
+				data := gt.Cast[map[string]any](t, v.Msg.Data)
+				event := gt.Cast[map[string]any](t, data["event"])
+				gt.Equal(t, event["text"], "<@UBOT> deploy")
+			})
+		})
+	}
+}
+
+func TestSlackEventsURLVerification(t *testing.T) {
+	uc := &mock.UseCasesMock{}
+	srv := server.New(uc, server.WithSlackSigningSecret("my-secret"))
+
+	body := `{"token": "x", "challenge": "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P", "type": "url_verification"}`
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, newSlackEventRequest(body, "my-secret", time.Now()))
+	gt.Equal(t, w.Code, http.StatusOK)
+	gt.Equal(t, w.Body.String(), "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P")
+	gt.A(t, uc.RouteCalls()).Length(0)
+}
+
+func TestSlackEventsRejected(t *testing.T) {
+	testCases := map[string]struct {
+		req  *http.Request
+		code int
+	}{
+		"invalid signature": {
+			req:  newSlackEventRequest(slackEventPayload, "other-secret", time.Now()),
+			code: http.StatusUnauthorized,
+		},
+		"timestamp skew": {
+			req:  newSlackEventRequest(slackEventPayload, "my-secret", time.Now().Add(10*time.Minute)),
+			code: http.StatusUnauthorized,
+		},
+		"no signature": {
+			req:  newSlackEventRequest(slackEventPayload, "", time.Now()),
+			code: http.StatusUnauthorized,
+		},
+		"event by the app itself": {
+			req:  newSlackEventRequest(strings.Replace(slackEventPayload, `"user": "U0123"`, `"user": "UBOT"`, 1), "my-secret", time.Now()),
+			code: http.StatusOK,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{}
+			srv := server.New(uc, server.WithSlackSigningSecret("my-secret"))
+
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, tc.req)
+			gt.Equal(t, w.Code, tc.code)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
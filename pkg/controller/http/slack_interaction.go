@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/slack-go/slack"
+)
+
+// handleSlackInteraction routes payload of Slack interactive components, e.g. click of a button. The request must be signed with the signing secret. Schema of the message is type of the interaction, e.g. "block_actions".
+func handleSlackInteraction(r *http.Request, uc interfaces.UseCases, signingSecret string) error {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return goerr.Wrap(err, "Unable to read request body")
+	}
+	if err := verifySlackSignature(r.Header, raw, signingSecret); err != nil {
+		return err
+	}
+
+	form, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return goerr.Wrap(err, "Failed to parse Slack interaction form", goerr.T(types.ErrTagBadRequest))
+	}
+	payload := form.Get("payload")
+	if payload == "" {
+		return goerr.New("payload of Slack interaction is empty", goerr.T(types.ErrTagBadRequest))
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		return goerr.Wrap(err, "Failed to unmarshal Slack interaction payload", goerr.V("payload", payload), goerr.T(types.ErrTagBadRequest))
+	}
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(payload), &callback); err != nil {
+		return goerr.Wrap(err, "Failed to parse Slack interaction payload", goerr.V("payload", payload), goerr.T(types.ErrTagBadRequest))
+	}
+
+	msg := model.Message{
+		Source: "slack.interaction",
+		Schema: string(callback.Type),
+		Header: cloneHeader(r.Header),
+		Body:   payload,
+		Data:   data,
+		Auth: model.AuthContext{
+			Slack: &model.AuthContextSlack{
+				TeamID:   callback.Team.ID,
+				AppID:    callback.APIAppID,
+				UserID:   callback.User.ID,
+				UserName: callback.User.Name,
+				Verified: true,
+			},
+		},
+	}
+
+	if err := routeMessage(r, uc, msg); err != nil {
+		return goerr.Wrap(err, "Failed to route message")
+	}
+
+	return nil
+}
+
+// verifySlackSignature verifies X-Slack-Signature with the signing secret. Request with timestamp older than 5 minutes is rejected to prevent replay.
+func verifySlackSignature(header http.Header, body []byte, signingSecret string) error {
+	verifier, err := slack.NewSecretsVerifier(header, signingSecret)
+	if err != nil {
+		return goerr.Wrap(err, "Invalid Slack signature header", goerr.T(types.ErrTagUnauthorized))
+	}
+	if _, err := verifier.Write(body); err != nil {
+		return goerr.Wrap(err, "Failed to verify Slack signature")
+	}
+	if err := verifier.Ensure(); err != nil {
+		return goerr.Wrap(err, "Failed to verify Slack signature", goerr.T(types.ErrTagUnauthorized))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package http_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+const slackInteractionPayload = `{
+	"type": "block_actions",
+	"team": {"id": "T0123", "domain": "example"},
+	"user": {"id": "U0123", "name": "alice"},
+	"container": {"type": "message", "channel_id": "C0123", "message_ts": "1700000000.000100"},
+	"actions": [{"type": "button", "action_id": "ack", "value": "incident-1"}]
+}`
+
+func newSlackInteractionRequest(secret string, ts time.Time) *http.Request {
+	body := url.Values{"payload": {slackInteractionPayload}}.Encode()
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	r := httptest.NewRequest("POST", "/slack/interactions", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestSlackInteraction(t *testing.T) {
+	uc := &mock.UseCasesMock{
+		AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+			gt.Equal(t, input.Path, "/slack/interactions")
+			gt.Equal(t, input.Auth.Slack.TeamID, "T0123")
+			return nil
+		},
+		RouteFunc: func(ctx context.Context, msg model.Message) error {
+			return nil
+		},
+	}
+	srv := server.New(uc, server.WithSlackSigningSecret("my-secret"))
+
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, newSlackInteractionRequest("my-secret", time.Now()))
+	gt.Equal(t, w.Code, http.StatusOK)
+
+	gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+		Ctx context.Context
+		Msg model.Message
+	}) {
+		gt.Equal(t, v.Msg.Source, "slack.interaction")
+		gt.Equal(t, v.Msg.Schema, "block_actions")
+		gt.Equal(t, v.Msg.Auth.Slack.UserID, "U0123")
+		gt.Equal(t, v.Msg.Auth.Slack.UserName, "alice")
+
+		data := gt.Cast[map[string]any](t, v.Msg.Data)
+		container := gt.Cast[map[string]any](t, data["container"])
+		gt.Equal(t, container["message_ts"], "1700000000.000100")
+	})
+}
+
+func TestSlackInteractionInvalidRequest(t *testing.T) {
+	testCases := map[string]struct {
+		options []server.Option
+		req     *http.Request
+		code    int
+	}{
+		"invalid signature": {
+			options: []server.Option{server.WithSlackSigningSecret("my-secret")},
+			req:     newSlackInteractionRequest("other-secret", time.Now()),
+			code:    http.StatusUnauthorized,
+		},
+		"expired timestamp": {
+			options: []server.Option{server.WithSlackSigningSecret("my-secret")},
+			req:     newSlackInteractionRequest("my-secret", time.Now().Add(-time.Hour)),
+			code:    http.StatusUnauthorized,
+		},
+		"disabled without signing secret": {
+			req:  newSlackInteractionRequest("", time.Now()),
+			code: http.StatusNotFound,
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			uc := &mock.UseCasesMock{}
+			srv := server.New(uc, tc.options...)
+
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, tc.req)
+			gt.Equal(t, w.Code, tc.code)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
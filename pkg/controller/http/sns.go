@@ -1,9 +1,31 @@
 package http
 
-/*
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
 type snsMessage struct {
 	Type             string `json:"Type"`
 	MessageId        string `json:"MessageId"`
+	Token            string `json:"Token"`
 	TopicArn         string `json:"TopicArn"`
 	Subject          string `json:"Subject"`
 	Message          string `json:"Message"`
@@ -11,61 +33,314 @@ type snsMessage struct {
 	SignatureVersion string `json:"SignatureVersion"`
 	Signature        string `json:"Signature"`
 	SigningCertURL   string `json:"SigningCertURL"`
-	UnsubscribeURL   string `json:"UnsubscribeURL"`
 	SubscribeURL     string `json:"SubscribeURL"`
+	UnsubscribeURL   string `json:"UnsubscribeURL"`
 }
 
-func handleSNSSchema(r *http.Request, uc *usecase.UseCase) error {
-	ctx := r.Context()
-	if r.Method != http.MethodPost {
-		return goerr.New("Invalid HTTP method", goerr.V("method", r.Method))
+// SNSURLValidator checks URL given by SNS message, SigningCertURL and SubscribeURL. It should return error if the URL is not acceptable.
+type SNSURLValidator func(u *url.URL) error
+
+// snsHostPattern matches regional SNS endpoints only. Other amazonaws.com hosts such as S3 buckets can be owned by anyone.
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// DefaultSNSURLValidator accepts only HTTPS URL of SNS endpoint.
+func DefaultSNSURLValidator(u *url.URL) error {
+	if u.Scheme != "https" || !snsHostPattern.MatchString(u.Hostname()) {
+		return goerr.New("unacceptable SNS URL", goerr.V("url", u.String()))
+	}
+	return nil
+}
+
+// snsMessageTolerance is acceptable difference between Timestamp of SNS message and current time. Message captured once is rejected after it, and it covers retries by the default delivery policy of SNS.
+const snsMessageTolerance = 15 * time.Minute
+
+// snsHTTPClient is used to fetch signing certificate and to confirm subscription.
+var snsHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// snsCertCache keeps signing certificates by URL until they expire, to avoid fetching certificate for every message.
+type snsCertCache struct {
+	mutex sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+var snsCerts = &snsCertCache{certs: map[string]*x509.Certificate{}}
+
+func (x *snsCertCache) get(certURL string, now time.Time) *x509.Certificate {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	cert, ok := x.certs[certURL]
+	if !ok {
+		return nil
 	}
-	body, err := io.ReadAll(r.Body)
+	if now.After(cert.NotAfter) {
+		delete(x.certs, certURL)
+		return nil
+	}
+	return cert
+}
+
+func (x *snsCertCache) put(certURL string, cert *x509.Certificate) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	x.certs[certURL] = cert
+}
+
+func handleSNSMessage(r *http.Request, uc interfaces.UseCases, validator SNSURLValidator) error {
+	ctx := r.Context()
+	logger := logging.Extract(ctx)
+
+	raw, err := io.ReadAll(r.Body)
 	if err != nil {
 		return goerr.Wrap(err, "Unable to read request body")
 	}
 
-	var message snsMessage
-	err = json.Unmarshal(body, &message)
+	var snsMsg snsMessage
+	if err := json.Unmarshal(raw, &snsMsg); err != nil {
+		return goerr.Wrap(err, "Invalid JSON",
+			goerr.V("body", string(raw)),
+			goerr.T(types.ErrTagBadRequest),
+		)
+	}
+	logger.Debug("Received SNS message", "msg", snsMsg)
+
+	auth, err := verifySNSMessage(ctx, snsMsg, validator, time.Now())
 	if err != nil {
-		return goerr.Wrap(err, "Invalid JSON", goerr.V("body", string(body)))
+		return goerr.Wrap(err, "Failed to verify SNS message", goerr.T(types.ErrTagUnauthorized))
 	}
 
-	logging.Extract(ctx).Debug("Received SNS message", "message", message)
+	msg := model.Message{
+		Source: "aws.sns",
+		Schema: r.PathValue("schema"),
+		Header: cloneHeader(r.Header),
+		Auth: model.AuthContext{
+			AWS: &model.AuthContextAWS{
+				SNS: auth,
+			},
+		},
+	}
 
-	// Here you can handle different SNS message types like subscription confirmation, notification, etc.
-	switch message.Type {
+	switch snsMsg.Type {
 	case "SubscriptionConfirmation":
-		if err := confirmSnsSubscription(ctx, message.SubscribeURL); err != nil {
+		// Subscription must be allowed by policy, otherwise anyone can subscribe xroute to their own topic.
+		if err := authorizeMessage(r, uc, msg); err != nil {
 			return err
 		}
+		return confirmSNSSubscription(ctx, snsMsg.SubscribeURL, validator)
+
+	case "UnsubscribeConfirmation":
+		logger.Info("SNS subscription is unsubscribed", "topic_arn", snsMsg.TopicArn)
+		return nil
 
 	case "Notification":
-		if err := uc.Route(ctx, message.Message); err != nil {
-			return err
+		// go through
+
+	default:
+		return goerr.New("Unsupported SNS message type",
+			goerr.V("type", snsMsg.Type),
+			goerr.T(types.ErrTagBadRequest),
+		)
+	}
+
+	var body any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return goerr.Wrap(err, "Failed to unmarshal JSON", goerr.V("data", string(raw)))
+	}
+	msg.Body = body
+
+	// SNS message is free format. If it can be parsed as JSON, it will be stored in msg.Data
+	var data any
+	if err := json.Unmarshal([]byte(snsMsg.Message), &data); err == nil {
+		msg.Data = data
+		logger.Debug("Parsed message of SNS as JSON", "data", data)
+	} else {
+		msg.Data = snsMsg.Message
+		logger.Debug("Message of SNS can not be parsed, use it as raw", "data", msg.Data)
+	}
+
+	if err := routeMessage(r, uc, msg); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func verifySNSMessage(ctx context.Context, msg snsMessage, validator SNSURLValidator, now time.Time) (*model.AWSSNSAuth, error) {
+	// Timestamp is checked before fetching certificate. It can not be forged because it's covered by the signature.
+	ts, err := time.Parse(time.RFC3339, msg.Timestamp)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid Timestamp", goerr.V("timestamp", msg.Timestamp))
+	}
+	if diff := now.Sub(ts); diff > snsMessageTolerance || diff < -snsMessageTolerance {
+		return nil, goerr.New("Timestamp of SNS message is too old or too new", goerr.V("timestamp", msg.Timestamp))
+	}
+
+	certURL, err := url.Parse(msg.SigningCertURL)
+	if err != nil {
+		return nil, goerr.Wrap(err, "invalid SigningCertURL", goerr.V("url", msg.SigningCertURL))
+	}
+	if err := validator(certURL); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(certURL.Path, ".pem") {
+		return nil, goerr.New("SigningCertURL must be PEM file", goerr.V("url", msg.SigningCertURL))
+	}
+
+	cert, err := fetchSNSCert(ctx, certURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := buildSNSMessageString(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyX509Signature(cert, msg.SignatureVersion, signed, msg.Signature); err != nil {
+		return nil, err
+	}
+
+	return &model.AWSSNSAuth{
+		Type:             msg.Type,
+		TopicArn:         msg.TopicArn,
+		MessageID:        msg.MessageId,
+		Timestamp:        ts,
+		SignatureVersion: msg.SignatureVersion,
+	}, nil
+}
+
+func fetchSNSCert(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	if cert := snsCerts.get(certURL, time.Now()); cert != nil {
+		return cert, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create request", goerr.V("url", certURL))
+	}
+
+	resp, err := snsHTTPClient.Do(req)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to fetch certificate", goerr.V("url", certURL))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, goerr.New("unexpected status code of certificate", goerr.V("url", certURL), goerr.V("status_code", resp.StatusCode))
+	}
+
+	certData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to read certificate data", goerr.V("url", certURL))
+	}
+
+	block, _ := pem.Decode(certData)
+	if block == nil {
+		return nil, goerr.New("failed to decode PEM block", goerr.V("url", certURL))
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to parse certificate", goerr.V("url", certURL))
+	}
+	snsCerts.put(certURL, cert)
+
+	return cert, nil
+}
+
+// buildSNSMessageString builds the string to be signed. See https://docs.aws.amazon.com/sns/latest/dg/sns-verify-signature-of-message.html
+func buildSNSMessageString(msg snsMessage) (string, error) {
+	var msgParts []string
+
+	switch msg.Type {
+	case "Notification":
+		msgParts = []string{
+			"Message", msg.Message,
+			"MessageId", msg.MessageId,
+		}
+
+		if msg.Subject != "" {
+			msgParts = append(msgParts, "Subject", msg.Subject)
 		}
+
+		msgParts = append(msgParts,
+			"Timestamp", msg.Timestamp,
+			"TopicArn", msg.TopicArn,
+			"Type", msg.Type,
+		)
+
+	case "SubscriptionConfirmation", "UnsubscribeConfirmation":
+		msgParts = []string{
+			"Message", msg.Message,
+			"MessageId", msg.MessageId,
+			"SubscribeURL", msg.SubscribeURL,
+			"Timestamp", msg.Timestamp,
+			"Token", msg.Token,
+			"TopicArn", msg.TopicArn,
+			"Type", msg.Type,
+		}
+
+	default:
+		return "", goerr.New("unknown SNS message type", goerr.V("type", msg.Type))
+	}
+
+	return strings.Join(msgParts, "\n") + "\n", nil
+}
+
+func verifyX509Signature(cert *x509.Certificate, version, message, signature string) error {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return goerr.Wrap(err, "failed to decode signature", goerr.V("signature", signature))
+	}
+
+	alg := map[string]x509.SignatureAlgorithm{
+		"1": x509.SHA1WithRSA,
+		"2": x509.SHA256WithRSA,
+	}
+
+	sigAlg, ok := alg[version]
+	if !ok {
+		return goerr.New("unsupported signature version", goerr.V("version", version))
+	}
+
+	if err := cert.CheckSignature(sigAlg, []byte(message), signatureBytes); err != nil {
+		return goerr.Wrap(err, "failed to verify signature",
+			goerr.V("message", message),
+			goerr.V("signature", signature),
+		)
 	}
 
 	return nil
 }
 
-func confirmSnsSubscription(ctx context.Context, subscribeURL string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscribeURL, nil)
+func confirmSNSSubscription(ctx context.Context, subscribeURL string, validator SNSURLValidator) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil {
+		return goerr.Wrap(err, "invalid SubscribeURL", goerr.V("url", subscribeURL), goerr.T(types.ErrTagBadRequest))
+	}
+	if err := validator(u); err != nil {
+		return goerr.Wrap(err, "SubscribeURL is not acceptable", goerr.T(types.ErrTagBadRequest))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return goerr.Wrap(err, "Failed to send GET request to subscription URL")
+		return goerr.Wrap(err, "Failed to create request to subscription URL", goerr.V("url", subscribeURL))
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := snsHTTPClient.Do(req)
 	if err != nil {
-		return goerr.Wrap(err, "Failed to send GET request to subscription URL")
+		return goerr.Wrap(err, "Failed to send GET request to subscription URL", goerr.V("url", subscribeURL))
 	}
-	defer resp.Body.Close()
+	defer safe.Close(ctx, resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return goerr.New("Failed to confirm subscription", goerr.V("status_code", resp.StatusCode), goerr.V("body", string(body)))
+		return goerr.New("Failed to confirm subscription",
+			goerr.V("status_code", resp.StatusCode),
+			goerr.V("body", string(body)),
+		)
 	}
 
+	logging.Extract(ctx).Info("SNS subscription is confirmed", "url", subscribeURL)
 	return nil
 }
-*/
@@ -0,0 +1,336 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" // #nosec G505 SNS signature version 1 uses SHA1
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	server "github.com/m-mizutani/xroute/pkg/controller/http"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/mock"
+)
+
+type snsSigner struct {
+	key     *rsa.PrivateKey
+	certURL string
+	fetched int
+}
+
+func newSNSSigner(t *testing.T) *snsSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	gt.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	gt.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	signer := &snsSigner{key: key}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signer.fetched++
+		_, _ = w.Write(certPEM)
+	}))
+	t.Cleanup(ts.Close)
+	signer.certURL = ts.URL + "/cert.pem"
+
+	return signer
+}
+
+func (x *snsSigner) sign(t *testing.T, msg map[string]string) []byte {
+	var keys []string
+	switch msg["Type"] {
+	case "Notification":
+		keys = []string{"Message", "MessageId", "Subject", "Timestamp", "TopicArn", "Type"}
+	default:
+		keys = []string{"Message", "MessageId", "SubscribeURL", "Timestamp", "Token", "TopicArn", "Type"}
+	}
+
+	var s string
+	for _, k := range keys {
+		if v, ok := msg[k]; ok {
+			s += k + "\n" + v + "\n"
+		}
+	}
+
+	msg["SigningCertURL"] = x.certURL
+	var sig []byte
+	var err error
+	switch msg["SignatureVersion"] {
+	case "1":
+		h := sha1.Sum([]byte(s)) // #nosec G401
+		sig, err = rsa.SignPKCS1v15(rand.Reader, x.key, crypto.SHA1, h[:])
+	default:
+		h := sha256.Sum256([]byte(s))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, x.key, crypto.SHA256, h[:])
+	}
+	gt.NoError(t, err)
+	msg["Signature"] = base64.StdEncoding.EncodeToString(sig)
+
+	raw, err := json.Marshal(msg)
+	gt.NoError(t, err)
+	return raw
+}
+
+func acceptAnyURL(u *url.URL) error { return nil }
+
+// snsTimestamp formats time as Timestamp of SNS message
+func snsTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func TestSNSNotification(t *testing.T) {
+	signer := newSNSSigner(t)
+	now := snsTimestamp(time.Now())
+
+	for _, version := range []string{"1", "2"} {
+		t.Run("version "+version, func(t *testing.T) {
+			uc := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					return nil
+				},
+				RouteFunc: func(ctx context.Context, msg model.Message) error {
+					return nil
+				},
+			}
+			srv := server.New(uc, server.WithSNSURLValidator(acceptAnyURL))
+
+			body := signer.sign(t, map[string]string{
+				"Type":             "Notification",
+				"MessageId":        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+				"TopicArn":         "arn:aws:sns:us-west-2:123456789012:MyTopic",
+				"Subject":          "My First Message",
+				"Message":          `{"color":"blue"}`,
+				"Timestamp":        now,
+				"SignatureVersion": version,
+			})
+
+			r := httptest.NewRequest("POST", "/msg/sns/my_schema", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+
+			gt.Equal(t, w.Code, 200)
+			gt.A(t, uc.RouteCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx context.Context
+				Msg model.Message
+			}) {
+				gt.Equal(t, v.Msg.Source, "aws.sns")
+				gt.Equal(t, v.Msg.Schema, "my_schema")
+				data := gt.Cast[map[string]any](t, v.Msg.Data)
+				gt.Equal(t, data["color"], "blue")
+
+				gt.NotEqual(t, v.Msg.Auth.AWS, nil)
+				auth := v.Msg.Auth.AWS.SNS
+				gt.NotEqual(t, auth, nil)
+				gt.Equal(t, auth.TopicArn, "arn:aws:sns:us-west-2:123456789012:MyTopic")
+				gt.Equal(t, auth.MessageID, "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324")
+				gt.Equal(t, auth.SignatureVersion, version)
+				gt.Equal(t, auth.Timestamp.Format(time.RFC3339Nano), now)
+			})
+		})
+	}
+
+	// Certificate is cached by URL
+	gt.Equal(t, signer.fetched, 1)
+}
+
+func TestSNSCertURLMustBePEM(t *testing.T) {
+	signer := newSNSSigner(t)
+	signer.certURL = signer.certURL[:len(signer.certURL)-len(".pem")]
+	uc := &mock.UseCasesMock{}
+	srv := server.New(uc, server.WithSNSURLValidator(acceptAnyURL))
+
+	body := signer.sign(t, map[string]string{
+		"Type":             "Notification",
+		"MessageId":        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+		"TopicArn":         "arn:aws:sns:us-west-2:123456789012:MyTopic",
+		"Message":          "Hello",
+		"Timestamp":        snsTimestamp(time.Now()),
+		"SignatureVersion": "2",
+	})
+
+	r := httptest.NewRequest("POST", "/msg/sns/my_schema", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	gt.Equal(t, w.Code, http.StatusUnauthorized)
+	gt.Equal(t, signer.fetched, 0)
+	gt.A(t, uc.RouteCalls()).Length(0)
+}
+
+func TestSNSInvalidSignature(t *testing.T) {
+	signer := newSNSSigner(t)
+	uc := &mock.UseCasesMock{}
+	srv := server.New(uc, server.WithSNSURLValidator(acceptAnyURL))
+
+	body := signer.sign(t, map[string]string{
+		"Type":             "Notification",
+		"MessageId":        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+		"TopicArn":         "arn:aws:sns:us-west-2:123456789012:MyTopic",
+		"Message":          "Hello",
+		"Timestamp":        snsTimestamp(time.Now()),
+		"SignatureVersion": "2",
+	})
+	body = bytes.Replace(body, []byte("Hello"), []byte("Hacked"), 1)
+
+	r := httptest.NewRequest("POST", "/msg/sns/my_schema", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	gt.Equal(t, w.Code, http.StatusUnauthorized)
+	gt.A(t, uc.RouteCalls()).Length(0)
+}
+
+func TestSNSExpiredTimestamp(t *testing.T) {
+	testCases := map[string]time.Duration{
+		"too old": -time.Hour,
+		"too new": time.Hour,
+	}
+
+	for name, offset := range testCases {
+		t.Run(name, func(t *testing.T) {
+			signer := newSNSSigner(t)
+			uc := &mock.UseCasesMock{}
+			srv := server.New(uc, server.WithSNSURLValidator(acceptAnyURL))
+
+			// Correctly signed message captured before is not accepted
+			body := signer.sign(t, map[string]string{
+				"Type":             "Notification",
+				"MessageId":        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+				"TopicArn":         "arn:aws:sns:us-west-2:123456789012:MyTopic",
+				"Message":          "Hello",
+				"Timestamp":        snsTimestamp(time.Now().Add(offset)),
+				"SignatureVersion": "2",
+			})
+
+			r := httptest.NewRequest("POST", "/msg/sns/my_schema", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+
+			gt.Equal(t, w.Code, http.StatusUnauthorized)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
+
+func TestSNSUnacceptableCertURL(t *testing.T) {
+	signer := newSNSSigner(t)
+	uc := &mock.UseCasesMock{}
+	srv := server.New(uc) // DefaultSNSURLValidator rejects local cert server
+
+	body := signer.sign(t, map[string]string{
+		"Type":             "Notification",
+		"MessageId":        "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+		"TopicArn":         "arn:aws:sns:us-west-2:123456789012:MyTopic",
+		"Message":          "Hello",
+		"Timestamp":        snsTimestamp(time.Now()),
+		"SignatureVersion": "2",
+	})
+
+	r := httptest.NewRequest("POST", "/msg/sns/my_schema", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, r)
+
+	gt.Equal(t, w.Code, http.StatusUnauthorized)
+	gt.A(t, uc.RouteCalls()).Length(0)
+}
+
+func TestSNSSubscriptionConfirmation(t *testing.T) {
+	testCases := map[string]struct {
+		authzErr  error
+		code      int
+		confirmed bool
+	}{
+		"allowed by policy": {
+			code:      http.StatusOK,
+			confirmed: true,
+		},
+		"denied by policy": {
+			authzErr: goerr.New("denied", goerr.T(types.ErrTagForbidden)),
+			code:     http.StatusForbidden,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			signer := newSNSSigner(t)
+
+			var confirmed bool
+			confirmSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				confirmed = r.URL.Query().Get("Token") == "my-token"
+			}))
+			defer confirmSrv.Close()
+
+			uc := &mock.UseCasesMock{
+				AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+					gt.Equal(t, input.Auth.AWS.SNS.Type, "SubscriptionConfirmation")
+					return tc.authzErr
+				},
+			}
+			srv := server.New(uc, server.WithSNSURLValidator(acceptAnyURL))
+
+			body := signer.sign(t, map[string]string{
+				"Type":             "SubscriptionConfirmation",
+				"MessageId":        "165545c9-2a5c-472c-8df2-7ff2be2b3b1b",
+				"Token":            "my-token",
+				"TopicArn":         "arn:aws:sns:us-west-2:123456789012:MyTopic",
+				"Message":          "You have chosen to subscribe to the topic",
+				"SubscribeURL":     confirmSrv.URL + "/?Action=ConfirmSubscription&Token=my-token",
+				"Timestamp":        snsTimestamp(time.Now()),
+				"SignatureVersion": "1",
+			})
+
+			r := httptest.NewRequest("POST", "/msg/sns/my_schema", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+
+			gt.Equal(t, w.Code, tc.code)
+			gt.Equal(t, confirmed, tc.confirmed)
+			gt.A(t, uc.RouteCalls()).Length(0)
+		})
+	}
+}
+
+func TestDefaultSNSURLValidator(t *testing.T) {
+	testCases := map[string]bool{
+		"https://sns.us-east-1.amazonaws.com/SimpleNotificationService-xxx.pem":     true,
+		"http://sns.us-east-1.amazonaws.com/SimpleNotificationService-xxx.pem":      false,
+		"https://sns.us-east-1.amazonaws.com.example.com/cert.pem":                  false,
+		"https://example.com/cert.pem":                                              false,
+		"https://sns.cn-north-1.amazonaws.com.cn/SimpleNotificationService-xxx.pem": true,
+		// S3 bucket can be created by anyone, so the certificate could be forged
+		"https://attacker.s3.amazonaws.com/cert.pem":            false,
+		"https://sns.s3.us-east-1.amazonaws.com/cert.pem":       false,
+		"https://sns.us-east-1.amazonaws.com/?Action=Subscribe": true,
+	}
+
+	for raw, ok := range testCases {
+		t.Run(raw, func(t *testing.T) {
+			u, err := url.Parse(raw)
+			gt.NoError(t, err)
+			gt.Equal(t, server.DefaultSNSURLValidator(u) == nil, ok)
+		})
+	}
+}
@@ -2,15 +2,101 @@ package interfaces
 
 import (
 	"context"
+	"net/http"
+	"time"
 
+	"github.com/google/go-github/v68/github"
 	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
 	"github.com/slack-go/slack"
 )
 
 type Slack interface {
 	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error)
+	OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+}
+
+// SlackThreadStore keeps the first message of Slack thread by channel and thread key.
+type SlackThreadStore interface {
+	// Get returns the thread. It returns nil if the thread is not found, expired or only claimed.
+	Get(ctx context.Context, channel, key string) (*model.SlackThread, error)
+
+	// Put saves the thread. It overwrites the existing one and the claim.
+	Put(ctx context.Context, channel, key string, thread *model.SlackThread) error
+
+	// Claim atomically reserves the key to start a new thread, so only one process posts the first message. It returns false if the thread exists or the key is claimed by another. The claim expires after ttl unless the thread is saved by Put.
+	Claim(ctx context.Context, channel, key string, ttl time.Duration) (bool, error)
+
+	// Release removes the claim when the first message could not be posted.
+	Release(ctx context.Context, channel, key string) error
+}
+
+// Teams posts message to Microsoft Teams via incoming webhook or Workflows.
+type Teams interface {
+	// Post sends payload as JSON to the webhook URL configured with the channel name.
+	Post(ctx context.Context, channel string, payload any) error
+}
+
+// Discord posts message to Discord via webhook.
+type Discord interface {
+	// Post sends payload as JSON to the webhook URL configured with the channel name.
+	Post(ctx context.Context, channel string, payload any) error
+}
+
+// Email sends mail.
+type Email interface {
+	Send(ctx context.Context, mail *model.Email) error
+}
+
+// PagerDuty sends event to PagerDuty Events API v2.
+type PagerDuty interface {
+	// Enqueue sends event with the routing key configured with the name.
+	Enqueue(ctx context.Context, routingKey string, event map[string]any) error
+}
+
+// GitHub manages issues and comments of GitHub repository.
+type GitHub interface {
+	CreateIssue(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, error)
+	CreateComment(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error)
+
+	// ListOpenIssues returns open issues, not including pull requests, that have all of the labels. The number of issues is limited.
+	ListOpenIssues(ctx context.Context, owner, repo string, labels []string) ([]*github.Issue, error)
 }
 
 type Policy interface {
 	Query(ctx context.Context, query string, input, output any, options ...opac.QueryOption) error
 }
+
+// Queue stores deliveries until they are transmitted.
+type Queue interface {
+	// Push adds the delivery to the queue. If a delivery with the same ID already exists, it's overwritten. Pushing an in-flight delivery, e.g. for retry, replaces it with the pending one, so Done is not required afterwards.
+	Push(ctx context.Context, delivery *model.Delivery) error
+
+	// Pop takes out a delivery whose NextAttemptAt is not after now. It returns nil if no delivery is ready. The taken delivery is kept as in-flight until Done is called with its ID.
+	Pop(ctx context.Context, now time.Time) (*model.Delivery, error)
+
+	// Done removes the in-flight delivery.
+	Done(ctx context.Context, id string) error
+}
+
+// DeadLetterStore stores deliveries that have been given up.
+type DeadLetterStore interface {
+	Put(ctx context.Context, entry *model.DeadLetter) error
+
+	// Get returns the entry. It returns nil if the entry is not found.
+	Get(ctx context.Context, id string) (*model.DeadLetter, error)
+
+	// List returns all entries in order of FailedAt.
+	List(ctx context.Context) ([]*model.DeadLetter, error)
+
+	// Delete removes the entry. It does not return error if the entry is not found.
+	Delete(ctx context.Context, id string) error
+}
+
+// HTTPClient sends HTTP request. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
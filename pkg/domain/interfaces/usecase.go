@@ -7,5 +7,14 @@ import (
 )
 
 type UseCases interface {
+	Authorize(ctx context.Context, input model.PolicyAuthzInput) error
 	Route(ctx context.Context, msg model.Message) error
+
+	ListDeadLetters(ctx context.Context) ([]*model.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, id string) error
+	ReplayDeadLetter(ctx context.Context, id string) error
+
+	ReloadPolicy(ctx context.Context, trigger string) error
+	PolicyStatus(ctx context.Context) (*model.PolicyStatus, error)
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// AlertmanagerWebhook is payload of Prometheus Alertmanager webhook version 4. See https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is an alert in the notification.
+type AlertmanagerAlert struct {
+	// Status is "firing" or "resolved".
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertmanagerSplitAlert is data of a message split from the notification per alert. It has fields of the alert and the group that the alert belongs to.
+type AlertmanagerSplitAlert struct {
+	AlertmanagerAlert
+
+	GroupKey    string            `json:"groupKey"`
+	Receiver    string            `json:"receiver"`
+	GroupLabels map[string]string `json:"groupLabels"`
+	ExternalURL string            `json:"externalURL"`
+}
@@ -1 +1,63 @@
 package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+)
+
+// HMACProfile is signature scheme of HMAC signed webhook, e.g. Stripe, Linear, Sentry and Shopify.
+type HMACProfile struct {
+	// Secret is key of HMAC.
+	Secret string
+
+	// Header is name of header that has signature.
+	Header string
+	// SignatureKey is set if the header is comma separated key=value pairs, e.g. "v1" of Stripe-Signature. Every value of the key is tried as signature.
+	SignatureKey string
+	// Prefix is removed from signature, e.g. "sha256=".
+	Prefix string
+
+	// Algorithm is sha1, sha256 or sha512.
+	Algorithm string
+	// Encoding of signature is hex or base64.
+	Encoding string
+
+	// Template is signed content. {body}, {timestamp} and {header:<name>} are replaced. Default is "{body}".
+	Template string
+
+	// TimestampHeader is name of header that has timestamp in unix seconds or milliseconds.
+	TimestampHeader string
+	// TimestampKey is set if timestamp is in key=value pairs of the header, e.g. "t" of Stripe-Signature. If TimestampHeader is empty, Header is used.
+	TimestampKey string
+	// MaxSkew is acceptable difference between the timestamp and current time. If zero, timestamp is not checked.
+	MaxSkew time.Duration
+}
+
+func (x HMACProfile) Validate() error {
+	if x.Secret == "" {
+		return goerr.New("secret of HMAC profile is required")
+	}
+	if x.Header == "" {
+		return goerr.New("header of HMAC profile is required")
+	}
+	switch x.Algorithm {
+	case "sha1", "sha256", "sha512":
+	default:
+		return goerr.New("algorithm of HMAC profile must be sha1, sha256 or sha512", goerr.V("algorithm", x.Algorithm))
+	}
+	switch x.Encoding {
+	case "hex", "base64":
+	default:
+		return goerr.New("encoding of HMAC profile must be hex or base64", goerr.V("encoding", x.Encoding))
+	}
+	if x.MaxSkew > 0 && x.TimestampHeader == "" && x.TimestampKey == "" {
+		return goerr.New("timestamp_header or timestamp_key of HMAC profile is required to check max_skew")
+	}
+	// Otherwise {timestamp} is replaced with empty string and signature never matches
+	if strings.Contains(x.Template, "{timestamp}") && x.TimestampHeader == "" && x.TimestampKey == "" {
+		return goerr.New("timestamp_header or timestamp_key of HMAC profile is required to use {timestamp} in template", goerr.V("template", x.Template))
+	}
+	return nil
+}
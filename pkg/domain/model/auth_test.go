@@ -0,0 +1,72 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+func TestHMACProfileValidate(t *testing.T) {
+	base := model.HMACProfile{
+		Secret:    "secret",
+		Header:    "X-Signature",
+		Algorithm: "sha256",
+		Encoding:  "hex",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(p *model.HMACProfile)
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			modify: func(p *model.HMACProfile) {},
+		},
+		{
+			name:    "no secret",
+			modify:  func(p *model.HMACProfile) { p.Secret = "" },
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			modify:  func(p *model.HMACProfile) { p.Algorithm = "md5" },
+			wantErr: true,
+		},
+		{
+			name:    "max skew without timestamp",
+			modify:  func(p *model.HMACProfile) { p.MaxSkew = time.Minute },
+			wantErr: true,
+		},
+		{
+			name:    "timestamp in template without timestamp",
+			modify:  func(p *model.HMACProfile) { p.Template = "{timestamp}.{body}" },
+			wantErr: true,
+		},
+		{
+			name: "timestamp in template with timestamp key",
+			modify: func(p *model.HMACProfile) {
+				p.Template = "{timestamp}.{body}"
+				p.TimestampKey = "t"
+			},
+		},
+		{
+			name: "timestamp in template with timestamp header",
+			modify: func(p *model.HMACProfile) {
+				p.Template = "v0:{timestamp}:{body}"
+				p.TimestampHeader = "X-Timestamp"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := base
+			tt.modify(&profile)
+			if err := profile.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
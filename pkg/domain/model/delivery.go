@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Delivery is a unit of transmission to one destination. It's created from policy output of a message and processed asynchronously by delivery worker.
+type Delivery struct {
+	// ID is unique identifier of the delivery.
+	ID string `json:"id"`
+
+	// Message is the original message that the delivery is created from.
+	Message Message `json:"message"`
+
+	// Slack is set if destination of the delivery is Slack.
+	Slack *SlackMessage `json:"slack,omitempty"`
+
+	// SlackFile is set if the delivery uploads a file to the thread of posted Slack message.
+	SlackFile *SlackFileUpload `json:"slack_file,omitempty"`
+
+	// Teams is set if destination of the delivery is Microsoft Teams.
+	Teams *TeamsMessage `json:"teams,omitempty"`
+
+	// Webhook is set if destination of the delivery is an HTTP endpoint.
+	Webhook *WebhookMessage `json:"webhook,omitempty"`
+
+	// Discord is set if destination of the delivery is Discord.
+	Discord *DiscordMessage `json:"discord,omitempty"`
+
+	// Email is set if destination of the delivery is email.
+	Email *EmailMessage `json:"email,omitempty"`
+
+	// PagerDuty is set if destination of the delivery is PagerDuty.
+	PagerDuty *PagerDutyMessage `json:"pagerduty,omitempty"`
+
+	// GitHub is set if destination of the delivery is GitHub issue.
+	GitHub *GitHubMessage `json:"github,omitempty"`
+
+	// Attempts is number of failed attempts of the delivery.
+	Attempts int `json:"attempts"`
+
+	// LastError is error message of the last failed attempt.
+	LastError string `json:"last_error,omitempty"`
+
+	// CreatedAt is the time when the delivery is created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// NextAttemptAt is the time when the delivery can be attempted next.
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// DeadLetter is a delivery that has failed and will not be retried anymore.
+type DeadLetter struct {
+	// ID is the same as ID of the delivery.
+	ID string `json:"id"`
+
+	// Delivery is the failed delivery, including the original message and the policy output.
+	Delivery Delivery `json:"delivery"`
+
+	// Error is error message of the last attempt.
+	Error string `json:"error"`
+
+	// FailedAt is the time when the delivery is given up.
+	FailedAt time.Time `json:"failed_at"`
+}
@@ -0,0 +1,7 @@
+package model
+
+// DryRunDelivery is a rendered payload that would be transmitted to the destination if dry-run mode is disabled.
+type DryRunDelivery struct {
+	Destination string `json:"destination"`
+	Payload     any    `json:"payload"`
+}
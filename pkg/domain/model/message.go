@@ -19,10 +19,28 @@ type Message struct {
 	// Data is parsed data part of the message. It's free format and can be any type. If it's JSON, it's parsed as JSON. Otherwise, it's raw bytes.
 	Data any `json:"data"`
 
+	// CloudEvent is context attributes of CloudEvents. It's set only for message from /msg/cloudevents.
+	CloudEvent *CloudEvent `json:"cloudevent,omitempty"`
+
 	// Auth is authentication information of the message. Authentication message is extracted from header mainly, e.g. JWT token, Secret key, etc.
 	Auth AuthContext `json:"auth"`
 }
 
+// CloudEvent is context attributes of CloudEvents 1.0.
+type CloudEvent struct {
+	ID              string     `json:"id"`
+	Source          string     `json:"source"`
+	SpecVersion     string     `json:"specversion"`
+	Type            string     `json:"type"`
+	Subject         string     `json:"subject,omitempty"`
+	Time            *time.Time `json:"time,omitempty"`
+	DataContentType string     `json:"datacontenttype,omitempty"`
+	DataSchema      string     `json:"dataschema,omitempty"`
+
+	// Extensions are attributes not defined in the spec, e.g. "traceparent". Values of binary mode are always string.
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
 // AuthContext is authentication context of the message.
 type AuthContext struct {
 	// Google is parsed Google ID Token. It's set if the message is authenticated by Google ID Token.
@@ -30,6 +48,84 @@ type AuthContext struct {
 
 	// GitHub is parsed GitHub authentication information.
 	GitHub *AuthContextGitHub `json:"github,omitempty"`
+
+	// AWS is AWS authentication information.
+	AWS *AuthContextAWS `json:"aws,omitempty"`
+
+	// GitLab is GitLab webhook information.
+	GitLab *AuthContextGitLab `json:"gitlab,omitempty"`
+
+	// HMAC is verification result of HMAC signed webhook.
+	HMAC *AuthContextHMAC `json:"hmac,omitempty"`
+
+	// Slack is Slack authentication information. It's set for requests from Slack.
+	Slack *AuthContextSlack `json:"slack,omitempty"`
+}
+
+// AuthContextGitLab is GitLab webhook information.
+type AuthContextGitLab struct {
+	// Instance is URL of GitLab instance from "X-Gitlab-Instance" header.
+	Instance string `json:"instance"`
+
+	// EventUUID is from "X-Gitlab-Event-UUID" header. It's unique for each event, and same for retries.
+	EventUUID string `json:"event_uuid"`
+
+	// WebhookUUID is from "X-Gitlab-Webhook-UUID" header.
+	WebhookUUID string `json:"webhook_uuid"`
+
+	// Valid is true if the webhook is validated by secret token or signing token.
+	Valid bool `json:"valid"`
+}
+
+// AuthContextHMAC is verification result of HMAC signed webhook. Request with invalid signature is also routed, so the policy should check Valid.
+type AuthContextHMAC struct {
+	// Profile is name of the signature scheme.
+	Profile string `json:"profile"`
+
+	// Valid is true if the signature is verified and the timestamp is within max skew.
+	Valid bool `json:"valid"`
+
+	// Reason is why the signature is not valid.
+	Reason string `json:"reason,omitempty"`
+
+	// Timestamp is signed timestamp in unix seconds. It's set if the profile has timestamp.
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// AuthContextSlack is Slack workspace, app and user that sent the request.
+type AuthContextSlack struct {
+	TeamID string `json:"team_id"`
+	AppID  string `json:"app_id"`
+
+	// UserID and UserName are set for interaction.
+	UserID   string `json:"user_id,omitempty"`
+	UserName string `json:"user_name,omitempty"`
+
+	// Verified is true if the request is verified by signing secret.
+	Verified bool `json:"verified"`
+}
+
+// AuthContextAWS is AWS authentication information.
+type AuthContextAWS struct {
+	SNS *AWSSNSAuth `json:"sns,omitempty"`
+}
+
+// AWSSNSAuth is verified AWS SNS message information. It's set only if the signature of the message is verified by the signing certificate.
+type AWSSNSAuth struct {
+	// Type is SNS message type, e.g. "Notification", "SubscriptionConfirmation".
+	Type string `json:"type"`
+
+	// TopicArn is ARN of the SNS topic that the message is published to.
+	TopicArn string `json:"topic_arn"`
+
+	// MessageID is unique identifier of the SNS message.
+	MessageID string `json:"message_id"`
+
+	// Timestamp is the time when the message was published.
+	Timestamp time.Time `json:"timestamp"`
+
+	// SignatureVersion is version of the SNS signature, "1" (SHA1) or "2" (SHA256).
+	SignatureVersion string `json:"signature_version"`
 }
 
 // AuthContextGitHub is GitHub authentication information.
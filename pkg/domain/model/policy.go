@@ -1,6 +1,20 @@
 package model
 
+import "time"
+
+// PolicyAuthzInput is input of "data.auth" query. It's evaluated for every incoming message before routing.
 type PolicyAuthzInput struct {
+	// Method is HTTP method of the request.
+	Method string `json:"method"`
+
+	// Path is URL path of the request.
+	Path string `json:"path"`
+
+	// Header is HTTP header of the request. Only the first value is stored.
+	Header map[string]string `json:"header"`
+
+	// Auth is verified authentication information of the request.
+	Auth AuthContext `json:"auth"`
 }
 
 type PolicyAuthzOutput struct {
@@ -12,5 +26,35 @@ type PolicyTransmitInput struct {
 }
 
 type PolicyTransmitOutput struct {
-	Slack []SlackMessage `json:"slack"`
+	Slack     []SlackMessage     `json:"slack"`
+	Teams     []TeamsMessage     `json:"teams,omitempty"`
+	Webhook   []WebhookMessage   `json:"webhook,omitempty"`
+	Discord   []DiscordMessage   `json:"discord,omitempty"`
+	Email     []EmailMessage     `json:"email,omitempty"`
+	PagerDuty []PagerDutyMessage `json:"pagerduty,omitempty"`
+	GitHub    []GitHubMessage    `json:"github,omitempty"`
+}
+
+// PolicyStatus is status of policy reloading.
+type PolicyStatus struct {
+	// LoadedAt is the time when the current policy was loaded.
+	LoadedAt time.Time `json:"loaded_at"`
+
+	// LastReload is result of the last reloading. It's nil if the policy has never been reloaded.
+	LastReload *PolicyReloadResult `json:"last_reload,omitempty"`
+}
+
+// PolicyReloadResult is result of a policy reloading.
+type PolicyReloadResult struct {
+	// Trigger is what triggered the reloading, e.g. "signal", "file", "api".
+	Trigger string `json:"trigger"`
+
+	// At is the time when the reloading was attempted.
+	At time.Time `json:"at"`
+
+	// Success is true if the new policy was loaded. If false, the previous policy is kept.
+	Success bool `json:"success"`
+
+	// Error is error message of the failed reloading.
+	Error string `json:"error,omitempty"`
 }
@@ -1,13 +1,74 @@
 package model
 
 type SlackMessage struct {
-	Emoji   string              `json:"emoji"`
-	Icon    string              `json:"icon"`
+	Emoji string `json:"emoji"`
+	Icon  string `json:"icon"`
+	// Channel is channel name or ID. If it's email, the message is sent as direct message to the user.
 	Channel string              `json:"channel"`
 	Color   string              `json:"color"`
 	Title   string              `json:"title"`
 	Body    string              `json:"body"`
 	Fields  []SlackMessageField `json:"fields"`
+
+	// Mentions are users to be mentioned at the head of the body. Email is resolved by users.lookupByEmail, and other value is GitHub login resolved by --slack-user-mapping.
+	Mentions []string `json:"mentions,omitempty"`
+
+	// Blocks are Block Kit blocks passed to Slack as is. Title, body and fields are still rendered in the colored attachment if they are set. See https://api.slack.com/reference/block-kit/blocks
+	Blocks []map[string]any `json:"blocks,omitempty"`
+	// Text is fallback text for notification. Title is used if empty and blocks are set.
+	Text string `json:"text,omitempty"`
+
+	// Actions are rendered as buttons in the attachment. Clicks are sent to /slack/interactions and routed as message of "slack.interaction" source.
+	Actions []SlackMessageAction `json:"actions,omitempty"`
+
+	// TS is timestamp of the message to be updated, e.g. the message that has the clicked button. Channel must be channel ID if it's set.
+	TS string `json:"ts,omitempty"`
+
+	// Files are uploaded and shared in the thread of the message. It's for payload that is too large for body.
+	Files []SlackFile `json:"files,omitempty"`
+
+	// ThreadKey correlates messages. The first message with the key is posted as usual, and following messages with the same key and channel are posted as replies in its thread.
+	ThreadKey string `json:"thread_key,omitempty"`
+	// Update edits the first message of the thread instead of replying.
+	Update bool `json:"update,omitempty"`
+	// Broadcast also sends the reply to the channel.
+	Broadcast bool `json:"broadcast,omitempty"`
+}
+
+// SlackThread is the first message of a thread that is correlated by thread key.
+type SlackThread struct {
+	// Channel is channel ID returned by Slack. It's required to update the message.
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// SlackMessageAction is a button. One of URL and Value should be set.
+type SlackMessageAction struct {
+	Text     string `json:"text"`
+	ActionID string `json:"action_id"`
+	Value    string `json:"value,omitempty"`
+	URL      string `json:"url,omitempty"`
+	// Style is "primary" or "danger". Default style is used if empty.
+	Style string `json:"style,omitempty"`
+}
+
+// SlackFile is a file uploaded with Slack message.
+type SlackFile struct {
+	// Filename is required.
+	Filename string `json:"filename"`
+	// Content is file content. If it's not string, it's encoded as indented JSON.
+	Content any `json:"content"`
+	// Filetype is appended to filename as extension if filename has no extension, because Slack detects type of file by its name. Default is json for non-string content.
+	Filetype string `json:"filetype,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+// SlackFileUpload is a file of Slack message to be uploaded to the thread of the posted message.
+type SlackFileUpload struct {
+	// Channel is channel ID that the message is posted to.
+	Channel  string    `json:"channel"`
+	ThreadTS string    `json:"thread_ts"`
+	File     SlackFile `json:"file"`
 }
 
 type SlackMessageField struct {
@@ -15,3 +76,140 @@ type SlackMessageField struct {
 	Value string `json:"value"`
 	Link  string `json:"link"`
 }
+
+// TeamsMessage is a message to Microsoft Teams. It's rendered as Adaptive Card.
+type TeamsMessage struct {
+	// Channel is name of the webhook configured by --teams-webhook. Webhook URL is not written in policy because it works as credential.
+	Channel string              `json:"channel"`
+	Color   string              `json:"color"`
+	Title   string              `json:"title"`
+	Body    string              `json:"body"`
+	Fields  []TeamsMessageField `json:"fields"`
+	Links   []TeamsMessageLink  `json:"links"`
+}
+
+type TeamsMessageField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Link  string `json:"link"`
+}
+
+// TeamsMessageLink is rendered as a button to open the URL.
+type TeamsMessageLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// WebhookMessage is a request to arbitrary HTTP endpoint.
+type WebhookMessage struct {
+	// URL is destination of the request. It's required.
+	URL string `json:"url"`
+
+	// Method is HTTP method of the request. Default is POST.
+	Method string `json:"method"`
+
+	// Header is HTTP header of the request.
+	Header map[string]string `json:"header"`
+
+	// Body is request body. If it's string, it's sent as is. Otherwise, it's encoded as JSON and Content-Type is set to application/json unless specified in Header.
+	Body any `json:"body"`
+
+	// Credential is name of credential configured by --webhook-credential. The credential is set to the request header, so secrets are not written in policy.
+	Credential string `json:"credential"`
+
+	// ExpectedStatus is list of acceptable status code of response. If empty, any 2xx is acceptable.
+	ExpectedStatus []int `json:"expected_status"`
+}
+
+// WebhookCredential is a secret header value that is set to webhook request.
+type WebhookCredential struct {
+	Header string
+	Value  string
+
+	// URL is https URL that the credential is allowed to be sent to. Request URL must have the same host, and its path must be under the path of URL.
+	URL string
+}
+
+// DiscordMessage is a message to Discord. It's rendered as an embed.
+type DiscordMessage struct {
+	// Channel is name of the webhook configured by --discord-webhook.
+	Channel string                `json:"channel"`
+	Color   string                `json:"color"`
+	Title   string                `json:"title"`
+	Body    string                `json:"body"`
+	Fields  []DiscordMessageField `json:"fields"`
+}
+
+type DiscordMessageField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Link  string `json:"link"`
+}
+
+// EmailMessage is a message to mail addresses. Title, body and fields are rendered into both plain text and HTML in the same manner as SlackMessage.
+type EmailMessage struct {
+	To      []string            `json:"to"`
+	Cc      []string            `json:"cc"`
+	Subject string              `json:"subject"` // Title is used if empty
+	Color   string              `json:"color"`
+	Title   string              `json:"title"`
+	Body    string              `json:"body"`
+	Fields  []EmailMessageField `json:"fields"`
+}
+
+type EmailMessageField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Link  string `json:"link"`
+}
+
+// Email is a rendered mail to be sent.
+type Email struct {
+	To      []string `json:"to"`
+	Cc      []string `json:"cc,omitempty"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html"`
+}
+
+// PagerDutyMessage is an event of PagerDuty Events API v2.
+type PagerDutyMessage struct {
+	// RoutingKey is name of the routing key configured by --pagerduty-routing-key.
+	RoutingKey string `json:"routing_key"`
+	// Action is trigger, acknowledge or resolve. Default is trigger.
+	Action string `json:"action"`
+	// DedupKey identifies the alert. It's required to acknowledge or resolve the alert.
+	DedupKey string `json:"dedup_key"`
+	// Severity is critical, error, warning or info. It's required for trigger.
+	Severity string `json:"severity"`
+	// Summary is required for trigger.
+	Summary string `json:"summary"`
+	// Source is "xroute" if empty.
+	Source        string         `json:"source"`
+	Component     string         `json:"component"`
+	Group         string         `json:"group"`
+	Class         string         `json:"class"`
+	CustomDetails map[string]any `json:"custom_details"`
+}
+
+// GitHubMessage opens an issue or comments on an existing issue or pull request.
+type GitHubMessage struct {
+	// Repo is target repository as owner/name.
+	Repo      string   `json:"repo"`
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Labels    []string `json:"labels"`
+	Assignees []string `json:"assignees"`
+	// Number is issue or pull request number to comment on. If set, new issue is not created.
+	Number int `json:"number"`
+	// Dedup finds an open issue to comment on instead of creating new one.
+	Dedup *GitHubDedup `json:"dedup"`
+}
+
+// GitHubDedup is condition to find existing open issue. If both are set, the issue must satisfy both.
+type GitHubDedup struct {
+	// Label must be attached to the issue. It's also attached to the new issue.
+	Label string `json:"label"`
+	// Title requires the issue to have the same title.
+	Title bool `json:"title"`
+}
@@ -1,8 +1,57 @@
 package types
 
-import "github.com/m-mizutani/goerr/v2"
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+)
 
 var (
 	ErrTagUnauthorized = goerr.NewTag("unauthorized")
+	ErrTagForbidden    = goerr.NewTag("forbidden")
 	ErrTagBadRequest   = goerr.NewTag("bad_request")
+	ErrTagNotFound     = goerr.NewTag("not_found")
 )
+
+// HTTPStatusError is returned when a destination responds error status code.
+type HTTPStatusError struct {
+	// Service is name of the destination used in error message, e.g. "teams webhook".
+	Service    string
+	StatusCode int
+	Body       string
+	retryAfter time.Duration
+}
+
+// NewHTTPStatusError creates HTTPStatusError. retryAfter is duration that the destination requests to wait, or zero if not specified.
+func NewHTTPStatusError(service string, statusCode int, body string, retryAfter time.Duration) *HTTPStatusError {
+	return &HTTPStatusError{
+		Service:    service,
+		StatusCode: statusCode,
+		Body:       body,
+		retryAfter: retryAfter,
+	}
+}
+
+func (x *HTTPStatusError) Error() string {
+	return x.Service + " returned status " + strconv.Itoa(x.StatusCode) + ": " + x.Body
+}
+
+// Retryable returns true if the request is throttled or failed by server error.
+func (x *HTTPStatusError) Retryable() bool {
+	return x.StatusCode == http.StatusTooManyRequests || x.StatusCode >= 500
+}
+
+// RetryAfter returns duration to wait before the next request. It's zero if the destination does not specify it.
+func (x *HTTPStatusError) RetryAfter() time.Duration {
+	return x.retryAfter
+}
+
+// ParseRetryAfter returns duration of Retry-After header in seconds. It's zero if the header is not set or not seconds.
+func ParseRetryAfter(header http.Header) time.Duration {
+	if sec, err := strconv.Atoi(header.Get("Retry-After")); err == nil && sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	return 0
+}
@@ -5,11 +5,14 @@ package mock
 
 import (
 	"context"
+	"github.com/google/go-github/v68/github"
 	"github.com/m-mizutani/opac"
 	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
 	"github.com/m-mizutani/xroute/pkg/domain/model"
 	"github.com/slack-go/slack"
+	"net/http"
 	"sync"
+	"time"
 )
 
 // Ensure, that SlackMock does implement interfaces.Slack.
@@ -22,9 +25,21 @@ var _ interfaces.Slack = &SlackMock{}
 //
 //		// make and configure a mocked interfaces.Slack
 //		mockedSlack := &SlackMock{
+//			GetUserByEmailContextFunc: func(ctx context.Context, email string) (*slack.User, error) {
+//				panic("mock out the GetUserByEmailContext method")
+//			},
+//			OpenConversationContextFunc: func(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+//				panic("mock out the OpenConversationContext method")
+//			},
 //			PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
 //				panic("mock out the PostMessageContext method")
 //			},
+//			UpdateMessageContextFunc: func(ctx context.Context, channelID string, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+//				panic("mock out the UpdateMessageContext method")
+//			},
+//			UploadFileV2ContextFunc: func(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+//				panic("mock out the UploadFileV2Context method")
+//			},
 //		}
 //
 //		// use mockedSlack in code that requires interfaces.Slack
@@ -32,61 +47,1039 @@ var _ interfaces.Slack = &SlackMock{}
 //
 //	}
 type SlackMock struct {
+	// GetUserByEmailContextFunc mocks the GetUserByEmailContext method.
+	GetUserByEmailContextFunc func(ctx context.Context, email string) (*slack.User, error)
+
+	// OpenConversationContextFunc mocks the OpenConversationContext method.
+	OpenConversationContextFunc func(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+
 	// PostMessageContextFunc mocks the PostMessageContext method.
 	PostMessageContextFunc func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
 
+	// UpdateMessageContextFunc mocks the UpdateMessageContext method.
+	UpdateMessageContextFunc func(ctx context.Context, channelID string, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+
+	// UploadFileV2ContextFunc mocks the UploadFileV2Context method.
+	UploadFileV2ContextFunc func(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetUserByEmailContext holds details about calls to the GetUserByEmailContext method.
+		GetUserByEmailContext []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Email is the email argument value.
+			Email string
+		}
+		// OpenConversationContext holds details about calls to the OpenConversationContext method.
+		OpenConversationContext []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params *slack.OpenConversationParameters
+		}
+		// PostMessageContext holds details about calls to the PostMessageContext method.
+		PostMessageContext []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Options is the options argument value.
+			Options []slack.MsgOption
+		}
+		// UpdateMessageContext holds details about calls to the UpdateMessageContext method.
+		UpdateMessageContext []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ChannelID is the channelID argument value.
+			ChannelID string
+			// Timestamp is the timestamp argument value.
+			Timestamp string
+			// Options is the options argument value.
+			Options []slack.MsgOption
+		}
+		// UploadFileV2Context holds details about calls to the UploadFileV2Context method.
+		UploadFileV2Context []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Params is the params argument value.
+			Params slack.UploadFileV2Parameters
+		}
+	}
+	lockGetUserByEmailContext   sync.RWMutex
+	lockOpenConversationContext sync.RWMutex
+	lockPostMessageContext      sync.RWMutex
+	lockUpdateMessageContext    sync.RWMutex
+	lockUploadFileV2Context     sync.RWMutex
+}
+
+// GetUserByEmailContext calls GetUserByEmailContextFunc.
+func (mock *SlackMock) GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error) {
+	if mock.GetUserByEmailContextFunc == nil {
+		panic("SlackMock.GetUserByEmailContextFunc: method is nil but Slack.GetUserByEmailContext was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Email string
+	}{
+		Ctx:   ctx,
+		Email: email,
+	}
+	mock.lockGetUserByEmailContext.Lock()
+	mock.calls.GetUserByEmailContext = append(mock.calls.GetUserByEmailContext, callInfo)
+	mock.lockGetUserByEmailContext.Unlock()
+	return mock.GetUserByEmailContextFunc(ctx, email)
+}
+
+// GetUserByEmailContextCalls gets all the calls that were made to GetUserByEmailContext.
+// Check the length with:
+//
+//	len(mockedSlack.GetUserByEmailContextCalls())
+func (mock *SlackMock) GetUserByEmailContextCalls() []struct {
+	Ctx   context.Context
+	Email string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Email string
+	}
+	mock.lockGetUserByEmailContext.RLock()
+	calls = mock.calls.GetUserByEmailContext
+	mock.lockGetUserByEmailContext.RUnlock()
+	return calls
+}
+
+// OpenConversationContext calls OpenConversationContextFunc.
+func (mock *SlackMock) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	if mock.OpenConversationContextFunc == nil {
+		panic("SlackMock.OpenConversationContextFunc: method is nil but Slack.OpenConversationContext was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params *slack.OpenConversationParameters
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockOpenConversationContext.Lock()
+	mock.calls.OpenConversationContext = append(mock.calls.OpenConversationContext, callInfo)
+	mock.lockOpenConversationContext.Unlock()
+	return mock.OpenConversationContextFunc(ctx, params)
+}
+
+// OpenConversationContextCalls gets all the calls that were made to OpenConversationContext.
+// Check the length with:
+//
+//	len(mockedSlack.OpenConversationContextCalls())
+func (mock *SlackMock) OpenConversationContextCalls() []struct {
+	Ctx    context.Context
+	Params *slack.OpenConversationParameters
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params *slack.OpenConversationParameters
+	}
+	mock.lockOpenConversationContext.RLock()
+	calls = mock.calls.OpenConversationContext
+	mock.lockOpenConversationContext.RUnlock()
+	return calls
+}
+
+// PostMessageContext calls PostMessageContextFunc.
+func (mock *SlackMock) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+	if mock.PostMessageContextFunc == nil {
+		panic("SlackMock.PostMessageContextFunc: method is nil but Slack.PostMessageContext was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		Options   []slack.MsgOption
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Options:   options,
+	}
+	mock.lockPostMessageContext.Lock()
+	mock.calls.PostMessageContext = append(mock.calls.PostMessageContext, callInfo)
+	mock.lockPostMessageContext.Unlock()
+	return mock.PostMessageContextFunc(ctx, channelID, options...)
+}
+
+// PostMessageContextCalls gets all the calls that were made to PostMessageContext.
+// Check the length with:
+//
+//	len(mockedSlack.PostMessageContextCalls())
+func (mock *SlackMock) PostMessageContextCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	Options   []slack.MsgOption
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		Options   []slack.MsgOption
+	}
+	mock.lockPostMessageContext.RLock()
+	calls = mock.calls.PostMessageContext
+	mock.lockPostMessageContext.RUnlock()
+	return calls
+}
+
+// UpdateMessageContext calls UpdateMessageContextFunc.
+func (mock *SlackMock) UpdateMessageContext(ctx context.Context, channelID string, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	if mock.UpdateMessageContextFunc == nil {
+		panic("SlackMock.UpdateMessageContextFunc: method is nil but Slack.UpdateMessageContext was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ChannelID string
+		Timestamp string
+		Options   []slack.MsgOption
+	}{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Timestamp: timestamp,
+		Options:   options,
+	}
+	mock.lockUpdateMessageContext.Lock()
+	mock.calls.UpdateMessageContext = append(mock.calls.UpdateMessageContext, callInfo)
+	mock.lockUpdateMessageContext.Unlock()
+	return mock.UpdateMessageContextFunc(ctx, channelID, timestamp, options...)
+}
+
+// UpdateMessageContextCalls gets all the calls that were made to UpdateMessageContext.
+// Check the length with:
+//
+//	len(mockedSlack.UpdateMessageContextCalls())
+func (mock *SlackMock) UpdateMessageContextCalls() []struct {
+	Ctx       context.Context
+	ChannelID string
+	Timestamp string
+	Options   []slack.MsgOption
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ChannelID string
+		Timestamp string
+		Options   []slack.MsgOption
+	}
+	mock.lockUpdateMessageContext.RLock()
+	calls = mock.calls.UpdateMessageContext
+	mock.lockUpdateMessageContext.RUnlock()
+	return calls
+}
+
+// UploadFileV2Context calls UploadFileV2ContextFunc.
+func (mock *SlackMock) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	if mock.UploadFileV2ContextFunc == nil {
+		panic("SlackMock.UploadFileV2ContextFunc: method is nil but Slack.UploadFileV2Context was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Params slack.UploadFileV2Parameters
+	}{
+		Ctx:    ctx,
+		Params: params,
+	}
+	mock.lockUploadFileV2Context.Lock()
+	mock.calls.UploadFileV2Context = append(mock.calls.UploadFileV2Context, callInfo)
+	mock.lockUploadFileV2Context.Unlock()
+	return mock.UploadFileV2ContextFunc(ctx, params)
+}
+
+// UploadFileV2ContextCalls gets all the calls that were made to UploadFileV2Context.
+// Check the length with:
+//
+//	len(mockedSlack.UploadFileV2ContextCalls())
+func (mock *SlackMock) UploadFileV2ContextCalls() []struct {
+	Ctx    context.Context
+	Params slack.UploadFileV2Parameters
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Params slack.UploadFileV2Parameters
+	}
+	mock.lockUploadFileV2Context.RLock()
+	calls = mock.calls.UploadFileV2Context
+	mock.lockUploadFileV2Context.RUnlock()
+	return calls
+}
+
+// Ensure, that SlackThreadStoreMock does implement interfaces.SlackThreadStore.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.SlackThreadStore = &SlackThreadStoreMock{}
+
+// SlackThreadStoreMock is a mock implementation of interfaces.SlackThreadStore.
+//
+//	func TestSomethingThatUsesSlackThreadStore(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.SlackThreadStore
+//		mockedSlackThreadStore := &SlackThreadStoreMock{
+//			ClaimFunc: func(ctx context.Context, channel string, key string, ttl time.Duration) (bool, error) {
+//				panic("mock out the Claim method")
+//			},
+//			GetFunc: func(ctx context.Context, channel string, key string) (*model.SlackThread, error) {
+//				panic("mock out the Get method")
+//			},
+//			PutFunc: func(ctx context.Context, channel string, key string, thread *model.SlackThread) error {
+//				panic("mock out the Put method")
+//			},
+//			ReleaseFunc: func(ctx context.Context, channel string, key string) error {
+//				panic("mock out the Release method")
+//			},
+//		}
+//
+//		// use mockedSlackThreadStore in code that requires interfaces.SlackThreadStore
+//		// and then make assertions.
+//
+//	}
+type SlackThreadStoreMock struct {
+	// ClaimFunc mocks the Claim method.
+	ClaimFunc func(ctx context.Context, channel string, key string, ttl time.Duration) (bool, error)
+
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, channel string, key string) (*model.SlackThread, error)
+
+	// PutFunc mocks the Put method.
+	PutFunc func(ctx context.Context, channel string, key string, thread *model.SlackThread) error
+
+	// ReleaseFunc mocks the Release method.
+	ReleaseFunc func(ctx context.Context, channel string, key string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Claim holds details about calls to the Claim method.
+		Claim []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel string
+			// Key is the key argument value.
+			Key string
+			// TTL is the ttl argument value.
+			TTL time.Duration
+		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel string
+			// Key is the key argument value.
+			Key string
+		}
+		// Put holds details about calls to the Put method.
+		Put []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel string
+			// Key is the key argument value.
+			Key string
+			// Thread is the thread argument value.
+			Thread *model.SlackThread
+		}
+		// Release holds details about calls to the Release method.
+		Release []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel string
+			// Key is the key argument value.
+			Key string
+		}
+	}
+	lockClaim   sync.RWMutex
+	lockGet     sync.RWMutex
+	lockPut     sync.RWMutex
+	lockRelease sync.RWMutex
+}
+
+// Claim calls ClaimFunc.
+func (mock *SlackThreadStoreMock) Claim(ctx context.Context, channel string, key string, ttl time.Duration) (bool, error) {
+	if mock.ClaimFunc == nil {
+		panic("SlackThreadStoreMock.ClaimFunc: method is nil but SlackThreadStore.Claim was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+		TTL     time.Duration
+	}{
+		Ctx:     ctx,
+		Channel: channel,
+		Key:     key,
+		TTL:     ttl,
+	}
+	mock.lockClaim.Lock()
+	mock.calls.Claim = append(mock.calls.Claim, callInfo)
+	mock.lockClaim.Unlock()
+	return mock.ClaimFunc(ctx, channel, key, ttl)
+}
+
+// ClaimCalls gets all the calls that were made to Claim.
+// Check the length with:
+//
+//	len(mockedSlackThreadStore.ClaimCalls())
+func (mock *SlackThreadStoreMock) ClaimCalls() []struct {
+	Ctx     context.Context
+	Channel string
+	Key     string
+	TTL     time.Duration
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+		TTL     time.Duration
+	}
+	mock.lockClaim.RLock()
+	calls = mock.calls.Claim
+	mock.lockClaim.RUnlock()
+	return calls
+}
+
+// Get calls GetFunc.
+func (mock *SlackThreadStoreMock) Get(ctx context.Context, channel string, key string) (*model.SlackThread, error) {
+	if mock.GetFunc == nil {
+		panic("SlackThreadStoreMock.GetFunc: method is nil but SlackThreadStore.Get was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+	}{
+		Ctx:     ctx,
+		Channel: channel,
+		Key:     key,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, channel, key)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedSlackThreadStore.GetCalls())
+func (mock *SlackThreadStoreMock) GetCalls() []struct {
+	Ctx     context.Context
+	Channel string
+	Key     string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// Put calls PutFunc.
+func (mock *SlackThreadStoreMock) Put(ctx context.Context, channel string, key string, thread *model.SlackThread) error {
+	if mock.PutFunc == nil {
+		panic("SlackThreadStoreMock.PutFunc: method is nil but SlackThreadStore.Put was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+		Thread  *model.SlackThread
+	}{
+		Ctx:     ctx,
+		Channel: channel,
+		Key:     key,
+		Thread:  thread,
+	}
+	mock.lockPut.Lock()
+	mock.calls.Put = append(mock.calls.Put, callInfo)
+	mock.lockPut.Unlock()
+	return mock.PutFunc(ctx, channel, key, thread)
+}
+
+// PutCalls gets all the calls that were made to Put.
+// Check the length with:
+//
+//	len(mockedSlackThreadStore.PutCalls())
+func (mock *SlackThreadStoreMock) PutCalls() []struct {
+	Ctx     context.Context
+	Channel string
+	Key     string
+	Thread  *model.SlackThread
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+		Thread  *model.SlackThread
+	}
+	mock.lockPut.RLock()
+	calls = mock.calls.Put
+	mock.lockPut.RUnlock()
+	return calls
+}
+
+// Release calls ReleaseFunc.
+func (mock *SlackThreadStoreMock) Release(ctx context.Context, channel string, key string) error {
+	if mock.ReleaseFunc == nil {
+		panic("SlackThreadStoreMock.ReleaseFunc: method is nil but SlackThreadStore.Release was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+	}{
+		Ctx:     ctx,
+		Channel: channel,
+		Key:     key,
+	}
+	mock.lockRelease.Lock()
+	mock.calls.Release = append(mock.calls.Release, callInfo)
+	mock.lockRelease.Unlock()
+	return mock.ReleaseFunc(ctx, channel, key)
+}
+
+// ReleaseCalls gets all the calls that were made to Release.
+// Check the length with:
+//
+//	len(mockedSlackThreadStore.ReleaseCalls())
+func (mock *SlackThreadStoreMock) ReleaseCalls() []struct {
+	Ctx     context.Context
+	Channel string
+	Key     string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Channel string
+		Key     string
+	}
+	mock.lockRelease.RLock()
+	calls = mock.calls.Release
+	mock.lockRelease.RUnlock()
+	return calls
+}
+
+// Ensure, that TeamsMock does implement interfaces.Teams.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.Teams = &TeamsMock{}
+
+// TeamsMock is a mock implementation of interfaces.Teams.
+//
+//	func TestSomethingThatUsesTeams(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.Teams
+//		mockedTeams := &TeamsMock{
+//			PostFunc: func(ctx context.Context, channel string, payload any) error {
+//				panic("mock out the Post method")
+//			},
+//		}
+//
+//		// use mockedTeams in code that requires interfaces.Teams
+//		// and then make assertions.
+//
+//	}
+type TeamsMock struct {
+	// PostFunc mocks the Post method.
+	PostFunc func(ctx context.Context, channel string, payload any) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Post holds details about calls to the Post method.
+		Post []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel string
+			// Payload is the payload argument value.
+			Payload any
+		}
+	}
+	lockPost sync.RWMutex
+}
+
+// Post calls PostFunc.
+func (mock *TeamsMock) Post(ctx context.Context, channel string, payload any) error {
+	if mock.PostFunc == nil {
+		panic("TeamsMock.PostFunc: method is nil but Teams.Post was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Channel string
+		Payload any
+	}{
+		Ctx:     ctx,
+		Channel: channel,
+		Payload: payload,
+	}
+	mock.lockPost.Lock()
+	mock.calls.Post = append(mock.calls.Post, callInfo)
+	mock.lockPost.Unlock()
+	return mock.PostFunc(ctx, channel, payload)
+}
+
+// PostCalls gets all the calls that were made to Post.
+// Check the length with:
+//
+//	len(mockedTeams.PostCalls())
+func (mock *TeamsMock) PostCalls() []struct {
+	Ctx     context.Context
+	Channel string
+	Payload any
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Channel string
+		Payload any
+	}
+	mock.lockPost.RLock()
+	calls = mock.calls.Post
+	mock.lockPost.RUnlock()
+	return calls
+}
+
+// Ensure, that DiscordMock does implement interfaces.Discord.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.Discord = &DiscordMock{}
+
+// DiscordMock is a mock implementation of interfaces.Discord.
+//
+//	func TestSomethingThatUsesDiscord(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.Discord
+//		mockedDiscord := &DiscordMock{
+//			PostFunc: func(ctx context.Context, channel string, payload any) error {
+//				panic("mock out the Post method")
+//			},
+//		}
+//
+//		// use mockedDiscord in code that requires interfaces.Discord
+//		// and then make assertions.
+//
+//	}
+type DiscordMock struct {
+	// PostFunc mocks the Post method.
+	PostFunc func(ctx context.Context, channel string, payload any) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Post holds details about calls to the Post method.
+		Post []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Channel is the channel argument value.
+			Channel string
+			// Payload is the payload argument value.
+			Payload any
+		}
+	}
+	lockPost sync.RWMutex
+}
+
+// Post calls PostFunc.
+func (mock *DiscordMock) Post(ctx context.Context, channel string, payload any) error {
+	if mock.PostFunc == nil {
+		panic("DiscordMock.PostFunc: method is nil but Discord.Post was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Channel string
+		Payload any
+	}{
+		Ctx:     ctx,
+		Channel: channel,
+		Payload: payload,
+	}
+	mock.lockPost.Lock()
+	mock.calls.Post = append(mock.calls.Post, callInfo)
+	mock.lockPost.Unlock()
+	return mock.PostFunc(ctx, channel, payload)
+}
+
+// PostCalls gets all the calls that were made to Post.
+// Check the length with:
+//
+//	len(mockedDiscord.PostCalls())
+func (mock *DiscordMock) PostCalls() []struct {
+	Ctx     context.Context
+	Channel string
+	Payload any
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Channel string
+		Payload any
+	}
+	mock.lockPost.RLock()
+	calls = mock.calls.Post
+	mock.lockPost.RUnlock()
+	return calls
+}
+
+// Ensure, that EmailMock does implement interfaces.Email.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.Email = &EmailMock{}
+
+// EmailMock is a mock implementation of interfaces.Email.
+//
+//	func TestSomethingThatUsesEmail(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.Email
+//		mockedEmail := &EmailMock{
+//			SendFunc: func(ctx context.Context, mail *model.Email) error {
+//				panic("mock out the Send method")
+//			},
+//		}
+//
+//		// use mockedEmail in code that requires interfaces.Email
+//		// and then make assertions.
+//
+//	}
+type EmailMock struct {
+	// SendFunc mocks the Send method.
+	SendFunc func(ctx context.Context, mail *model.Email) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Send holds details about calls to the Send method.
+		Send []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Mail is the mail argument value.
+			Mail *model.Email
+		}
+	}
+	lockSend sync.RWMutex
+}
+
+// Send calls SendFunc.
+func (mock *EmailMock) Send(ctx context.Context, mail *model.Email) error {
+	if mock.SendFunc == nil {
+		panic("EmailMock.SendFunc: method is nil but Email.Send was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Mail *model.Email
+	}{
+		Ctx:  ctx,
+		Mail: mail,
+	}
+	mock.lockSend.Lock()
+	mock.calls.Send = append(mock.calls.Send, callInfo)
+	mock.lockSend.Unlock()
+	return mock.SendFunc(ctx, mail)
+}
+
+// SendCalls gets all the calls that were made to Send.
+// Check the length with:
+//
+//	len(mockedEmail.SendCalls())
+func (mock *EmailMock) SendCalls() []struct {
+	Ctx  context.Context
+	Mail *model.Email
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Mail *model.Email
+	}
+	mock.lockSend.RLock()
+	calls = mock.calls.Send
+	mock.lockSend.RUnlock()
+	return calls
+}
+
+// Ensure, that PagerDutyMock does implement interfaces.PagerDuty.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.PagerDuty = &PagerDutyMock{}
+
+// PagerDutyMock is a mock implementation of interfaces.PagerDuty.
+//
+//	func TestSomethingThatUsesPagerDuty(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.PagerDuty
+//		mockedPagerDuty := &PagerDutyMock{
+//			EnqueueFunc: func(ctx context.Context, routingKey string, event map[string]any) error {
+//				panic("mock out the Enqueue method")
+//			},
+//		}
+//
+//		// use mockedPagerDuty in code that requires interfaces.PagerDuty
+//		// and then make assertions.
+//
+//	}
+type PagerDutyMock struct {
+	// EnqueueFunc mocks the Enqueue method.
+	EnqueueFunc func(ctx context.Context, routingKey string, event map[string]any) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Enqueue holds details about calls to the Enqueue method.
+		Enqueue []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// RoutingKey is the routingKey argument value.
+			RoutingKey string
+			// Event is the event argument value.
+			Event map[string]any
+		}
+	}
+	lockEnqueue sync.RWMutex
+}
+
+// Enqueue calls EnqueueFunc.
+func (mock *PagerDutyMock) Enqueue(ctx context.Context, routingKey string, event map[string]any) error {
+	if mock.EnqueueFunc == nil {
+		panic("PagerDutyMock.EnqueueFunc: method is nil but PagerDuty.Enqueue was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		RoutingKey string
+		Event      map[string]any
+	}{
+		Ctx:        ctx,
+		RoutingKey: routingKey,
+		Event:      event,
+	}
+	mock.lockEnqueue.Lock()
+	mock.calls.Enqueue = append(mock.calls.Enqueue, callInfo)
+	mock.lockEnqueue.Unlock()
+	return mock.EnqueueFunc(ctx, routingKey, event)
+}
+
+// EnqueueCalls gets all the calls that were made to Enqueue.
+// Check the length with:
+//
+//	len(mockedPagerDuty.EnqueueCalls())
+func (mock *PagerDutyMock) EnqueueCalls() []struct {
+	Ctx        context.Context
+	RoutingKey string
+	Event      map[string]any
+} {
+	var calls []struct {
+		Ctx        context.Context
+		RoutingKey string
+		Event      map[string]any
+	}
+	mock.lockEnqueue.RLock()
+	calls = mock.calls.Enqueue
+	mock.lockEnqueue.RUnlock()
+	return calls
+}
+
+// Ensure, that GitHubMock does implement interfaces.GitHub.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.GitHub = &GitHubMock{}
+
+// GitHubMock is a mock implementation of interfaces.GitHub.
+//
+//	func TestSomethingThatUsesGitHub(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.GitHub
+//		mockedGitHub := &GitHubMock{
+//			CreateCommentFunc: func(ctx context.Context, owner string, repo string, number int, body string) (*github.IssueComment, error) {
+//				panic("mock out the CreateComment method")
+//			},
+//			CreateIssueFunc: func(ctx context.Context, owner string, repo string, req *github.IssueRequest) (*github.Issue, error) {
+//				panic("mock out the CreateIssue method")
+//			},
+//			ListOpenIssuesFunc: func(ctx context.Context, owner string, repo string, labels []string) ([]*github.Issue, error) {
+//				panic("mock out the ListOpenIssues method")
+//			},
+//		}
+//
+//		// use mockedGitHub in code that requires interfaces.GitHub
+//		// and then make assertions.
+//
+//	}
+type GitHubMock struct {
+	// CreateCommentFunc mocks the CreateComment method.
+	CreateCommentFunc func(ctx context.Context, owner string, repo string, number int, body string) (*github.IssueComment, error)
+
+	// CreateIssueFunc mocks the CreateIssue method.
+	CreateIssueFunc func(ctx context.Context, owner string, repo string, req *github.IssueRequest) (*github.Issue, error)
+
+	// ListOpenIssuesFunc mocks the ListOpenIssues method.
+	ListOpenIssuesFunc func(ctx context.Context, owner string, repo string, labels []string) ([]*github.Issue, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
-		// PostMessageContext holds details about calls to the PostMessageContext method.
-		PostMessageContext []struct {
+		// CreateComment holds details about calls to the CreateComment method.
+		CreateComment []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
-			// ChannelID is the channelID argument value.
-			ChannelID string
-			// Options is the options argument value.
-			Options []slack.MsgOption
+			// Owner is the owner argument value.
+			Owner string
+			// Repo is the repo argument value.
+			Repo string
+			// Number is the number argument value.
+			Number int
+			// Body is the body argument value.
+			Body string
+		}
+		// CreateIssue holds details about calls to the CreateIssue method.
+		CreateIssue []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Owner is the owner argument value.
+			Owner string
+			// Repo is the repo argument value.
+			Repo string
+			// Req is the req argument value.
+			Req *github.IssueRequest
+		}
+		// ListOpenIssues holds details about calls to the ListOpenIssues method.
+		ListOpenIssues []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Owner is the owner argument value.
+			Owner string
+			// Repo is the repo argument value.
+			Repo string
+			// Labels is the labels argument value.
+			Labels []string
 		}
 	}
-	lockPostMessageContext sync.RWMutex
+	lockCreateComment  sync.RWMutex
+	lockCreateIssue    sync.RWMutex
+	lockListOpenIssues sync.RWMutex
 }
 
-// PostMessageContext calls PostMessageContextFunc.
-func (mock *SlackMock) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
-	if mock.PostMessageContextFunc == nil {
-		panic("SlackMock.PostMessageContextFunc: method is nil but Slack.PostMessageContext was just called")
+// CreateComment calls CreateCommentFunc.
+func (mock *GitHubMock) CreateComment(ctx context.Context, owner string, repo string, number int, body string) (*github.IssueComment, error) {
+	if mock.CreateCommentFunc == nil {
+		panic("GitHubMock.CreateCommentFunc: method is nil but GitHub.CreateComment was just called")
 	}
 	callInfo := struct {
-		Ctx       context.Context
-		ChannelID string
-		Options   []slack.MsgOption
+		Ctx    context.Context
+		Owner  string
+		Repo   string
+		Number int
+		Body   string
 	}{
-		Ctx:       ctx,
-		ChannelID: channelID,
-		Options:   options,
+		Ctx:    ctx,
+		Owner:  owner,
+		Repo:   repo,
+		Number: number,
+		Body:   body,
 	}
-	mock.lockPostMessageContext.Lock()
-	mock.calls.PostMessageContext = append(mock.calls.PostMessageContext, callInfo)
-	mock.lockPostMessageContext.Unlock()
-	return mock.PostMessageContextFunc(ctx, channelID, options...)
+	mock.lockCreateComment.Lock()
+	mock.calls.CreateComment = append(mock.calls.CreateComment, callInfo)
+	mock.lockCreateComment.Unlock()
+	return mock.CreateCommentFunc(ctx, owner, repo, number, body)
 }
 
-// PostMessageContextCalls gets all the calls that were made to PostMessageContext.
+// CreateCommentCalls gets all the calls that were made to CreateComment.
 // Check the length with:
 //
-//	len(mockedSlack.PostMessageContextCalls())
-func (mock *SlackMock) PostMessageContextCalls() []struct {
-	Ctx       context.Context
-	ChannelID string
-	Options   []slack.MsgOption
+//	len(mockedGitHub.CreateCommentCalls())
+func (mock *GitHubMock) CreateCommentCalls() []struct {
+	Ctx    context.Context
+	Owner  string
+	Repo   string
+	Number int
+	Body   string
 } {
 	var calls []struct {
-		Ctx       context.Context
-		ChannelID string
-		Options   []slack.MsgOption
+		Ctx    context.Context
+		Owner  string
+		Repo   string
+		Number int
+		Body   string
 	}
-	mock.lockPostMessageContext.RLock()
-	calls = mock.calls.PostMessageContext
-	mock.lockPostMessageContext.RUnlock()
+	mock.lockCreateComment.RLock()
+	calls = mock.calls.CreateComment
+	mock.lockCreateComment.RUnlock()
+	return calls
+}
+
+// CreateIssue calls CreateIssueFunc.
+func (mock *GitHubMock) CreateIssue(ctx context.Context, owner string, repo string, req *github.IssueRequest) (*github.Issue, error) {
+	if mock.CreateIssueFunc == nil {
+		panic("GitHubMock.CreateIssueFunc: method is nil but GitHub.CreateIssue was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Owner string
+		Repo  string
+		Req   *github.IssueRequest
+	}{
+		Ctx:   ctx,
+		Owner: owner,
+		Repo:  repo,
+		Req:   req,
+	}
+	mock.lockCreateIssue.Lock()
+	mock.calls.CreateIssue = append(mock.calls.CreateIssue, callInfo)
+	mock.lockCreateIssue.Unlock()
+	return mock.CreateIssueFunc(ctx, owner, repo, req)
+}
+
+// CreateIssueCalls gets all the calls that were made to CreateIssue.
+// Check the length with:
+//
+//	len(mockedGitHub.CreateIssueCalls())
+func (mock *GitHubMock) CreateIssueCalls() []struct {
+	Ctx   context.Context
+	Owner string
+	Repo  string
+	Req   *github.IssueRequest
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Owner string
+		Repo  string
+		Req   *github.IssueRequest
+	}
+	mock.lockCreateIssue.RLock()
+	calls = mock.calls.CreateIssue
+	mock.lockCreateIssue.RUnlock()
+	return calls
+}
+
+// ListOpenIssues calls ListOpenIssuesFunc.
+func (mock *GitHubMock) ListOpenIssues(ctx context.Context, owner string, repo string, labels []string) ([]*github.Issue, error) {
+	if mock.ListOpenIssuesFunc == nil {
+		panic("GitHubMock.ListOpenIssuesFunc: method is nil but GitHub.ListOpenIssues was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Owner  string
+		Repo   string
+		Labels []string
+	}{
+		Ctx:    ctx,
+		Owner:  owner,
+		Repo:   repo,
+		Labels: labels,
+	}
+	mock.lockListOpenIssues.Lock()
+	mock.calls.ListOpenIssues = append(mock.calls.ListOpenIssues, callInfo)
+	mock.lockListOpenIssues.Unlock()
+	return mock.ListOpenIssuesFunc(ctx, owner, repo, labels)
+}
+
+// ListOpenIssuesCalls gets all the calls that were made to ListOpenIssues.
+// Check the length with:
+//
+//	len(mockedGitHub.ListOpenIssuesCalls())
+func (mock *GitHubMock) ListOpenIssuesCalls() []struct {
+	Ctx    context.Context
+	Owner  string
+	Repo   string
+	Labels []string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Owner  string
+		Repo   string
+		Labels []string
+	}
+	mock.lockListOpenIssues.RLock()
+	calls = mock.calls.ListOpenIssues
+	mock.lockListOpenIssues.RUnlock()
 	return calls
 }
 
@@ -180,6 +1173,72 @@ func (mock *PolicyMock) QueryCalls() []struct {
 	return calls
 }
 
+// Ensure, that HTTPClientMock does implement interfaces.HTTPClient.
+// If this is not the case, regenerate this file with moq.
+var _ interfaces.HTTPClient = &HTTPClientMock{}
+
+// HTTPClientMock is a mock implementation of interfaces.HTTPClient.
+//
+//	func TestSomethingThatUsesHTTPClient(t *testing.T) {
+//
+//		// make and configure a mocked interfaces.HTTPClient
+//		mockedHTTPClient := &HTTPClientMock{
+//			DoFunc: func(req *http.Request) (*http.Response, error) {
+//				panic("mock out the Do method")
+//			},
+//		}
+//
+//		// use mockedHTTPClient in code that requires interfaces.HTTPClient
+//		// and then make assertions.
+//
+//	}
+type HTTPClientMock struct {
+	// DoFunc mocks the Do method.
+	DoFunc func(req *http.Request) (*http.Response, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Do holds details about calls to the Do method.
+		Do []struct {
+			// Req is the req argument value.
+			Req *http.Request
+		}
+	}
+	lockDo sync.RWMutex
+}
+
+// Do calls DoFunc.
+func (mock *HTTPClientMock) Do(req *http.Request) (*http.Response, error) {
+	if mock.DoFunc == nil {
+		panic("HTTPClientMock.DoFunc: method is nil but HTTPClient.Do was just called")
+	}
+	callInfo := struct {
+		Req *http.Request
+	}{
+		Req: req,
+	}
+	mock.lockDo.Lock()
+	mock.calls.Do = append(mock.calls.Do, callInfo)
+	mock.lockDo.Unlock()
+	return mock.DoFunc(req)
+}
+
+// DoCalls gets all the calls that were made to Do.
+// Check the length with:
+//
+//	len(mockedHTTPClient.DoCalls())
+func (mock *HTTPClientMock) DoCalls() []struct {
+	Req *http.Request
+} {
+	var calls []struct {
+		Req *http.Request
+	}
+	mock.lockDo.RLock()
+	calls = mock.calls.Do
+	mock.lockDo.RUnlock()
+	return calls
+}
+
 // Ensure, that UseCasesMock does implement interfaces.UseCases.
 // If this is not the case, regenerate this file with moq.
 var _ interfaces.UseCases = &UseCasesMock{}
@@ -190,6 +1249,27 @@ var _ interfaces.UseCases = &UseCasesMock{}
 //
 //		// make and configure a mocked interfaces.UseCases
 //		mockedUseCases := &UseCasesMock{
+//			AuthorizeFunc: func(ctx context.Context, input model.PolicyAuthzInput) error {
+//				panic("mock out the Authorize method")
+//			},
+//			DeleteDeadLetterFunc: func(ctx context.Context, id string) error {
+//				panic("mock out the DeleteDeadLetter method")
+//			},
+//			GetDeadLetterFunc: func(ctx context.Context, id string) (*model.DeadLetter, error) {
+//				panic("mock out the GetDeadLetter method")
+//			},
+//			ListDeadLettersFunc: func(ctx context.Context) ([]*model.DeadLetter, error) {
+//				panic("mock out the ListDeadLetters method")
+//			},
+//			PolicyStatusFunc: func(ctx context.Context) (*model.PolicyStatus, error) {
+//				panic("mock out the PolicyStatus method")
+//			},
+//			ReloadPolicyFunc: func(ctx context.Context, trigger string) error {
+//				panic("mock out the ReloadPolicy method")
+//			},
+//			ReplayDeadLetterFunc: func(ctx context.Context, id string) error {
+//				panic("mock out the ReplayDeadLetter method")
+//			},
 //			RouteFunc: func(ctx context.Context, msg model.Message) error {
 //				panic("mock out the Route method")
 //			},
@@ -200,11 +1280,77 @@ var _ interfaces.UseCases = &UseCasesMock{}
 //
 //	}
 type UseCasesMock struct {
+	// AuthorizeFunc mocks the Authorize method.
+	AuthorizeFunc func(ctx context.Context, input model.PolicyAuthzInput) error
+
+	// DeleteDeadLetterFunc mocks the DeleteDeadLetter method.
+	DeleteDeadLetterFunc func(ctx context.Context, id string) error
+
+	// GetDeadLetterFunc mocks the GetDeadLetter method.
+	GetDeadLetterFunc func(ctx context.Context, id string) (*model.DeadLetter, error)
+
+	// ListDeadLettersFunc mocks the ListDeadLetters method.
+	ListDeadLettersFunc func(ctx context.Context) ([]*model.DeadLetter, error)
+
+	// PolicyStatusFunc mocks the PolicyStatus method.
+	PolicyStatusFunc func(ctx context.Context) (*model.PolicyStatus, error)
+
+	// ReloadPolicyFunc mocks the ReloadPolicy method.
+	ReloadPolicyFunc func(ctx context.Context, trigger string) error
+
+	// ReplayDeadLetterFunc mocks the ReplayDeadLetter method.
+	ReplayDeadLetterFunc func(ctx context.Context, id string) error
+
 	// RouteFunc mocks the Route method.
 	RouteFunc func(ctx context.Context, msg model.Message) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// Authorize holds details about calls to the Authorize method.
+		Authorize []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input model.PolicyAuthzInput
+		}
+		// DeleteDeadLetter holds details about calls to the DeleteDeadLetter method.
+		DeleteDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
+		// GetDeadLetter holds details about calls to the GetDeadLetter method.
+		GetDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
+		// ListDeadLetters holds details about calls to the ListDeadLetters method.
+		ListDeadLetters []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// PolicyStatus holds details about calls to the PolicyStatus method.
+		PolicyStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ReloadPolicy holds details about calls to the ReloadPolicy method.
+		ReloadPolicy []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Trigger is the trigger argument value.
+			Trigger string
+		}
+		// ReplayDeadLetter holds details about calls to the ReplayDeadLetter method.
+		ReplayDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
 		// Route holds details about calls to the Route method.
 		Route []struct {
 			// Ctx is the ctx argument value.
@@ -213,7 +1359,258 @@ type UseCasesMock struct {
 			Msg model.Message
 		}
 	}
-	lockRoute sync.RWMutex
+	lockAuthorize        sync.RWMutex
+	lockDeleteDeadLetter sync.RWMutex
+	lockGetDeadLetter    sync.RWMutex
+	lockListDeadLetters  sync.RWMutex
+	lockPolicyStatus     sync.RWMutex
+	lockReloadPolicy     sync.RWMutex
+	lockReplayDeadLetter sync.RWMutex
+	lockRoute            sync.RWMutex
+}
+
+// Authorize calls AuthorizeFunc.
+func (mock *UseCasesMock) Authorize(ctx context.Context, input model.PolicyAuthzInput) error {
+	if mock.AuthorizeFunc == nil {
+		panic("UseCasesMock.AuthorizeFunc: method is nil but UseCases.Authorize was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input model.PolicyAuthzInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockAuthorize.Lock()
+	mock.calls.Authorize = append(mock.calls.Authorize, callInfo)
+	mock.lockAuthorize.Unlock()
+	return mock.AuthorizeFunc(ctx, input)
+}
+
+// AuthorizeCalls gets all the calls that were made to Authorize.
+// Check the length with:
+//
+//	len(mockedUseCases.AuthorizeCalls())
+func (mock *UseCasesMock) AuthorizeCalls() []struct {
+	Ctx   context.Context
+	Input model.PolicyAuthzInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input model.PolicyAuthzInput
+	}
+	mock.lockAuthorize.RLock()
+	calls = mock.calls.Authorize
+	mock.lockAuthorize.RUnlock()
+	return calls
+}
+
+// DeleteDeadLetter calls DeleteDeadLetterFunc.
+func (mock *UseCasesMock) DeleteDeadLetter(ctx context.Context, id string) error {
+	if mock.DeleteDeadLetterFunc == nil {
+		panic("UseCasesMock.DeleteDeadLetterFunc: method is nil but UseCases.DeleteDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteDeadLetter.Lock()
+	mock.calls.DeleteDeadLetter = append(mock.calls.DeleteDeadLetter, callInfo)
+	mock.lockDeleteDeadLetter.Unlock()
+	return mock.DeleteDeadLetterFunc(ctx, id)
+}
+
+// DeleteDeadLetterCalls gets all the calls that were made to DeleteDeadLetter.
+// Check the length with:
+//
+//	len(mockedUseCases.DeleteDeadLetterCalls())
+func (mock *UseCasesMock) DeleteDeadLetterCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockDeleteDeadLetter.RLock()
+	calls = mock.calls.DeleteDeadLetter
+	mock.lockDeleteDeadLetter.RUnlock()
+	return calls
+}
+
+// GetDeadLetter calls GetDeadLetterFunc.
+func (mock *UseCasesMock) GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	if mock.GetDeadLetterFunc == nil {
+		panic("UseCasesMock.GetDeadLetterFunc: method is nil but UseCases.GetDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetDeadLetter.Lock()
+	mock.calls.GetDeadLetter = append(mock.calls.GetDeadLetter, callInfo)
+	mock.lockGetDeadLetter.Unlock()
+	return mock.GetDeadLetterFunc(ctx, id)
+}
+
+// GetDeadLetterCalls gets all the calls that were made to GetDeadLetter.
+// Check the length with:
+//
+//	len(mockedUseCases.GetDeadLetterCalls())
+func (mock *UseCasesMock) GetDeadLetterCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockGetDeadLetter.RLock()
+	calls = mock.calls.GetDeadLetter
+	mock.lockGetDeadLetter.RUnlock()
+	return calls
+}
+
+// ListDeadLetters calls ListDeadLettersFunc.
+func (mock *UseCasesMock) ListDeadLetters(ctx context.Context) ([]*model.DeadLetter, error) {
+	if mock.ListDeadLettersFunc == nil {
+		panic("UseCasesMock.ListDeadLettersFunc: method is nil but UseCases.ListDeadLetters was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListDeadLetters.Lock()
+	mock.calls.ListDeadLetters = append(mock.calls.ListDeadLetters, callInfo)
+	mock.lockListDeadLetters.Unlock()
+	return mock.ListDeadLettersFunc(ctx)
+}
+
+// ListDeadLettersCalls gets all the calls that were made to ListDeadLetters.
+// Check the length with:
+//
+//	len(mockedUseCases.ListDeadLettersCalls())
+func (mock *UseCasesMock) ListDeadLettersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListDeadLetters.RLock()
+	calls = mock.calls.ListDeadLetters
+	mock.lockListDeadLetters.RUnlock()
+	return calls
+}
+
+// PolicyStatus calls PolicyStatusFunc.
+func (mock *UseCasesMock) PolicyStatus(ctx context.Context) (*model.PolicyStatus, error) {
+	if mock.PolicyStatusFunc == nil {
+		panic("UseCasesMock.PolicyStatusFunc: method is nil but UseCases.PolicyStatus was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockPolicyStatus.Lock()
+	mock.calls.PolicyStatus = append(mock.calls.PolicyStatus, callInfo)
+	mock.lockPolicyStatus.Unlock()
+	return mock.PolicyStatusFunc(ctx)
+}
+
+// PolicyStatusCalls gets all the calls that were made to PolicyStatus.
+// Check the length with:
+//
+//	len(mockedUseCases.PolicyStatusCalls())
+func (mock *UseCasesMock) PolicyStatusCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockPolicyStatus.RLock()
+	calls = mock.calls.PolicyStatus
+	mock.lockPolicyStatus.RUnlock()
+	return calls
+}
+
+// ReloadPolicy calls ReloadPolicyFunc.
+func (mock *UseCasesMock) ReloadPolicy(ctx context.Context, trigger string) error {
+	if mock.ReloadPolicyFunc == nil {
+		panic("UseCasesMock.ReloadPolicyFunc: method is nil but UseCases.ReloadPolicy was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Trigger string
+	}{
+		Ctx:     ctx,
+		Trigger: trigger,
+	}
+	mock.lockReloadPolicy.Lock()
+	mock.calls.ReloadPolicy = append(mock.calls.ReloadPolicy, callInfo)
+	mock.lockReloadPolicy.Unlock()
+	return mock.ReloadPolicyFunc(ctx, trigger)
+}
+
+// ReloadPolicyCalls gets all the calls that were made to ReloadPolicy.
+// Check the length with:
+//
+//	len(mockedUseCases.ReloadPolicyCalls())
+func (mock *UseCasesMock) ReloadPolicyCalls() []struct {
+	Ctx     context.Context
+	Trigger string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Trigger string
+	}
+	mock.lockReloadPolicy.RLock()
+	calls = mock.calls.ReloadPolicy
+	mock.lockReloadPolicy.RUnlock()
+	return calls
+}
+
+// ReplayDeadLetter calls ReplayDeadLetterFunc.
+func (mock *UseCasesMock) ReplayDeadLetter(ctx context.Context, id string) error {
+	if mock.ReplayDeadLetterFunc == nil {
+		panic("UseCasesMock.ReplayDeadLetterFunc: method is nil but UseCases.ReplayDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockReplayDeadLetter.Lock()
+	mock.calls.ReplayDeadLetter = append(mock.calls.ReplayDeadLetter, callInfo)
+	mock.lockReplayDeadLetter.Unlock()
+	return mock.ReplayDeadLetterFunc(ctx, id)
+}
+
+// ReplayDeadLetterCalls gets all the calls that were made to ReplayDeadLetter.
+// Check the length with:
+//
+//	len(mockedUseCases.ReplayDeadLetterCalls())
+func (mock *UseCasesMock) ReplayDeadLetterCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockReplayDeadLetter.RLock()
+	calls = mock.calls.ReplayDeadLetter
+	mock.lockReplayDeadLetter.RUnlock()
+	return calls
 }
 
 // Route calls RouteFunc.
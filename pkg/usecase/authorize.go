@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+// Authorize evaluates "data.auth" policy with the request information. It returns error tagged with types.ErrTagForbidden if the request is not allowed. If "data.auth" is not defined in the policy, every request is denied.
+func (x *UseCases) Authorize(ctx context.Context, input model.PolicyAuthzInput) error {
+	logger := logging.Extract(ctx)
+
+	var output model.PolicyAuthzOutput
+	if err := x.adaptors.Policy().Query(ctx, "data.auth", input, &output); err != nil {
+		if !errors.Is(err, opac.ErrNoEvalResult) {
+			return goerr.Wrap(err, "Failed to query auth policy", goerr.V("input", input))
+		}
+		logger.Warn("data.auth is not defined in policy, request is denied")
+	}
+	logger.Debug("Auth query result", "input", input, "output", output)
+
+	if !output.Allow {
+		return goerr.New("Request is not allowed by policy",
+			goerr.V("method", input.Method),
+			goerr.V("path", input.Path),
+			goerr.T(types.ErrTagForbidden),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"context"
+	_ "embed"
+	"testing"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+//go:embed testdata/auth.rego
+var policyAuthRego string
+
+func TestAuthorize(t *testing.T) {
+	policy, err := opac.New(opac.Data(map[string]string{
+		"auth.rego": policyAuthRego,
+	}))
+	gt.NoError(t, err)
+	uc := usecase.New(adapter.New(adapter.WithPolicy(policy)))
+
+	testCases := map[string]struct {
+		auth  model.AuthContext
+		allow bool
+	}{
+		"valid GitHub webhook": {
+			auth: model.AuthContext{
+				GitHub: &model.AuthContextGitHub{
+					Webhook: &model.GitHubWebhookAuth{Valid: true},
+				},
+			},
+			allow: true,
+		},
+		"invalid GitHub webhook": {
+			auth: model.AuthContext{
+				GitHub: &model.AuthContextGitHub{
+					Webhook: &model.GitHubWebhookAuth{Valid: false},
+				},
+			},
+			allow: false,
+		},
+		"allowed Google account": {
+			auth: model.AuthContext{
+				Google: &model.GoogleIDToken{Email: "pubsub@example.iam.gserviceaccount.com"},
+			},
+			allow: true,
+		},
+		"no auth": {
+			allow: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := uc.Authorize(context.Background(), model.PolicyAuthzInput{
+				Method: "POST",
+				Path:   "/msg/github/webhook",
+				Auth:   tc.auth,
+			})
+			if tc.allow {
+				gt.NoError(t, err)
+			} else {
+				gt.True(t, goerr.HasTag(err, types.ErrTagForbidden))
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithoutAuthPolicy(t *testing.T) {
+	policy, err := opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyTransmitRego,
+	}))
+	gt.NoError(t, err)
+	uc := usecase.New(adapter.New(adapter.WithPolicy(policy)))
+
+	err = uc.Authorize(context.Background(), model.PolicyAuthzInput{Method: "POST", Path: "/msg/raw/test"})
+	gt.True(t, goerr.HasTag(err, types.ErrTagForbidden))
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+func (x *UseCases) putDeadLetter(ctx context.Context, d *model.Delivery) {
+	store := x.adaptors.DeadLetter()
+	if store == nil {
+		return
+	}
+
+	entry := &model.DeadLetter{
+		ID:       d.ID,
+		Delivery: *d,
+		Error:    d.LastError,
+		FailedAt: time.Now(),
+	}
+	if err := store.Put(ctx, entry); err != nil {
+		logging.Extract(ctx).Error("Failed to save dead-letter", "error", err, "delivery_id", d.ID)
+	}
+}
+
+func (x *UseCases) deadLetterStore() (interfaces.DeadLetterStore, error) {
+	store := x.adaptors.DeadLetter()
+	if store == nil {
+		return nil, goerr.New("Dead-letter store is not configured", goerr.T(types.ErrTagNotFound))
+	}
+	return store, nil
+}
+
+// ListDeadLetters returns all given up deliveries.
+func (x *UseCases) ListDeadLetters(ctx context.Context) ([]*model.DeadLetter, error) {
+	store, err := x.deadLetterStore()
+	if err != nil {
+		return nil, err
+	}
+
+	return store.List(ctx)
+}
+
+// GetDeadLetter returns the given up delivery. It returns error tagged with types.ErrTagNotFound if the entry does not exist.
+func (x *UseCases) GetDeadLetter(ctx context.Context, id string) (*model.DeadLetter, error) {
+	store, err := x.deadLetterStore()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, goerr.New("Dead-letter not found", goerr.V("id", id), goerr.T(types.ErrTagNotFound))
+	}
+
+	return entry, nil
+}
+
+// DeleteDeadLetter removes the given up delivery.
+func (x *UseCases) DeleteDeadLetter(ctx context.Context, id string) error {
+	store, err := x.deadLetterStore()
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(ctx, id)
+}
+
+// ReplayDeadLetter transmits the given up delivery again, and removes the entry if it's transmitted or enqueued. Only the failed delivery is transmitted, so other destinations of the original message don't receive it twice. If the delivery queue is configured, the delivery is enqueued with reset attempts.
+func (x *UseCases) ReplayDeadLetter(ctx context.Context, id string) error {
+	entry, err := x.GetDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// New ID avoids conflict with the entry if the replayed delivery is given up again before the entry is removed
+	d := entry.Delivery
+	d.ID = uuid.NewString()
+	d.Attempts = 0
+	d.LastError = ""
+	d.NextAttemptAt = time.Now()
+
+	return x.redeliver(ctx, entry, []*model.Delivery{&d})
+}
+
+// RerouteDeadLetter evaluates "data.route" policy again with the original message of the given up delivery, and transmits outputs only to the destination that failed. It's for the case that the policy has been fixed. If the policy outputs multiple messages to the destination, only ones with the same target, e.g. channel, are transmitted. The entry is removed if they are transmitted or enqueued.
+func (x *UseCases) RerouteDeadLetter(ctx context.Context, id string) error {
+	entry, err := x.GetDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	dest := destinationOfDelivery(&entry.Delivery)
+	if dest == nil {
+		return goerr.New("No destination in dead-letter", goerr.V("id", id))
+	}
+
+	output, err := x.Evaluate(ctx, entry.Delivery.Message)
+	if err != nil {
+		return err
+	}
+
+	deliveries := dest.redeliveries(&entry.Delivery, *output, time.Now())
+	if len(deliveries) == 0 {
+		return goerr.New("Policy has no output to the destination of dead-letter", goerr.V("id", id), goerr.V("destination", dest.name()))
+	}
+
+	return x.redeliver(ctx, entry, deliveries)
+}
+
+// redeliver transmits or enqueues deliveries created from the dead-letter, and removes the entry.
+func (x *UseCases) redeliver(ctx context.Context, entry *model.DeadLetter, deliveries []*model.Delivery) error {
+	logger := logging.Extract(ctx)
+	queue := x.adaptors.Queue()
+
+	for _, d := range deliveries {
+		logger.Info("Replay dead-letter", "id", entry.ID, "delivery_id", d.ID, "destination", destinationName(d))
+		if queue != nil {
+			if err := queue.Push(ctx, d); err != nil {
+				return goerr.Wrap(err, "Failed to enqueue dead-letter", goerr.V("id", entry.ID))
+			}
+		} else if err := x.deliver(ctx, d); err != nil {
+			return goerr.Wrap(err, "Failed to replay dead-letter", goerr.V("id", entry.ID))
+		}
+	}
+	if queue != nil {
+		x.notifyDelivery()
+	}
+
+	return x.DeleteDeadLetter(ctx, entry.ID)
+}
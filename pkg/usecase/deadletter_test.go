@@ -0,0 +1,229 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/adapter/deadletter"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/slack-go/slack"
+)
+
+func TestDeadLetterAndReplay(t *testing.T) {
+	ctx := context.Background()
+	broken := true
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			if broken {
+				return "", "", slack.SlackErrorResponse{Err: "invalid_auth"}
+			}
+			return "", "", nil
+		},
+	}
+
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyTransmitRego,
+	}))).NoError(t)
+
+	q := queue.NewMemory()
+	store := deadletter.NewMemory()
+	uc := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithPolicy(policy),
+		adapter.WithQueue(q),
+		adapter.WithDeadLetter(store),
+	))
+
+	gt.NoError(t, uc.Route(ctx, model.Message{Schema: "for_slack", Data: "hello"}))
+	runDelivery(t, uc, func() bool {
+		return q.Len() == 0
+	})
+
+	entries := gt.R1(uc.ListDeadLetters(ctx)).NoError(t)
+	gt.A(t, entries).Length(1).At(0, func(t testing.TB, v *model.DeadLetter) {
+		gt.S(t, v.Error).Contains("invalid_auth")
+		gt.Equal(t, v.Delivery.Attempts, 1)
+		gt.Equal(t, v.Delivery.Message.Schema, "for_slack")
+		gt.Equal(t, v.Delivery.Slack.Channel, "#general")
+	})
+	id := entries[0].ID
+
+	// Token is fixed, replay without queue
+	broken = false
+	replayUC := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithPolicy(policy),
+		adapter.WithDeadLetter(store),
+	))
+	gt.NoError(t, replayUC.ReplayDeadLetter(ctx, id))
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(2)
+	gt.A(t, gt.R1(uc.ListDeadLetters(ctx)).NoError(t)).Length(0)
+
+	err := replayUC.ReplayDeadLetter(ctx, id)
+	gt.True(t, goerr.HasTag(err, types.ErrTagNotFound))
+}
+
+func TestReplayOnlyFailedDestination(t *testing.T) {
+	ctx := context.Background()
+	broken := true
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			if broken {
+				return "", "", slack.SlackErrorResponse{Err: "invalid_auth"}
+			}
+			return "", "", nil
+		},
+	}
+	teamsMock := mock.TeamsMock{
+		PostFunc: func(ctx context.Context, channel string, payload any) error {
+			return nil
+		},
+	}
+
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": `package route
+
+import rego.v1
+
+slack contains {"channel": "#general"}
+
+teams contains {"channel": "alerts", "title": "hello"}
+`,
+	}))).NoError(t)
+
+	q := queue.NewMemory()
+	store := deadletter.NewMemory()
+	uc := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithTeams(&teamsMock),
+		adapter.WithPolicy(policy),
+		adapter.WithQueue(q),
+		adapter.WithDeadLetter(store),
+	))
+
+	// Two messages create sibling dead-letters, and both are replayed like `replay --all`
+	gt.NoError(t, uc.Route(ctx, model.Message{Schema: "first"}))
+	gt.NoError(t, uc.Route(ctx, model.Message{Schema: "second"}))
+	runDelivery(t, uc, func() bool {
+		return q.Len() == 0
+	})
+	gt.A(t, teamsMock.PostCalls()).Length(2)
+
+	entries := gt.R1(uc.ListDeadLetters(ctx)).NoError(t)
+	gt.A(t, entries).Length(2)
+
+	broken = false
+	for _, entry := range entries {
+		gt.NoError(t, uc.ReplayDeadLetter(ctx, entry.ID))
+	}
+	gt.A(t, gt.R1(uc.ListDeadLetters(ctx)).NoError(t)).Length(0)
+
+	runDelivery(t, uc, func() bool {
+		return q.Len() == 0
+	})
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(4)
+	gt.A(t, teamsMock.PostCalls()).Length(2)
+	gt.A(t, gt.R1(uc.ListDeadLetters(ctx)).NoError(t)).Length(0)
+}
+
+func TestRerouteDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			if strings.Contains(applySlackOptions(t, options).Encode(), "broken") {
+				return "", "", slack.SlackErrorResponse{Err: "invalid_blocks"}
+			}
+			return "", "", nil
+		},
+	}
+	teamsMock := mock.TeamsMock{
+		PostFunc: func(ctx context.Context, channel string, payload any) error {
+			return nil
+		},
+	}
+	newPolicy := func(title string) *opac.Client {
+		return gt.R1(opac.New(opac.Data(map[string]string{
+			"transmit.rego": `package route
+
+import rego.v1
+
+slack contains {"channel": "#alerts", "title": "` + title + `"}
+
+slack contains {"channel": "#ops", "title": "ops"}
+
+teams contains {"channel": "alerts", "title": "hello"}
+`,
+		}))).NoError(t)
+	}
+
+	q := queue.NewMemory()
+	store := deadletter.NewMemory()
+	uc := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithTeams(&teamsMock),
+		adapter.WithPolicy(newPolicy("broken")),
+		adapter.WithQueue(q),
+		adapter.WithDeadLetter(store),
+	))
+
+	gt.NoError(t, uc.Route(ctx, model.Message{Schema: "alert"}))
+	runDelivery(t, uc, func() bool {
+		return q.Len() == 0
+	})
+	entries := gt.R1(uc.ListDeadLetters(ctx)).NoError(t)
+	gt.A(t, entries).Length(1)
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(2)
+
+	// Policy is fixed, and only the failed channel receives the new output
+	rerouteUC := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithTeams(&teamsMock),
+		adapter.WithPolicy(newPolicy("fixed")),
+		adapter.WithDeadLetter(store),
+	))
+	gt.NoError(t, rerouteUC.RerouteDeadLetter(ctx, entries[0].ID))
+	calls := slackMock.PostMessageContextCalls()
+	gt.A(t, calls).Length(3)
+	gt.Equal(t, calls[2].ChannelID, "#alerts")
+	gt.S(t, applySlackOptions(t, calls[2].Options).Encode()).Contains("fixed")
+	gt.A(t, teamsMock.PostCalls()).Length(1)
+	gt.A(t, gt.R1(uc.ListDeadLetters(ctx)).NoError(t)).Length(0)
+}
+
+func TestRerouteDeadLetterWithoutOutput(t *testing.T) {
+	ctx := context.Background()
+	store := deadletter.NewMemory()
+	gt.NoError(t, store.Put(ctx, &model.DeadLetter{
+		ID: "entry-1",
+		Delivery: model.Delivery{
+			ID:      "entry-1",
+			Message: model.Message{Schema: "alert"},
+			Slack:   &model.SlackMessage{Channel: "#alerts"},
+		},
+	}))
+
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": `package route
+
+import rego.v1
+
+teams contains {"channel": "alerts"}
+`,
+	}))).NoError(t)
+	slackMock := mock.SlackMock{}
+	uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy), adapter.WithDeadLetter(store)))
+
+	// Entry is kept because the policy does not output to Slack anymore
+	gt.Error(t, uc.RerouteDeadLetter(ctx, "entry-1"))
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+	gt.A(t, gt.R1(uc.ListDeadLetters(ctx)).NoError(t)).Length(1)
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/slack-go/slack"
+)
+
+func newDeliveries(msg model.Message, output model.PolicyTransmitOutput) []*model.Delivery {
+	now := time.Now()
+	var deliveries []*model.Delivery
+	for _, dest := range destinations {
+		deliveries = append(deliveries, dest.deliveries(msg, output, now)...)
+	}
+	return deliveries
+}
+
+func (x *UseCases) deliver(ctx context.Context, d *model.Delivery) error {
+	dest := destinationOfDelivery(d)
+	if dest == nil {
+		return goerr.New("No destination in delivery", goerr.V("delivery_id", d.ID))
+	}
+	return dest.transmit(ctx, x, d)
+}
+
+// renderDelivery returns payload that would be transmitted for the delivery.
+func renderDelivery(d *model.Delivery) model.DryRunDelivery {
+	rendered := model.DryRunDelivery{
+		Destination: destinationName(d),
+	}
+	if dest := destinationOfDelivery(d); dest != nil {
+		rendered.Payload = dest.render(d)
+	}
+	return rendered
+}
+
+// RunDelivery transmits deliveries in the queue until ctx is canceled. Failed deliveries are retried with exponential backoff. If the destination requests to wait, e.g. Retry-After of rate limit, the delivery is retried after the duration.
+func (x *UseCases) RunDelivery(ctx context.Context) error {
+	queue := x.adaptors.Queue()
+	if queue == nil {
+		return goerr.New("Delivery queue is not configured")
+	}
+
+	logger := logging.Extract(ctx)
+	logger.Info("Start delivery worker")
+
+	for {
+		d, err := queue.Pop(ctx, time.Now())
+		if err != nil {
+			logger.Error("Failed to pop delivery from queue", "error", err)
+		}
+
+		if d == nil {
+			select {
+			case <-ctx.Done():
+				logger.Info("Stop delivery worker")
+				return nil
+			case <-x.wakeup:
+			case <-time.After(x.pollInterval):
+			}
+			continue
+		}
+
+		x.processDelivery(ctx, queue, d)
+	}
+}
+
+// notifyDelivery wakes up the delivery worker without waiting for the next poll.
+func (x *UseCases) notifyDelivery() {
+	select {
+	case x.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (x *UseCases) processDelivery(ctx context.Context, queue interfaces.Queue, d *model.Delivery) {
+	logger := logging.Extract(ctx).With("delivery_id", d.ID, "destination", destinationName(d))
+
+	err := x.deliver(ctx, d)
+	if err == nil {
+		logger.Info("Delivered message", "attempts", d.Attempts+1)
+		if err := queue.Done(ctx, d.ID); err != nil {
+			logger.Error("Failed to complete delivery", "error", err)
+		}
+		return
+	}
+
+	d.Attempts++
+	d.LastError = err.Error()
+
+	if !isRetryable(err) || d.Attempts >= x.maxAttempts {
+		logger.Error("Gave up delivery", "attempts", d.Attempts, "error", err)
+		x.putDeadLetter(ctx, d)
+	} else {
+		delay := x.backoff(d.Attempts, err)
+		d.NextAttemptAt = time.Now().Add(delay)
+		logger.Warn("Failed to deliver message, will retry", "attempts", d.Attempts, "delay", delay, "error", err)
+
+		if err := queue.Push(ctx, d); err != nil {
+			logger.Error("Failed to requeue delivery", "error", err)
+		}
+	}
+
+	if err := queue.Done(ctx, d.ID); err != nil {
+		logger.Error("Failed to complete delivery", "error", err)
+	}
+}
+
+// backoff returns delay before the next attempt. If the destination requests a specific delay, it's used as is.
+func (x *UseCases) backoff(attempts int, err error) time.Duration {
+	var rateLimited *slack.RateLimitedError
+	if errors.As(err, &rateLimited) && rateLimited.RetryAfter > 0 {
+		return rateLimited.RetryAfter
+	}
+
+	var retryAfter interface{ RetryAfter() time.Duration }
+	if errors.As(err, &retryAfter) && retryAfter.RetryAfter() > 0 {
+		return retryAfter.RetryAfter()
+	}
+
+	delay := x.backoffBase
+	for i := 1; i < attempts && delay < x.backoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, x.backoffMax)
+}
+
+// isRetryable returns true if the error is temporary, e.g. rate limit, server error or network error.
+func isRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/slack-go/slack"
+)
+
+func init() {
+	if _, ok := os.LookupEnv("TEST_ENABLE_LOGGER"); !ok {
+		logging.Disable()
+	}
+}
+
+func runDelivery(t *testing.T, uc *usecase.UseCases, done func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		gt.NoError(t, uc.RunDelivery(ctx))
+	}()
+
+	timeout := time.After(5 * time.Second)
+	for !done() {
+		select {
+		case <-timeout:
+			t.Error("timeout")
+			cancel()
+			wg.Wait()
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	wg.Wait()
+}
+
+func TestDeliveryRetry(t *testing.T) {
+	var called int
+	var mutex sync.Mutex
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			called++
+			if called == 1 {
+				return "", "", &slack.RateLimitedError{RetryAfter: 50 * time.Millisecond}
+			}
+			return "", "", nil
+		},
+	}
+
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyTransmitRego,
+	}))).NoError(t)
+
+	q := queue.NewMemory()
+	uc := usecase.New(
+		adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy), adapter.WithQueue(q)),
+		usecase.WithPollInterval(10*time.Millisecond),
+	)
+
+	gt.NoError(t, uc.Route(context.Background(), model.Message{Schema: "for_slack"}))
+	gt.Equal(t, q.Len(), 1)
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+
+	ts := time.Now()
+	runDelivery(t, uc, func() bool {
+		return len(slackMock.PostMessageContextCalls()) == 2 && q.Len() == 0
+	})
+	gt.True(t, time.Since(ts) >= 50*time.Millisecond)
+}
+
+func TestDeliveryGiveUp(t *testing.T) {
+	testCases := map[string]struct {
+		err   error
+		calls int
+	}{
+		"not retryable": {
+			err:   slack.SlackErrorResponse{Err: "channel_not_found"},
+			calls: 1,
+		},
+		"retryable until max attempts": {
+			err:   slack.StatusCodeError{Code: 503, Status: "Service Unavailable"},
+			calls: 3,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			slackMock := mock.SlackMock{
+				PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+					return "", "", tc.err
+				},
+			}
+
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": policyTransmitRego,
+			}))).NoError(t)
+
+			q := queue.NewMemory()
+			uc := usecase.New(
+				adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy), adapter.WithQueue(q)),
+				usecase.WithMaxAttempts(3),
+				usecase.WithBackoff(time.Millisecond, 5*time.Millisecond),
+				usecase.WithPollInterval(time.Millisecond),
+			)
+
+			gt.NoError(t, uc.Route(context.Background(), model.Message{Schema: "for_slack"}))
+			runDelivery(t, uc, func() bool {
+				return q.Len() == 0
+			})
+			gt.A(t, slackMock.PostMessageContextCalls()).Length(tc.calls)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// destination is a kind of transmission target. A new destination needs a field of model.PolicyTransmitOutput, a field of model.Delivery and an entry of destinations.
+type destination interface {
+	name() string
+
+	// deliveries creates a delivery for each message of the destination in the output.
+	deliveries(msg model.Message, output model.PolicyTransmitOutput, now time.Time) []*model.Delivery
+
+	// has returns true if the delivery is for the destination.
+	has(d *model.Delivery) bool
+
+	// redeliveries returns deliveries of the output to the same destination as d. If the output has multiple messages of the destination, only ones with the same target as d are returned, so that messages transmitted successfully are not sent again.
+	redeliveries(d *model.Delivery, output model.PolicyTransmitOutput, now time.Time) []*model.Delivery
+
+	transmit(ctx context.Context, x *UseCases, d *model.Delivery) error
+	render(d *model.Delivery) any
+}
+
+// destinationOf binds the field of model.Delivery for message type T to how the message is transmitted and rendered.
+type destinationOf[T any] struct {
+	key     string
+	outputs func(output model.PolicyTransmitOutput) []T
+	field   func(d *model.Delivery) **T
+	// target identifies where the message is transmitted in the destination, e.g. channel.
+	target  func(msg T) string
+	send    func(ctx context.Context, x *UseCases, d *model.Delivery, msg T) error
+	preview func(msg T) any
+}
+
+func (x *destinationOf[T]) name() string { return x.key }
+
+func (x *destinationOf[T]) deliveries(msg model.Message, output model.PolicyTransmitOutput, now time.Time) []*model.Delivery {
+	var deliveries []*model.Delivery
+	for _, m := range x.outputs(output) {
+		d := &model.Delivery{
+			ID:            uuid.NewString(),
+			Message:       msg,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+		*x.field(d) = &m
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+func (x *destinationOf[T]) redeliveries(d *model.Delivery, output model.PolicyTransmitOutput, now time.Time) []*model.Delivery {
+	deliveries := x.deliveries(d.Message, output, now)
+	if len(deliveries) <= 1 {
+		return deliveries
+	}
+
+	target := x.target(**x.field(d))
+	var matched []*model.Delivery
+	for _, redelivery := range deliveries {
+		if x.target(**x.field(redelivery)) == target {
+			matched = append(matched, redelivery)
+		}
+	}
+	return matched
+}
+
+func (x *destinationOf[T]) has(d *model.Delivery) bool {
+	return *x.field(d) != nil
+}
+
+func (x *destinationOf[T]) transmit(ctx context.Context, uc *UseCases, d *model.Delivery) error {
+	return x.send(ctx, uc, d, **x.field(d))
+}
+
+func (x *destinationOf[T]) render(d *model.Delivery) any {
+	return x.preview(**x.field(d))
+}
+
+var destinations = []destination{
+	&destinationOf[model.SlackMessage]{
+		key:     "slack",
+		outputs: func(output model.PolicyTransmitOutput) []model.SlackMessage { return output.Slack },
+		field:   func(d *model.Delivery) **model.SlackMessage { return &d.Slack },
+		target:  func(msg model.SlackMessage) string { return msg.Channel },
+		send: func(ctx context.Context, x *UseCases, d *model.Delivery, msg model.SlackMessage) error {
+			client := x.adaptors.Slack()
+			if client == nil {
+				return goerr.New("Slack is not configured")
+			}
+			return x.transmitSlack(ctx, d, msg, client)
+		},
+		preview: renderSlackMessage,
+	},
+	// Files of Slack message are not output of policy. They are delivered after the message is posted.
+	&destinationOf[model.SlackFileUpload]{
+		key:     "slack_file",
+		outputs: func(output model.PolicyTransmitOutput) []model.SlackFileUpload { return nil },
+		field:   func(d *model.Delivery) **model.SlackFileUpload { return &d.SlackFile },
+		target:  func(upload model.SlackFileUpload) string { return upload.Channel },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, upload model.SlackFileUpload) error {
+			client := x.adaptors.Slack()
+			if client == nil {
+				return goerr.New("Slack is not configured")
+			}
+			return transmitSlackFile(ctx, upload, client)
+		},
+		preview: renderSlackFileUpload,
+	},
+	&destinationOf[model.TeamsMessage]{
+		key:     "teams",
+		outputs: func(output model.PolicyTransmitOutput) []model.TeamsMessage { return output.Teams },
+		field:   func(d *model.Delivery) **model.TeamsMessage { return &d.Teams },
+		target:  func(msg model.TeamsMessage) string { return msg.Channel },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, msg model.TeamsMessage) error {
+			client := x.adaptors.Teams()
+			if client == nil {
+				return goerr.New("Teams is not configured")
+			}
+			return transmitTeams(ctx, msg, client)
+		},
+		preview: renderTeamsMessage,
+	},
+	&destinationOf[model.WebhookMessage]{
+		key:     "webhook",
+		outputs: func(output model.PolicyTransmitOutput) []model.WebhookMessage { return output.Webhook },
+		field:   func(d *model.Delivery) **model.WebhookMessage { return &d.Webhook },
+		target:  func(msg model.WebhookMessage) string { return msg.URL },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, msg model.WebhookMessage) error {
+			return x.transmitWebhook(ctx, msg, x.adaptors.HTTPClient())
+		},
+		preview: renderWebhookMessage,
+	},
+	&destinationOf[model.DiscordMessage]{
+		key:     "discord",
+		outputs: func(output model.PolicyTransmitOutput) []model.DiscordMessage { return output.Discord },
+		field:   func(d *model.Delivery) **model.DiscordMessage { return &d.Discord },
+		target:  func(msg model.DiscordMessage) string { return msg.Channel },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, msg model.DiscordMessage) error {
+			client := x.adaptors.Discord()
+			if client == nil {
+				return goerr.New("Discord is not configured")
+			}
+			return transmitDiscord(ctx, msg, client)
+		},
+		preview: renderDiscordMessage,
+	},
+	&destinationOf[model.EmailMessage]{
+		key:     "email",
+		outputs: func(output model.PolicyTransmitOutput) []model.EmailMessage { return output.Email },
+		field:   func(d *model.Delivery) **model.EmailMessage { return &d.Email },
+		target:  func(msg model.EmailMessage) string { return strings.Join(msg.To, ",") },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, msg model.EmailMessage) error {
+			client := x.adaptors.Email()
+			if client == nil {
+				return goerr.New("Email is not configured")
+			}
+			return transmitEmail(ctx, msg, client)
+		},
+		preview: renderEmail,
+	},
+	&destinationOf[model.PagerDutyMessage]{
+		key:     "pagerduty",
+		outputs: func(output model.PolicyTransmitOutput) []model.PagerDutyMessage { return output.PagerDuty },
+		field:   func(d *model.Delivery) **model.PagerDutyMessage { return &d.PagerDuty },
+		target:  func(msg model.PagerDutyMessage) string { return msg.RoutingKey },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, msg model.PagerDutyMessage) error {
+			client := x.adaptors.PagerDuty()
+			if client == nil {
+				return goerr.New("PagerDuty is not configured")
+			}
+			return transmitPagerDuty(ctx, msg, client)
+		},
+		preview: renderPagerDutyEvent,
+	},
+	&destinationOf[model.GitHubMessage]{
+		key:     "github",
+		outputs: func(output model.PolicyTransmitOutput) []model.GitHubMessage { return output.GitHub },
+		field:   func(d *model.Delivery) **model.GitHubMessage { return &d.GitHub },
+		target:  func(msg model.GitHubMessage) string { return msg.Repo },
+		send: func(ctx context.Context, x *UseCases, _ *model.Delivery, msg model.GitHubMessage) error {
+			client := x.adaptors.GitHub()
+			if client == nil {
+				return goerr.New("GitHub is not configured")
+			}
+			return transmitGitHub(ctx, msg, client)
+		},
+		preview: renderGitHubMessage,
+	},
+}
+
+// destinationOfDelivery returns the destination of the delivery, or nil if no destination is set.
+func destinationOfDelivery(d *model.Delivery) destination {
+	for _, dest := range destinations {
+		if dest.has(d) {
+			return dest
+		}
+	}
+	return nil
+}
+
+// destinationName returns name of the destination of the delivery for logging.
+func destinationName(d *model.Delivery) string {
+	if dest := destinationOfDelivery(d); dest != nil {
+		return dest.name()
+	}
+	return "unknown"
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+func transmitDiscord(ctx context.Context, msg model.DiscordMessage, client interfaces.Discord) error {
+	logger := logging.Extract(ctx)
+	logger.Debug("Transmit discord message", "message", msg)
+
+	if err := client.Post(ctx, msg.Channel, buildDiscordMessage(msg)); err != nil {
+		return goerr.Wrap(err, "failed to post discord message", goerr.V("message", msg))
+	}
+
+	return nil
+}
+
+// renderDiscordMessage returns channel name and request body that transmitDiscord sends.
+func renderDiscordMessage(msg model.DiscordMessage) any {
+	return map[string]any{
+		"channel": msg.Channel,
+		"body":    buildDiscordMessage(msg),
+	}
+}
+
+// buildDiscordMessage builds webhook payload with an embed. Color of embed is integer, so color code is converted. Invalid color code is ignored.
+func buildDiscordMessage(msg model.DiscordMessage) map[string]any {
+	embed := map[string]any{}
+
+	if color, err := strconv.ParseInt(strings.TrimPrefix(resolveColor(msg.Color), "#"), 16, 32); err == nil {
+		embed["color"] = color
+	}
+	if msg.Title != "" {
+		embed["title"] = msg.Title
+	}
+	if msg.Body != "" {
+		embed["description"] = msg.Body
+	}
+
+	if len(msg.Fields) > 0 {
+		fields := make([]map[string]any, len(msg.Fields))
+		for i, field := range msg.Fields {
+			value := field.Value
+			if field.Link != "" {
+				value = "[" + field.Value + "](" + field.Link + ")"
+			}
+			fields[i] = map[string]any{
+				"name":   field.Name,
+				"value":  value,
+				"inline": true,
+			}
+		}
+		embed["fields"] = fields
+	}
+
+	return map[string]any{
+		"embeds": []map[string]any{embed},
+	}
+}
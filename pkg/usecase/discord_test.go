@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+const policyDiscordRego = `package route
+
+import rego.v1
+
+discord contains {
+	"channel": "community",
+	"title": "New release",
+	"body": input.data.message,
+	"color": input.data.color,
+	"fields": [
+		{"name": "Version", "value": "v1.0.0", "link": "https://example.com/releases/v1.0.0"},
+	],
+} if {
+	input.schema == "for_discord"
+}
+`
+
+func TestTransmitDiscord(t *testing.T) {
+	testCases := map[string]struct {
+		color    string
+		expected int64
+	}{
+		"default":   {color: "", expected: 0x2EB67D},
+		"preserved": {color: "error", expected: 0xFF0000},
+		"code":      {color: "#123456", expected: 0x123456},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			discordMock := mock.DiscordMock{
+				PostFunc: func(ctx context.Context, channel string, payload any) error {
+					return nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": policyDiscordRego,
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithDiscord(&discordMock), adapter.WithPolicy(policy)))
+			gt.NoError(t, uc.Route(context.Background(), model.Message{
+				Schema: "for_discord",
+				Data:   map[string]any{"message": "Released", "color": tc.color},
+			}))
+
+			gt.A(t, discordMock.PostCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx     context.Context
+				Channel string
+				Payload any
+			}) {
+				gt.Equal(t, v.Channel, "community")
+
+				msg := gt.Cast[map[string]any](t, v.Payload)
+				embeds := gt.Cast[[]map[string]any](t, msg["embeds"])
+				gt.A(t, embeds).Length(1)
+				gt.Equal(t, embeds[0]["title"], "New release")
+				gt.Equal(t, embeds[0]["description"], "Released")
+				gt.Equal(t, embeds[0]["color"], any(tc.expected))
+
+				fields := gt.Cast[[]map[string]any](t, embeds[0]["fields"])
+				gt.Equal(t, fields[0]["value"], "[v1.0.0](https://example.com/releases/v1.0.0)")
+			})
+		})
+	}
+}
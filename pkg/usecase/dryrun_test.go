@@ -0,0 +1,40 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/m-mizutani/xroute/pkg/utils/dryrun"
+)
+
+func TestDryRun(t *testing.T) {
+	slackMock := mock.SlackMock{}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyTransmitRego,
+	}))).NoError(t)
+
+	q := queue.NewMemory()
+	uc := usecase.New(
+		adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy), adapter.WithQueue(q)),
+		usecase.WithDryRun(),
+	)
+
+	recorder := dryrun.New()
+	ctx := dryrun.Inject(context.Background(), recorder)
+	gt.NoError(t, uc.Route(ctx, model.Message{Schema: "for_slack"}))
+
+	gt.Equal(t, q.Len(), 0)
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+	gt.A(t, recorder.Deliveries()).Length(1).At(0, func(t testing.TB, v model.DryRunDelivery) {
+		gt.Equal(t, v.Destination, "slack")
+		payload := gt.Cast[map[string]any](t, v.Payload)
+		gt.Equal(t, payload["channel"], "#general")
+	})
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+func transmitEmail(ctx context.Context, msg model.EmailMessage, client interfaces.Email) error {
+	logger := logging.Extract(ctx)
+	logger.Debug("Transmit email message", "message", msg)
+
+	mail, err := buildEmail(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Send(ctx, mail); err != nil {
+		return goerr.Wrap(err, "failed to send email", goerr.V("message", msg))
+	}
+
+	return nil
+}
+
+// renderEmail returns the mail that transmitEmail sends.
+func renderEmail(msg model.EmailMessage) any {
+	mail, err := buildEmail(msg)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	return mail
+}
+
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<div style="border-left: 4px solid {{ .Color }}; padding: 4px 12px;">
+{{- if .Title }}
+<h2>{{ .Title }}</h2>
+{{- end }}
+{{- if .Body }}
+<p style="white-space: pre-wrap;">{{ .Body }}</p>
+{{- end }}
+{{- if .Fields }}
+<table>
+{{- range .Fields }}
+<tr><th style="text-align: left; padding-right: 12px;">{{ .Name }}</th><td>{{ if .Link }}<a href="{{ .Link }}">{{ .Value }}</a>{{ else }}{{ .Value }}{{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</div>
+</body>
+</html>
+`))
+
+// buildEmail renders plain text and HTML bodies from title, body and fields of the message. Subject falls back to title.
+func buildEmail(msg model.EmailMessage) (*model.Email, error) {
+	if len(msg.To) == 0 {
+		return nil, goerr.New("no recipient of email", goerr.V("message", msg))
+	}
+
+	subject := msg.Subject
+	if subject == "" {
+		subject = msg.Title
+	}
+
+	var text []string
+	if msg.Title != "" {
+		text = append(text, msg.Title)
+	}
+	if msg.Body != "" {
+		text = append(text, msg.Body)
+	}
+	if len(msg.Fields) > 0 {
+		var fields []string
+		for _, field := range msg.Fields {
+			line := field.Name + ": " + field.Value
+			if field.Link != "" {
+				line += " <" + field.Link + ">"
+			}
+			fields = append(fields, line)
+		}
+		text = append(text, strings.Join(fields, "\n"))
+	}
+
+	var html bytes.Buffer
+	if err := emailHTMLTemplate.Execute(&html, map[string]any{
+		"Color":  resolveColor(msg.Color),
+		"Title":  msg.Title,
+		"Body":   msg.Body,
+		"Fields": msg.Fields,
+	}); err != nil {
+		return nil, goerr.Wrap(err, "failed to render email HTML", goerr.V("message", msg))
+	}
+
+	return &model.Email{
+		To:      msg.To,
+		Cc:      msg.Cc,
+		Subject: subject,
+		Text:    strings.Join(text, "\n\n") + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+const policyEmailRego = `package route
+
+import rego.v1
+
+email contains {
+	"to": ["security@example.com"],
+	"cc": ["oncall@example.com"],
+	"subject": input.data.subject,
+	"title": "Suspicious login",
+	"body": input.data.message,
+	"color": "error",
+	"fields": [
+		{"name": "User", "value": "<alice>"},
+		{"name": "Detail", "value": "console", "link": "https://example.com/alerts/1"},
+	],
+} if {
+	input.schema == "for_email"
+}
+`
+
+func TestTransmitEmail(t *testing.T) {
+	testCases := map[string]struct {
+		subject  string
+		expected string
+	}{
+		"subject":          {subject: "[alert] login", expected: "[alert] login"},
+		"title as subject": {subject: "", expected: "Suspicious login"},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			emailMock := mock.EmailMock{
+				SendFunc: func(ctx context.Context, mail *model.Email) error {
+					return nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": policyEmailRego,
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithEmail(&emailMock), adapter.WithPolicy(policy)))
+			gt.NoError(t, uc.Route(context.Background(), model.Message{
+				Schema: "for_email",
+				Data:   map[string]any{"message": "Login from <script>", "subject": tc.subject},
+			}))
+
+			gt.A(t, emailMock.SendCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx  context.Context
+				Mail *model.Email
+			}) {
+				gt.Equal(t, v.Mail.To, []string{"security@example.com"})
+				gt.Equal(t, v.Mail.Cc, []string{"oncall@example.com"})
+				gt.Equal(t, v.Mail.Subject, tc.expected)
+				gt.Equal(t, v.Mail.Text, "Suspicious login\n\nLogin from <script>\n\nUser: <alice>\nDetail: console <https://example.com/alerts/1>\n")
+
+				gt.Equal(t, strings.Contains(v.Mail.HTML, "<h2>Suspicious login</h2>"), true)
+				gt.Equal(t, strings.Contains(v.Mail.HTML, "Login from &lt;script&gt;"), true)
+				gt.Equal(t, strings.Contains(v.Mail.HTML, "&lt;alice&gt;"), true)
+				gt.Equal(t, strings.Contains(v.Mail.HTML, `<a href="https://example.com/alerts/1">console</a>`), true)
+				gt.Equal(t, strings.Contains(v.Mail.HTML, "border-left: 4px solid #FF0000"), true)
+			})
+		})
+	}
+}
+
+func TestTransmitEmailNotConfigured(t *testing.T) {
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyEmailRego,
+	}))).NoError(t)
+
+	uc := usecase.New(adapter.New(adapter.WithPolicy(policy)))
+	gt.Error(t, uc.Route(context.Background(), model.Message{
+		Schema: "for_email",
+		Data:   map[string]any{"message": "test", "subject": "test"},
+	}))
+}
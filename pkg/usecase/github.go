@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+// transmitGitHub comments on the issue if number is given or an open issue matches dedup condition. Otherwise, it creates new issue.
+func transmitGitHub(ctx context.Context, msg model.GitHubMessage, client interfaces.GitHub) error {
+	logger := logging.Extract(ctx)
+	logger.Debug("Transmit github message", "message", msg)
+
+	owner, repo, err := splitGitHubRepo(msg.Repo)
+	if err != nil {
+		return err
+	}
+
+	number := msg.Number
+	if number == 0 && msg.Dedup != nil {
+		issue, err := findGitHubIssue(ctx, client, owner, repo, msg)
+		if err != nil {
+			return err
+		}
+		if issue != nil {
+			logger.Debug("Found existing github issue", "repo", msg.Repo, "number", issue.GetNumber())
+			number = issue.GetNumber()
+		}
+	}
+
+	if number > 0 {
+		if msg.Body == "" {
+			return goerr.New("body is required to comment on github issue", goerr.V("message", msg))
+		}
+		if _, err := client.CreateComment(ctx, owner, repo, number, msg.Body); err != nil {
+			return goerr.Wrap(err, "failed to comment on github issue", goerr.V("message", msg), goerr.V("number", number))
+		}
+		return nil
+	}
+
+	req, err := buildGitHubIssue(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := client.CreateIssue(ctx, owner, repo, req); err != nil {
+		return goerr.Wrap(err, "failed to create github issue", goerr.V("message", msg))
+	}
+
+	return nil
+}
+
+// renderGitHubMessage returns request that transmitGitHub sends. If dedup is set, whether the issue is created or commented depends on existing issues, so both are rendered.
+func renderGitHubMessage(msg model.GitHubMessage) any {
+	payload := map[string]any{
+		"repo": msg.Repo,
+	}
+
+	if msg.Number > 0 {
+		payload["comment"] = map[string]any{
+			"number": msg.Number,
+			"body":   msg.Body,
+		}
+		return payload
+	}
+
+	req, err := buildGitHubIssue(msg)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	payload["issue"] = req
+	if msg.Dedup != nil {
+		payload["dedup"] = msg.Dedup
+	}
+
+	return payload
+}
+
+func splitGitHubRepo(fullName string) (string, string, error) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", goerr.New("repo must be owner/name", goerr.V("repo", fullName))
+	}
+	return owner, repo, nil
+}
+
+// findGitHubIssue returns open issue that has the dedup label and the same title if required. It returns nil if not found.
+func findGitHubIssue(ctx context.Context, client interfaces.GitHub, owner, repo string, msg model.GitHubMessage) (*github.Issue, error) {
+	if msg.Dedup.Label == "" && !msg.Dedup.Title {
+		return nil, goerr.New("dedup requires label or title", goerr.V("message", msg))
+	}
+
+	var labels []string
+	if msg.Dedup.Label != "" {
+		labels = []string{msg.Dedup.Label}
+	}
+
+	issues, err := client.ListOpenIssues(ctx, owner, repo, labels)
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to list github issues", goerr.V("message", msg))
+	}
+
+	for _, issue := range issues {
+		if !msg.Dedup.Title || issue.GetTitle() == msg.Title {
+			return issue, nil
+		}
+	}
+	return nil, nil
+}
+
+// buildGitHubIssue builds request to create issue. The dedup label is attached so that the issue is found next time.
+func buildGitHubIssue(msg model.GitHubMessage) (*github.IssueRequest, error) {
+	if msg.Title == "" {
+		return nil, goerr.New("title is required to create github issue", goerr.V("message", msg))
+	}
+
+	req := &github.IssueRequest{
+		Title: &msg.Title,
+	}
+	if msg.Body != "" {
+		req.Body = &msg.Body
+	}
+
+	labels := slices.Clone(msg.Labels)
+	if msg.Dedup != nil && msg.Dedup.Label != "" && !slices.Contains(labels, msg.Dedup.Label) {
+		labels = append(labels, msg.Dedup.Label)
+	}
+	if len(labels) > 0 {
+		req.Labels = &labels
+	}
+	if len(msg.Assignees) > 0 {
+		assignees := slices.Clone(msg.Assignees)
+		req.Assignees = &assignees
+	}
+
+	return req, nil
+}
@@ -0,0 +1,162 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+const policyGitHubRego = `package route
+
+import rego.v1
+
+github contains {
+	"repo": "org/security",
+	"title": sprintf("Finding: %s", [input.data.finding]),
+	"body": input.data.detail,
+	"labels": ["security"],
+	"assignees": ["alice"],
+	"dedup": {"label": "xroute", "title": true},
+} if {
+	input.schema == "for_github"
+	not input.data.pr
+}
+
+github contains {
+	"repo": "org/app",
+	"number": input.data.pr,
+	"body": input.data.detail,
+} if {
+	input.schema == "for_github"
+	input.data.pr
+}
+`
+
+func newGitHubMock(existing []*github.Issue) *mock.GitHubMock {
+	return &mock.GitHubMock{
+		CreateIssueFunc: func(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, error) {
+			return &github.Issue{Number: github.Ptr(100)}, nil
+		},
+		CreateCommentFunc: func(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error) {
+			return &github.IssueComment{}, nil
+		},
+		ListOpenIssuesFunc: func(ctx context.Context, owner, repo string, labels []string) ([]*github.Issue, error) {
+			return existing, nil
+		},
+	}
+}
+
+func TestTransmitGitHub(t *testing.T) {
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyGitHubRego,
+	}))).NoError(t)
+	ctx := context.Background()
+	finding := model.Message{
+		Schema: "for_github",
+		Data:   map[string]any{"finding": "exposed key", "detail": "found in main.go"},
+	}
+
+	t.Run("create issue", func(t *testing.T) {
+		ghMock := newGitHubMock([]*github.Issue{
+			{Number: github.Ptr(1), Title: github.Ptr("Finding: other")},
+		})
+		uc := usecase.New(adapter.New(adapter.WithGitHub(ghMock), adapter.WithPolicy(policy)))
+		gt.NoError(t, uc.Route(ctx, finding))
+
+		gt.A(t, ghMock.ListOpenIssuesCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx    context.Context
+			Owner  string
+			Repo   string
+			Labels []string
+		}) {
+			gt.Equal(t, v.Owner, "org")
+			gt.Equal(t, v.Repo, "security")
+			gt.Equal(t, v.Labels, []string{"xroute"})
+		})
+		gt.A(t, ghMock.CreateCommentCalls()).Length(0)
+		gt.A(t, ghMock.CreateIssueCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx   context.Context
+			Owner string
+			Repo  string
+			Req   *github.IssueRequest
+		}) {
+			gt.Equal(t, v.Req.GetTitle(), "Finding: exposed key")
+			gt.Equal(t, v.Req.GetBody(), "found in main.go")
+			gt.Equal(t, *v.Req.Labels, []string{"security", "xroute"})
+			gt.Equal(t, *v.Req.Assignees, []string{"alice"})
+		})
+	})
+
+	t.Run("comment on existing issue", func(t *testing.T) {
+		ghMock := newGitHubMock([]*github.Issue{
+			{Number: github.Ptr(1), Title: github.Ptr("Finding: other")},
+			{Number: github.Ptr(7), Title: github.Ptr("Finding: exposed key")},
+		})
+		uc := usecase.New(adapter.New(adapter.WithGitHub(ghMock), adapter.WithPolicy(policy)))
+		gt.NoError(t, uc.Route(ctx, finding))
+
+		gt.A(t, ghMock.CreateIssueCalls()).Length(0)
+		gt.A(t, ghMock.CreateCommentCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx    context.Context
+			Owner  string
+			Repo   string
+			Number int
+			Body   string
+		}) {
+			gt.Equal(t, v.Number, 7)
+			gt.Equal(t, v.Body, "found in main.go")
+		})
+	})
+
+	t.Run("comment on pull request", func(t *testing.T) {
+		ghMock := newGitHubMock(nil)
+		uc := usecase.New(adapter.New(adapter.WithGitHub(ghMock), adapter.WithPolicy(policy)))
+		gt.NoError(t, uc.Route(ctx, model.Message{
+			Schema: "for_github",
+			Data:   map[string]any{"pr": 42, "detail": "vulnerable dependency"},
+		}))
+
+		gt.A(t, ghMock.ListOpenIssuesCalls()).Length(0)
+		gt.A(t, ghMock.CreateCommentCalls()).Length(1).At(0, func(t testing.TB, v struct {
+			Ctx    context.Context
+			Owner  string
+			Repo   string
+			Number int
+			Body   string
+		}) {
+			gt.Equal(t, v.Owner, "org")
+			gt.Equal(t, v.Repo, "app")
+			gt.Equal(t, v.Number, 42)
+		})
+	})
+}
+
+func TestTransmitGitHubInvalidMessage(t *testing.T) {
+	testCases := map[string]string{
+		"invalid repo":    `github contains {"repo": "org", "title": "x"}`,
+		"no title":        `github contains {"repo": "org/repo", "body": "x"}`,
+		"empty comment":   `github contains {"repo": "org/repo", "number": 1}`,
+		"empty condition": `github contains {"repo": "org/repo", "title": "x", "dedup": {}}`,
+	}
+
+	for title, rule := range testCases {
+		t.Run(title, func(t *testing.T) {
+			ghMock := newGitHubMock(nil)
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": "package route\nimport rego.v1\n" + rule + "\n",
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithGitHub(ghMock), adapter.WithPolicy(policy)))
+			gt.Error(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+			gt.A(t, ghMock.CreateIssueCalls()).Length(0)
+			gt.A(t, ghMock.CreateCommentCalls()).Length(0)
+		})
+	}
+}
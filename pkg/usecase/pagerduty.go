@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+const (
+	pagerDutyDefaultSource = "xroute"
+
+	// pagerDutyMaxSummary is maximum length of summary accepted by Events API v2.
+	pagerDutyMaxSummary = 1024
+)
+
+var (
+	pagerDutyActions    = []string{"trigger", "acknowledge", "resolve"}
+	pagerDutySeverities = []string{"critical", "error", "warning", "info"}
+)
+
+func transmitPagerDuty(ctx context.Context, msg model.PagerDutyMessage, client interfaces.PagerDuty) error {
+	logger := logging.Extract(ctx)
+	logger.Debug("Transmit pagerduty event", "message", msg)
+
+	event, err := buildPagerDutyEvent(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Enqueue(ctx, msg.RoutingKey, event); err != nil {
+		return goerr.Wrap(err, "failed to send pagerduty event", goerr.V("message", msg))
+	}
+
+	return nil
+}
+
+// renderPagerDutyEvent returns routing key name and event that transmitPagerDuty sends. The routing key itself is not rendered.
+func renderPagerDutyEvent(msg model.PagerDutyMessage) any {
+	event, err := buildPagerDutyEvent(msg)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	return map[string]any{
+		"routing_key": msg.RoutingKey,
+		"body":        event,
+	}
+}
+
+// buildPagerDutyEvent builds event of Events API v2 except routing_key. Payload is required only for trigger, and dedup_key is required for acknowledge and resolve.
+func buildPagerDutyEvent(msg model.PagerDutyMessage) (map[string]any, error) {
+	action := msg.Action
+	if action == "" {
+		action = "trigger"
+	}
+	if !slices.Contains(pagerDutyActions, action) {
+		return nil, goerr.New("invalid action of pagerduty event", goerr.V("action", action))
+	}
+
+	event := map[string]any{
+		"event_action": action,
+	}
+	if msg.DedupKey != "" {
+		event["dedup_key"] = msg.DedupKey
+	}
+
+	if action != "trigger" {
+		if msg.DedupKey == "" {
+			return nil, goerr.New("dedup_key is required to acknowledge or resolve pagerduty alert", goerr.V("action", action))
+		}
+		return event, nil
+	}
+
+	if msg.Summary == "" {
+		return nil, goerr.New("summary is required to trigger pagerduty alert")
+	}
+	if !slices.Contains(pagerDutySeverities, msg.Severity) {
+		return nil, goerr.New("invalid severity of pagerduty event", goerr.V("severity", msg.Severity))
+	}
+
+	summary := msg.Summary
+	if runes := []rune(summary); len(runes) > pagerDutyMaxSummary {
+		summary = string(runes[:pagerDutyMaxSummary])
+	}
+	source := msg.Source
+	if source == "" {
+		source = pagerDutyDefaultSource
+	}
+
+	payload := map[string]any{
+		"summary":  summary,
+		"source":   source,
+		"severity": msg.Severity,
+	}
+	if msg.Component != "" {
+		payload["component"] = msg.Component
+	}
+	if msg.Group != "" {
+		payload["group"] = msg.Group
+	}
+	if msg.Class != "" {
+		payload["class"] = msg.Class
+	}
+	if len(msg.CustomDetails) > 0 {
+		payload["custom_details"] = msg.CustomDetails
+	}
+	event["payload"] = payload
+
+	return event, nil
+}
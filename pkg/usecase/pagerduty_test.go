@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+const policyPagerDutyRego = `package route
+
+import rego.v1
+
+pagerduty contains {
+	"routing_key": "ci",
+	"action": "trigger",
+	"dedup_key": sprintf("ci/%s", [input.data.workflow]),
+	"severity": "error",
+	"summary": sprintf("Workflow %s failed", [input.data.workflow]),
+	"component": input.data.workflow,
+	"custom_details": {"run_id": input.data.run_id},
+} if {
+	input.schema == "for_pagerduty"
+	input.data.conclusion == "failure"
+}
+
+pagerduty contains {
+	"routing_key": "ci",
+	"action": "resolve",
+	"dedup_key": sprintf("ci/%s", [input.data.workflow]),
+} if {
+	input.schema == "for_pagerduty"
+	input.data.conclusion == "success"
+}
+`
+
+func TestTransmitPagerDuty(t *testing.T) {
+	testCases := map[string]struct {
+		conclusion string
+		expected   map[string]any
+	}{
+		"trigger": {
+			conclusion: "failure",
+			expected: map[string]any{
+				"event_action": "trigger",
+				"dedup_key":    "ci/build",
+				"payload": map[string]any{
+					"summary":        "Workflow build failed",
+					"source":         "xroute",
+					"severity":       "error",
+					"component":      "build",
+					"custom_details": map[string]any{"run_id": "1234"},
+				},
+			},
+		},
+		"resolve": {
+			conclusion: "success",
+			expected: map[string]any{
+				"event_action": "resolve",
+				"dedup_key":    "ci/build",
+			},
+		},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			pdMock := mock.PagerDutyMock{
+				EnqueueFunc: func(ctx context.Context, routingKey string, event map[string]any) error {
+					return nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": policyPagerDutyRego,
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithPagerDuty(&pdMock), adapter.WithPolicy(policy)))
+			gt.NoError(t, uc.Route(context.Background(), model.Message{
+				Schema: "for_pagerduty",
+				Data:   map[string]any{"workflow": "build", "run_id": "1234", "conclusion": tc.conclusion},
+			}))
+
+			gt.A(t, pdMock.EnqueueCalls()).Length(1).At(0, func(t testing.TB, v struct {
+				Ctx        context.Context
+				RoutingKey string
+				Event      map[string]any
+			}) {
+				gt.Equal(t, v.RoutingKey, "ci")
+				gt.Equal(t, v.Event, tc.expected)
+			})
+		})
+	}
+}
+
+func TestTransmitPagerDutyInvalidEvent(t *testing.T) {
+	testCases := map[string]string{
+		"unknown action": `pagerduty contains {"routing_key": "ci", "action": "escalate", "dedup_key": "x"}`,
+		"no dedup key":   `pagerduty contains {"routing_key": "ci", "action": "resolve"}`,
+		"no summary":     `pagerduty contains {"routing_key": "ci", "severity": "error"}`,
+		"bad severity":   `pagerduty contains {"routing_key": "ci", "summary": "x", "severity": "fatal"}`,
+	}
+
+	for title, rule := range testCases {
+		t.Run(title, func(t *testing.T) {
+			pdMock := mock.PagerDutyMock{
+				EnqueueFunc: func(ctx context.Context, routingKey string, event map[string]any) error {
+					return nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": "package route\nimport rego.v1\n" + rule + "\n",
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithPagerDuty(&pdMock), adapter.WithPolicy(policy)))
+			gt.Error(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+			gt.A(t, pdMock.EnqueueCalls()).Length(0)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+// ReloadPolicy loads policy again with the loader given by WithPolicyLoader and replaces the current policy. If loading fails, the current policy is kept and the error is returned. trigger describes what triggered the reloading, and it's recorded in the status.
+func (x *UseCases) ReloadPolicy(ctx context.Context, trigger string) error {
+	if x.policyLoader == nil {
+		return goerr.New("Policy reloading is not configured", goerr.T(types.ErrTagNotFound))
+	}
+
+	x.policyMutex.Lock()
+	defer x.policyMutex.Unlock()
+
+	logger := logging.Extract(ctx)
+	result := &model.PolicyReloadResult{
+		Trigger: trigger,
+		At:      time.Now(),
+	}
+	x.policyStatus.LastReload = result
+
+	policy, err := x.policyLoader(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		logger.Error("Failed to reload policy, keep the current policy", "trigger", trigger, "error", err)
+		return goerr.Wrap(err, "Failed to reload policy", goerr.V("trigger", trigger))
+	}
+
+	x.adaptors.SetPolicy(policy)
+	result.Success = true
+	x.policyStatus.LoadedAt = result.At
+	logger.Info("Policy is reloaded", "trigger", trigger)
+
+	return nil
+}
+
+// PolicyStatus returns when the current policy was loaded and result of the last reloading.
+func (x *UseCases) PolicyStatus(ctx context.Context) (*model.PolicyStatus, error) {
+	x.policyMutex.Lock()
+	defer x.policyMutex.Unlock()
+
+	status := x.policyStatus
+	if status.LastReload != nil {
+		last := *status.LastReload
+		status.LastReload = &last
+	}
+	return &status, nil
+}
@@ -0,0 +1,76 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+func TestReloadPolicy(t *testing.T) {
+	ctx := context.Background()
+	denyAll := `package auth
+default allow := false
+`
+	allowAll := `package auth
+default allow := true
+`
+
+	source := allowAll
+	loader := func(ctx context.Context) (interfaces.Policy, error) {
+		return opac.New(opac.Data(map[string]string{"auth.rego": source}))
+	}
+
+	initial := gt.R1(opac.New(opac.Data(map[string]string{"auth.rego": denyAll}))).NoError(t)
+	loadedAt := time.Now().Add(-time.Hour)
+	uc := usecase.New(adapter.New(adapter.WithPolicy(initial)),
+		usecase.WithPolicyLoader(loader),
+		usecase.WithPolicyLoadedAt(loadedAt),
+	)
+	input := model.PolicyAuthzInput{Method: "POST", Path: "/msg/raw/test"}
+
+	gt.True(t, goerr.HasTag(uc.Authorize(ctx, input), types.ErrTagForbidden))
+
+	status := gt.R1(uc.PolicyStatus(ctx)).NoError(t)
+	gt.Equal(t, status.LoadedAt, loadedAt)
+	gt.Equal(t, status.LastReload, nil)
+
+	t.Run("new policy is applied", func(t *testing.T) {
+		gt.NoError(t, uc.ReloadPolicy(ctx, "test"))
+		gt.NoError(t, uc.Authorize(ctx, input))
+
+		status := gt.R1(uc.PolicyStatus(ctx)).NoError(t)
+		gt.NotEqual(t, status.LastReload, nil)
+		gt.True(t, status.LastReload.Success)
+		gt.Equal(t, status.LastReload.Trigger, "test")
+		gt.Equal(t, status.LoadedAt, status.LastReload.At)
+	})
+
+	t.Run("broken policy is not applied", func(t *testing.T) {
+		source = "package auth\nallow := {"
+		gt.Error(t, uc.ReloadPolicy(ctx, "test"))
+
+		// Previous policy is kept
+		gt.NoError(t, uc.Authorize(ctx, input))
+
+		status := gt.R1(uc.PolicyStatus(ctx)).NoError(t)
+		gt.NotEqual(t, status.LastReload, nil)
+		gt.False(t, status.LastReload.Success)
+		gt.NotEqual(t, status.LastReload.Error, "")
+		gt.True(t, status.LoadedAt.Before(status.LastReload.At))
+	})
+}
+
+func TestReloadPolicyNotConfigured(t *testing.T) {
+	uc := usecase.New(adapter.New())
+	err := uc.ReloadPolicy(context.Background(), "test")
+	gt.True(t, goerr.HasTag(err, types.ErrTagNotFound))
+}
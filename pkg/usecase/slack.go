@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
@@ -11,13 +13,270 @@ import (
 	"github.com/slack-go/slack"
 )
 
-func transmitSlack(ctx context.Context, msg model.SlackMessage, client interfaces.Slack) error {
+// transmitSlack posts the message of the delivery. If thread key is set, the message is posted as a reply in the thread started by the first message with the same key, or edits the first message if update is set. Files are uploaded to the thread of the message.
+func (x *UseCases) transmitSlack(ctx context.Context, d *model.Delivery, msg model.SlackMessage, client interfaces.Slack) error {
 	logger := logging.Extract(ctx)
 	logger.Debug("Transmit slack message", "message", msg)
 
-	attachment := buildSlackMessage(msg)
-	options := []slack.MsgOption{
-		slack.MsgOptionAttachments(attachment),
+	msg, err := x.applySlackMentions(ctx, client, msg)
+	if err != nil {
+		return err
+	}
+	options, err := buildSlackOptions(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := buildSlackFiles(msg.Files); err != nil {
+		return err
+	}
+	channel, err := x.resolveSlackChannel(ctx, client, msg.Channel)
+	if err != nil {
+		return err
+	}
+
+	channelID, threadTS, err := x.postSlack(ctx, msg, channel, client, options)
+	if err != nil {
+		return err
+	}
+	x.uploadSlackFiles(ctx, client, d, msg.Files, channelID, threadTS)
+
+	return nil
+}
+
+// postSlack posts or updates the message, and returns channel ID and timestamp of the thread that the message belongs to.
+func (x *UseCases) postSlack(ctx context.Context, msg model.SlackMessage, channel string, client interfaces.Slack, options []slack.MsgOption) (string, string, error) {
+	logger := logging.Extract(ctx)
+
+	// Message specified by timestamp is updated regardless of thread key
+	if msg.TS != "" {
+		if _, _, _, err := client.UpdateMessageContext(ctx, channel, msg.TS, options...); err != nil {
+			return "", "", goerr.Wrap(err, "failed to update slack message", goerr.V("message", msg))
+		}
+		return channel, msg.TS, nil
+	}
+
+	store := x.adaptors.SlackThreadStore()
+	if msg.ThreadKey == "" || store == nil {
+		if msg.ThreadKey != "" {
+			logger.Warn("Slack thread store is not configured, thread_key is ignored", "thread_key", msg.ThreadKey)
+		}
+		channelID, ts, err := client.PostMessageContext(ctx, channel, options...)
+		if err != nil {
+			return "", "", goerr.Wrap(err, "failed to post slack message", goerr.V("message", msg))
+		}
+		return channelID, ts, nil
+	}
+
+	// Lookup and save of the thread must not be interleaved, otherwise correlated messages sent at once start separate threads. Messages of other threads are not blocked.
+	unlock := x.threadLocks.lock(msg.Channel, msg.ThreadKey)
+	defer unlock()
+
+	thread, err := x.waitSlackThread(ctx, store, msg)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case thread != nil && msg.Update:
+		if _, _, _, err := client.UpdateMessageContext(ctx, thread.Channel, thread.TS, options...); err != nil {
+			return "", "", goerr.Wrap(err, "failed to update slack message", goerr.V("message", msg), goerr.V("thread", thread))
+		}
+		return thread.Channel, thread.TS, nil
+
+	case thread != nil:
+		options = append(options, slack.MsgOptionTS(thread.TS))
+		if msg.Broadcast {
+			options = append(options, slack.MsgOptionBroadcast())
+		}
+		channelID, _, err := client.PostMessageContext(ctx, channel, options...)
+		if err != nil {
+			return "", "", goerr.Wrap(err, "failed to reply slack message", goerr.V("message", msg), goerr.V("thread", thread))
+		}
+		return channelID, thread.TS, nil
+
+	default:
+		channelID, ts, err := client.PostMessageContext(ctx, channel, options...)
+		if err != nil {
+			if err := store.Release(ctx, msg.Channel, msg.ThreadKey); err != nil {
+				logger.Warn("Failed to release slack thread", "error", err, "channel", msg.Channel, "thread_key", msg.ThreadKey)
+			}
+			return "", "", goerr.Wrap(err, "failed to post slack message", goerr.V("message", msg))
+		}
+
+		// The message has been posted, so failure of saving thread should not cause retry and duplicated post.
+		if err := store.Put(ctx, msg.Channel, msg.ThreadKey, &model.SlackThread{Channel: channelID, TS: ts}); err != nil {
+			logger.Warn("Failed to save slack thread", "error", err, "channel", msg.Channel, "thread_key", msg.ThreadKey)
+		}
+		return channelID, ts, nil
+	}
+}
+
+// waitSlackThread returns the thread of the message. It returns nil after claiming the key if the thread does not exist, and then the caller must post the first message. If another process has claimed the key, it waits for the thread to be saved.
+func (x *UseCases) waitSlackThread(ctx context.Context, store interfaces.SlackThreadStore, msg model.SlackMessage) (*model.SlackThread, error) {
+	deadline := time.Now().Add(x.threadWait)
+	for {
+		thread, err := store.Get(ctx, msg.Channel, msg.ThreadKey)
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to get slack thread", goerr.V("message", msg))
+		}
+		if thread != nil {
+			return thread, nil
+		}
+
+		// Claim expires in case the process stops before saving the thread
+		claimed, err := store.Claim(ctx, msg.Channel, msg.ThreadKey, max(x.threadWait, slackThreadClaimTTL))
+		if err != nil {
+			return nil, goerr.Wrap(err, "failed to claim slack thread", goerr.V("message", msg))
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, goerr.Wrap(&slackThreadBusyError{}, "first message of slack thread is not posted yet", goerr.V("message", msg))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, goerr.Wrap(ctx.Err(), "canceled while waiting slack thread", goerr.V("message", msg))
+		case <-time.After(slackThreadPollInterval):
+		}
+	}
+}
+
+const (
+	slackThreadClaimTTL     = 30 * time.Second
+	slackThreadPollInterval = 100 * time.Millisecond
+)
+
+// slackThreadBusyError is returned when another process is posting the first message of the thread. It's retried later to reply in the thread.
+type slackThreadBusyError struct{}
+
+func (x *slackThreadBusyError) Error() string {
+	return "slack thread is claimed by another process"
+}
+
+func (x *slackThreadBusyError) Retryable() bool {
+	return true
+}
+
+// slackThreadLocks serializes messages by channel and thread key.
+type slackThreadLocks struct {
+	mutex sync.Mutex
+	locks map[string]*slackThreadLock
+}
+
+type slackThreadLock struct {
+	mutex sync.Mutex
+	refs  int
+}
+
+// lock acquires lock of the thread and returns function to release it. The lock is removed when no one holds or waits it.
+func (x *slackThreadLocks) lock(channel, key string) func() {
+	k := channel + "\x00" + key
+
+	x.mutex.Lock()
+	if x.locks == nil {
+		x.locks = map[string]*slackThreadLock{}
+	}
+	l, ok := x.locks[k]
+	if !ok {
+		l = &slackThreadLock{}
+		x.locks[k] = l
+	}
+	l.refs++
+	x.mutex.Unlock()
+
+	l.mutex.Lock()
+	return func() {
+		l.mutex.Unlock()
+
+		x.mutex.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(x.locks, k)
+		}
+		x.mutex.Unlock()
+	}
+}
+
+// renderSlackMessage returns parameters of chat.postMessage API that transmitSlack sends.
+func renderSlackMessage(msg model.SlackMessage) any {
+	payload := map[string]any{
+		"channel": msg.Channel,
+	}
+
+	if err := validateSlackActions(msg.Actions); err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	if len(msg.Blocks) > 0 {
+		if _, err := buildSlackBlocks(msg.Blocks); err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		payload["blocks"] = msg.Blocks
+	}
+	if hasSlackAttachment(msg) {
+		payload["attachments"] = []slack.Attachment{buildSlackMessage(msg)}
+	}
+	if text := slackFallbackText(msg); text != "" {
+		payload["text"] = text
+	}
+
+	if msg.Emoji != "" {
+		payload["icon_emoji"] = msg.Emoji
+	} else if msg.Icon != "" {
+		payload["icon_url"] = msg.Icon
+	}
+
+	if len(msg.Files) > 0 {
+		files, err := buildSlackFiles(msg.Files)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		payload["files"] = renderSlackFiles(files)
+	}
+
+	if msg.TS != "" {
+		payload["ts"] = msg.TS
+	}
+
+	// Users and direct message channel are resolved only at transmission.
+	if len(msg.Mentions) > 0 {
+		payload["mentions"] = msg.Mentions
+	}
+
+	// Timestamp of the thread is known only at transmission, so the key is rendered instead.
+	if msg.ThreadKey != "" {
+		payload["thread_key"] = msg.ThreadKey
+		if msg.Update {
+			payload["update"] = true
+		} else if msg.Broadcast {
+			payload["reply_broadcast"] = true
+		}
+	}
+
+	return payload
+}
+
+// buildSlackOptions returns options of chat.postMessage and chat.update except channel and thread.
+func buildSlackOptions(msg model.SlackMessage) ([]slack.MsgOption, error) {
+	var options []slack.MsgOption
+
+	if err := validateSlackActions(msg.Actions); err != nil {
+		return nil, err
+	}
+	if len(msg.Blocks) > 0 {
+		blocks, err := buildSlackBlocks(msg.Blocks)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, slack.MsgOptionBlocks(blocks...))
+	}
+	if hasSlackAttachment(msg) {
+		options = append(options, slack.MsgOptionAttachments(buildSlackMessage(msg)))
+	}
+	if text := slackFallbackText(msg); text != "" {
+		options = append(options, slack.MsgOptionText(text, false))
 	}
 
 	if msg.Emoji != "" { // Emoji has higher priority than Icon
@@ -26,28 +285,67 @@ func transmitSlack(ctx context.Context, msg model.SlackMessage, client interface
 		options = append(options, slack.MsgOptionIconURL(msg.Icon))
 	}
 
-	if _, _, err := client.PostMessageContext(ctx, msg.Channel, options...); err != nil {
-		return goerr.Wrap(err, "failed to post slack message", goerr.V("message", msg), goerr.V("attachment", attachment))
+	return options, nil
+}
+
+// hasSlackAttachment returns true if the simplified fields should be rendered as attachment. Without blocks, the attachment is always rendered as before.
+func hasSlackAttachment(msg model.SlackMessage) bool {
+	return len(msg.Blocks) == 0 || msg.Title != "" || msg.Body != "" || len(msg.Fields) > 0 || len(msg.Actions) > 0
+}
+
+func validateSlackActions(actions []model.SlackMessageAction) error {
+	if len(actions) > slackMaxActionElements {
+		return goerr.New("too many slack actions", goerr.V("count", len(actions)), goerr.V("max", slackMaxActionElements))
 	}
 
+	actionIDs := map[string]struct{}{}
+	for i, action := range actions {
+		if action.Text == "" {
+			return goerr.New("text of slack action is required", goerr.V("index", i))
+		}
+		if action.Style != "" && action.Style != string(slack.StylePrimary) && action.Style != string(slack.StyleDanger) {
+			return goerr.New("style of slack action must be primary or danger", goerr.V("index", i), goerr.V("style", action.Style))
+		}
+		if action.ActionID != "" {
+			if _, dup := actionIDs[action.ActionID]; dup {
+				return goerr.New("duplicated action_id of slack action", goerr.V("index", i), goerr.V("action_id", action.ActionID))
+			}
+			actionIDs[action.ActionID] = struct{}{}
+		}
+	}
 	return nil
 }
 
+// slackFallbackText returns top-level text. It's not set for a message with only attachment because the text is displayed above the attachment.
+func slackFallbackText(msg model.SlackMessage) string {
+	if msg.Text != "" {
+		return msg.Text
+	}
+	if len(msg.Blocks) > 0 {
+		return msg.Title
+	}
+	return ""
+}
+
 var preservedColors = map[string]string{
 	"info":    "#2EB67D",
 	"warning": "#FFA500",
 	"error":   "#FF0000",
 }
 
-func buildSlackMessage(msg model.SlackMessage) slack.Attachment {
-	color := "#2EB67D"
-	if msg.Color != "" {
-		if preserved, ok := preservedColors[msg.Color]; ok {
-			color = preserved
-		} else {
-			color = msg.Color
-		}
+// resolveColor returns color code of the preserved color name. If color is not preserved name, it's returned as is. Default is color of "info".
+func resolveColor(color string) string {
+	if color == "" {
+		return preservedColors["info"]
+	}
+	if preserved, ok := preservedColors[color]; ok {
+		return preserved
 	}
+	return color
+}
+
+func buildSlackMessage(msg model.SlackMessage) slack.Attachment {
+	color := resolveColor(msg.Color)
 
 	var blockSet []slack.Block
 
@@ -71,7 +369,22 @@ func buildSlackMessage(msg model.SlackMessage) slack.Attachment {
 		fields[i] = slack.NewTextBlockObject("mrkdwn", mrkdwn, false, false)
 	}
 
-	blockSet = append(blockSet, slack.NewSectionBlock(body, fields, nil))
+	// Slack rejects section block without text and fields as invalid_blocks, e.g. a message with only actions.
+	if body != nil || len(fields) > 0 {
+		blockSet = append(blockSet, slack.NewSectionBlock(body, fields, nil))
+	}
+
+	if len(msg.Actions) > 0 {
+		elements := make([]slack.BlockElement, len(msg.Actions))
+		for i, action := range msg.Actions {
+			txt := slack.NewTextBlockObject("plain_text", action.Text, false, false)
+			button := slack.NewButtonBlockElement(action.ActionID, action.Value, txt)
+			button.URL = action.URL
+			button.Style = slack.Style(action.Style)
+			elements[i] = button
+		}
+		blockSet = append(blockSet, slack.NewActionBlock("", elements...))
+	}
 
 	return slack.Attachment{
 		Color: color,
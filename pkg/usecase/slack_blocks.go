@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/slack-go/slack"
+)
+
+// Limits of Block Kit. See https://api.slack.com/reference/block-kit/blocks
+const (
+	slackMaxBlocks          = 50
+	slackMaxBlockIDLength   = 255
+	slackMaxHeaderLength    = 150
+	slackMaxSectionFields   = 10
+	slackMaxContextElements = 10
+	slackMaxActionElements  = 25
+)
+
+var slackBlockTypes = map[string]struct{}{
+	"actions":   {},
+	"context":   {},
+	"divider":   {},
+	"file":      {},
+	"header":    {},
+	"image":     {},
+	"input":     {},
+	"markdown":  {},
+	"rich_text": {},
+	"section":   {},
+	"video":     {},
+}
+
+// rawSlackBlock is a block given by policy. It's marshaled as is, so fields that slack-go does not know are preserved.
+type rawSlackBlock struct {
+	raw       map[string]any
+	blockType slack.MessageBlockType
+}
+
+func (x rawSlackBlock) BlockType() slack.MessageBlockType {
+	return x.blockType
+}
+
+func (x rawSlackBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.raw)
+}
+
+// buildSlackBlocks validates Block Kit structure of the blocks and returns them to be passed through.
+func buildSlackBlocks(blocks []map[string]any) ([]slack.Block, error) {
+	if len(blocks) > slackMaxBlocks {
+		return nil, goerr.New("too many slack blocks", goerr.V("count", len(blocks)), goerr.V("max", slackMaxBlocks))
+	}
+
+	blockIDs := map[string]struct{}{}
+	result := make([]slack.Block, len(blocks))
+	for i, block := range blocks {
+		if err := validateSlackBlock(block); err != nil {
+			return nil, goerr.Wrap(err, "invalid slack block", goerr.V("index", i), goerr.V("block", block))
+		}
+
+		if id, ok := block["block_id"].(string); ok {
+			if _, dup := blockIDs[id]; dup {
+				return nil, goerr.New("duplicated block_id of slack block", goerr.V("index", i), goerr.V("block_id", id))
+			}
+			blockIDs[id] = struct{}{}
+		}
+
+		result[i] = rawSlackBlock{
+			raw:       block,
+			blockType: slack.MessageBlockType(block["type"].(string)),
+		}
+	}
+
+	return result, nil
+}
+
+func validateSlackBlock(block map[string]any) error {
+	blockType, ok := block["type"].(string)
+	if !ok {
+		return goerr.New("type of slack block is required")
+	}
+	if _, ok := slackBlockTypes[blockType]; !ok {
+		return goerr.New("unsupported type of slack block", goerr.V("type", blockType))
+	}
+
+	if id, ok := block["block_id"]; ok {
+		s, ok := id.(string)
+		if !ok || s == "" || len(s) > slackMaxBlockIDLength {
+			return goerr.New("block_id must be non-empty string up to 255 characters", goerr.V("block_id", id))
+		}
+	}
+
+	// Decoding by slack-go checks types of known fields
+	raw, err := json.Marshal([]any{block})
+	if err != nil {
+		return goerr.Wrap(err, "failed to marshal slack block")
+	}
+	var decoded slack.Blocks
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return goerr.Wrap(err, "malformed slack block")
+	}
+	if len(decoded.BlockSet) != 1 {
+		return goerr.New("malformed slack block")
+	}
+
+	switch b := decoded.BlockSet[0].(type) {
+	case *slack.HeaderBlock:
+		if b.Text == nil || b.Text.Type != slack.PlainTextType {
+			return goerr.New("text of header block must be plain_text")
+		}
+		if utf8.RuneCountInString(b.Text.Text) > slackMaxHeaderLength {
+			return goerr.New("text of header block is too long", goerr.V("max", slackMaxHeaderLength))
+		}
+		return b.Text.Validate()
+
+	case *slack.SectionBlock:
+		if b.Text == nil && len(b.Fields) == 0 {
+			return goerr.New("section block requires text or fields")
+		}
+		if len(b.Fields) > slackMaxSectionFields {
+			return goerr.New("too many fields in section block", goerr.V("max", slackMaxSectionFields))
+		}
+		if b.Text != nil {
+			if err := b.Text.Validate(); err != nil {
+				return goerr.Wrap(err, "invalid text of section block")
+			}
+		}
+		for _, field := range b.Fields {
+			if err := field.Validate(); err != nil {
+				return goerr.Wrap(err, "invalid field of section block")
+			}
+		}
+
+	case *slack.ImageBlock:
+		if b.ImageURL == "" && b.SlackFile == nil {
+			return goerr.New("image block requires image_url or slack_file")
+		}
+		if b.AltText == "" {
+			return goerr.New("image block requires alt_text")
+		}
+
+	case *slack.ContextBlock:
+		n := len(b.ContextElements.Elements)
+		if n == 0 || n > slackMaxContextElements {
+			return goerr.New("context block requires 1 to 10 elements", goerr.V("count", n))
+		}
+
+	case *slack.ActionBlock:
+		n := 0
+		if b.Elements != nil {
+			n = len(b.Elements.ElementSet)
+		}
+		if n == 0 || n > slackMaxActionElements {
+			return goerr.New("actions block requires 1 to 25 elements", goerr.V("count", n))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/slack-go/slack"
+)
+
+// buildSlackFiles returns parameters of external upload without channel and thread. Files are validated before posting the message, so that invalid files do not leave the message without them.
+func buildSlackFiles(files []model.SlackFile) ([]slack.UploadFileV2Parameters, error) {
+	params := make([]slack.UploadFileV2Parameters, len(files))
+	for i, file := range files {
+		if file.Filename == "" {
+			return nil, goerr.New("filename of slack file is required", goerr.V("index", i))
+		}
+
+		filetype := file.Filetype
+		var content string
+		switch v := file.Content.(type) {
+		case string:
+			content = v
+		default:
+			raw, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return nil, goerr.Wrap(err, "failed to marshal content of slack file", goerr.V("filename", file.Filename))
+			}
+			content = string(raw)
+			if filetype == "" {
+				filetype = "json"
+			}
+		}
+		if content == "" {
+			return nil, goerr.New("content of slack file is empty", goerr.V("filename", file.Filename))
+		}
+
+		filename := file.Filename
+		if filetype != "" && filepath.Ext(filename) == "" {
+			filename += "." + filetype
+		}
+
+		params[i] = slack.UploadFileV2Parameters{
+			Filename: filename,
+			Title:    file.Title,
+			Content:  content,
+			FileSize: len(content),
+		}
+	}
+
+	return params, nil
+}
+
+// uploadSlackFiles uploads files to the thread of the posted message. Each file is a delivery derived from the delivery of the message. If the delivery queue is configured, they are enqueued, so that failed upload is retried without posting the message again and is kept in the dead letter store when given up. Otherwise, failure is logged instead of returning error to avoid duplicated post.
+func (x *UseCases) uploadSlackFiles(ctx context.Context, client interfaces.Slack, parent *model.Delivery, files []model.SlackFile, channelID, threadTS string) {
+	logger := logging.Extract(ctx)
+	queue := x.adaptors.Queue()
+	now := time.Now()
+
+	for _, file := range files {
+		d := &model.Delivery{
+			ID:      uuid.NewString(),
+			Message: parent.Message,
+			SlackFile: &model.SlackFileUpload{
+				Channel:  channelID,
+				ThreadTS: threadTS,
+				File:     file,
+			},
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+
+		if queue == nil {
+			if err := transmitSlackFile(ctx, *d.SlackFile, client); err != nil {
+				logger.Error("Failed to upload slack file", "error", err, "channel", channelID, "thread_ts", threadTS, "filename", file.Filename)
+			}
+			continue
+		}
+
+		if err := queue.Push(ctx, d); err != nil {
+			logger.Error("Failed to enqueue slack file", "error", err, "channel", channelID, "thread_ts", threadTS, "filename", file.Filename)
+		}
+	}
+
+	if queue != nil && len(files) > 0 {
+		x.notifyDelivery()
+	}
+}
+
+// transmitSlackFile uploads the file to the thread.
+func transmitSlackFile(ctx context.Context, upload model.SlackFileUpload, client interfaces.Slack) error {
+	files, err := buildSlackFiles([]model.SlackFile{upload.File})
+	if err != nil {
+		return err
+	}
+
+	params := files[0]
+	params.Channel = upload.Channel
+	params.ThreadTimestamp = upload.ThreadTS
+	if _, err := client.UploadFileV2Context(ctx, params); err != nil {
+		return goerr.Wrap(err, "failed to upload slack file", goerr.V("channel", upload.Channel), goerr.V("thread_ts", upload.ThreadTS), goerr.V("filename", params.Filename))
+	}
+	return nil
+}
+
+// renderSlackFileUpload returns the file without content and the thread that transmitSlackFile uploads to.
+func renderSlackFileUpload(upload model.SlackFileUpload) any {
+	files, err := buildSlackFiles([]model.SlackFile{upload.File})
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+
+	rendered := renderSlackFiles(files)[0]
+	rendered["channel"] = upload.Channel
+	rendered["thread_ts"] = upload.ThreadTS
+	return rendered
+}
+
+// renderSlackFiles returns files without content that can be large.
+func renderSlackFiles(files []slack.UploadFileV2Parameters) []map[string]any {
+	rendered := make([]map[string]any, len(files))
+	for i, file := range files {
+		rendered[i] = map[string]any{
+			"filename": file.Filename,
+			"title":    file.Title,
+			"size":     file.FileSize,
+		}
+	}
+	return rendered
+}
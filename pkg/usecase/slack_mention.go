@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/slack-go/slack"
+)
+
+// slackUserCache keeps Slack user ID by email to avoid calling users.lookupByEmail for every message. Empty user ID means the user is not found.
+type slackUserCache struct {
+	mutex   sync.Mutex
+	entries map[string]slackUserCacheEntry
+}
+
+type slackUserCacheEntry struct {
+	userID    string
+	expiresAt time.Time
+}
+
+func (x *slackUserCache) get(email string) (string, bool) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	entry, ok := x.entries[email]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.userID, true
+}
+
+func (x *slackUserCache) put(email, userID string, ttl time.Duration) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	if x.entries == nil {
+		x.entries = make(map[string]slackUserCacheEntry)
+	}
+	x.entries[email] = slackUserCacheEntry{userID: userID, expiresAt: time.Now().Add(ttl)}
+}
+
+func isEmail(v string) bool {
+	return strings.Contains(v, "@")
+}
+
+// lookupSlackUserByEmail returns Slack user ID of the email. It returns empty string if no user has the email.
+func (x *UseCases) lookupSlackUserByEmail(ctx context.Context, client interfaces.Slack, email string) (string, error) {
+	email = strings.ToLower(email)
+	if userID, ok := x.slackUsers.get(email); ok {
+		return userID, nil
+	}
+
+	var userID string
+	user, err := client.GetUserByEmailContext(ctx, email)
+	if err != nil {
+		var resp slack.SlackErrorResponse
+		if !errors.As(err, &resp) || resp.Err != "users_not_found" {
+			return "", goerr.Wrap(err, "failed to lookup slack user by email", goerr.V("email", email))
+		}
+	} else {
+		userID = user.ID
+	}
+
+	x.slackUsers.put(email, userID, x.slackUserCacheTTL)
+	return userID, nil
+}
+
+// resolveSlackUser returns Slack user ID of email or GitHub login. It returns empty string if the user is not found.
+func (x *UseCases) resolveSlackUser(ctx context.Context, client interfaces.Slack, name string) (string, error) {
+	if isEmail(name) {
+		return x.lookupSlackUserByEmail(ctx, client, name)
+	}
+
+	// GitHub login is case-insensitive
+	mapped, ok := x.slackUserMapping[strings.ToLower(name)]
+	if !ok {
+		return "", nil
+	}
+	if isEmail(mapped) {
+		return x.lookupSlackUserByEmail(ctx, client, mapped)
+	}
+	return mapped, nil
+}
+
+// applySlackMentions inserts mentions of resolved users at the head of the message. Mention in attachment does not notify the user, so they are put in the top-level text displayed above the attachment, or as a section block on top if the message has blocks. A user that is not found is written as is, so the reader can still know who is meant.
+func (x *UseCases) applySlackMentions(ctx context.Context, client interfaces.Slack, msg model.SlackMessage) (model.SlackMessage, error) {
+	if len(msg.Mentions) == 0 {
+		return msg, nil
+	}
+
+	mentions := make([]string, len(msg.Mentions))
+	for i, name := range msg.Mentions {
+		userID, err := x.resolveSlackUser(ctx, client, name)
+		if err != nil {
+			return msg, err
+		}
+		if userID == "" {
+			logging.Extract(ctx).Warn("Slack user to mention is not found", "name", name)
+			mentions[i] = name
+			continue
+		}
+		mentions[i] = "<@" + userID + ">"
+	}
+	text := strings.Join(mentions, " ")
+
+	if len(msg.Blocks) == 0 {
+		if msg.Text != "" {
+			text += "\n" + msg.Text
+		}
+		msg.Text = text
+		return msg, nil
+	}
+
+	block := map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": text},
+	}
+	msg.Blocks = append([]map[string]any{block}, msg.Blocks...)
+	return msg, nil
+}
+
+// resolveSlackChannel returns ID of direct message channel if the channel is email. Otherwise, the channel is returned as is.
+func (x *UseCases) resolveSlackChannel(ctx context.Context, client interfaces.Slack, channel string) (string, error) {
+	if !isEmail(channel) {
+		return channel, nil
+	}
+
+	userID, err := x.lookupSlackUserByEmail(ctx, client, channel)
+	if err != nil {
+		return "", err
+	}
+	if userID == "" {
+		return "", goerr.New("slack user of direct message is not found", goerr.V("channel", channel))
+	}
+
+	dm, _, _, err := client.OpenConversationContext(ctx, &slack.OpenConversationParameters{Users: []string{userID}})
+	if err != nil {
+		return "", goerr.Wrap(err, "failed to open slack direct message", goerr.V("channel", channel), goerr.V("user_id", userID))
+	}
+	return dm.ID, nil
+}
@@ -0,0 +1,749 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/adapter/deadletter"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/adapter/thread"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+	"github.com/slack-go/slack"
+)
+
+const policySlackThreadRego = `package route
+
+import rego.v1
+
+slack contains {
+	"channel": "#ci",
+	"title": sprintf("Workflow %s", [input.data.status]),
+	"thread_key": sprintf("run/%d", [input.data.run_id]),
+	"update": input.data.status == "passed",
+	"broadcast": input.data.status == "failed",
+} if {
+	input.schema == "for_slack_thread"
+}
+`
+
+func applySlackOptions(t testing.TB, options []slack.MsgOption) url.Values {
+	_, values, err := slack.UnsafeApplyMsgOptions("token", "#ci", "https://slack.com/api/", options...)
+	gt.NoError(t, err)
+	return values
+}
+
+func TestTransmitSlackThread(t *testing.T) {
+	var posted, updated []url.Values
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			posted = append(posted, applySlackOptions(t, options))
+			return "C0123", "1700000000.000100", nil
+		},
+		UpdateMessageContextFunc: func(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+			gt.Equal(t, channelID, "C0123")
+			gt.Equal(t, timestamp, "1700000000.000100")
+			updated = append(updated, applySlackOptions(t, options))
+			return channelID, timestamp, "", nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackThreadRego,
+	}))).NoError(t)
+
+	uc := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithSlackThreadStore(thread.NewMemory(time.Hour)),
+		adapter.WithPolicy(policy),
+	))
+	ctx := context.Background()
+	route := func(runID int, status string) {
+		gt.NoError(t, uc.Route(ctx, model.Message{
+			Schema: "for_slack_thread",
+			Data:   map[string]any{"run_id": runID, "status": status},
+		}))
+	}
+
+	// The first message starts the thread
+	route(1, "started")
+	gt.A(t, posted).Length(1)
+	gt.Equal(t, posted[0].Get("thread_ts"), "")
+
+	// Following messages are replies
+	route(1, "failed")
+	gt.A(t, posted).Length(2)
+	gt.Equal(t, posted[1].Get("thread_ts"), "1700000000.000100")
+	gt.Equal(t, posted[1].Get("reply_broadcast"), "true")
+
+	route(1, "retried")
+	gt.A(t, posted).Length(3)
+	gt.Equal(t, posted[2].Get("thread_ts"), "1700000000.000100")
+	gt.Equal(t, posted[2].Get("reply_broadcast"), "")
+
+	// Update edits the first message
+	route(1, "passed")
+	gt.A(t, posted).Length(3)
+	gt.A(t, updated).Length(1)
+
+	// Other key starts another thread
+	route(2, "started")
+	gt.A(t, posted).Length(4)
+	gt.Equal(t, posted[3].Get("thread_ts"), "")
+}
+
+func TestTransmitSlackThreadStoreError(t *testing.T) {
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			return "C0123", "1700000000.000100", nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackThreadRego,
+	}))).NoError(t)
+	msg := model.Message{
+		Schema: "for_slack_thread",
+		Data:   map[string]any{"run_id": 1, "status": "started"},
+	}
+
+	t.Run("failure of get is error", func(t *testing.T) {
+		store := &mock.SlackThreadStoreMock{
+			GetFunc: func(ctx context.Context, channel, key string) (*model.SlackThread, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithSlackThreadStore(store), adapter.WithPolicy(policy)))
+		gt.Error(t, uc.Route(context.Background(), msg))
+	})
+
+	t.Run("failure of put is not error because the message has been posted", func(t *testing.T) {
+		store := &mock.SlackThreadStoreMock{
+			GetFunc: func(ctx context.Context, channel, key string) (*model.SlackThread, error) {
+				return nil, nil
+			},
+			ClaimFunc: func(ctx context.Context, channel, key string, ttl time.Duration) (bool, error) {
+				return true, nil
+			},
+			PutFunc: func(ctx context.Context, channel, key string, thread *model.SlackThread) error {
+				return errors.New("connection refused")
+			},
+		}
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithSlackThreadStore(store), adapter.WithPolicy(policy)))
+		gt.NoError(t, uc.Route(context.Background(), msg))
+		gt.A(t, store.PutCalls()).Length(1)
+	})
+}
+
+func TestTransmitSlackThreadClaimed(t *testing.T) {
+	var mutex sync.Mutex
+	var posted []url.Values
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			posted = append(posted, applySlackOptions(t, options))
+			return "C0123", "1700000000.000200", nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackThreadRego,
+	}))).NoError(t)
+	msg := model.Message{
+		Schema: "for_slack_thread",
+		Data:   map[string]any{"run_id": 1, "status": "failed"},
+	}
+	ctx := context.Background()
+
+	t.Run("reply after another process posts the first message", func(t *testing.T) {
+		posted = nil
+		store := thread.NewMemory(time.Hour)
+		gt.True(t, gt.R1(store.Claim(ctx, "#ci", "run/1", time.Minute)).NoError(t))
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = store.Put(ctx, "#ci", "run/1", &model.SlackThread{Channel: "C0123", TS: "1700000000.000100"})
+		}()
+
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithSlackThreadStore(store), adapter.WithPolicy(policy)))
+		gt.NoError(t, uc.Route(ctx, msg))
+		gt.A(t, posted).Length(1)
+		gt.Equal(t, posted[0].Get("thread_ts"), "1700000000.000100")
+	})
+
+	t.Run("retry if the first message is not posted in time", func(t *testing.T) {
+		posted = nil
+		store := thread.NewMemory(time.Hour)
+		gt.True(t, gt.R1(store.Claim(ctx, "#ci", "run/1", 100*time.Millisecond)).NoError(t))
+
+		q := queue.NewMemory()
+		uc := usecase.New(
+			adapter.New(adapter.WithSlack(&slackMock), adapter.WithSlackThreadStore(store), adapter.WithPolicy(policy), adapter.WithQueue(q)),
+			usecase.WithSlackThreadWait(10*time.Millisecond),
+			usecase.WithBackoff(200*time.Millisecond, 200*time.Millisecond),
+			usecase.WithPollInterval(10*time.Millisecond),
+		)
+		gt.NoError(t, uc.Route(ctx, msg))
+
+		// The claim expires without thread, so the retried message starts a new thread
+		runDelivery(t, uc, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(posted) == 1 && q.Len() == 0
+		})
+		gt.Equal(t, posted[0].Get("thread_ts"), "")
+	})
+}
+
+const policySlackBlocksRego = `package route
+
+import rego.v1
+
+slack contains {
+	"channel": "#release",
+	"text": "v1.0.0 released",
+	"blocks": [
+		{"type": "header", "text": {"type": "plain_text", "text": "Release v1.0.0"}},
+		{"type": "divider"},
+		{"type": "context", "elements": [{"type": "mrkdwn", "text": "by *alice*"}]},
+		{
+			"type": "actions",
+			"block_id": "release_actions",
+			"elements": [{"type": "button", "text": {"type": "plain_text", "text": "Open"}, "url": "https://example.com/releases/v1.0.0", "style": "primary"}],
+		},
+	],
+} if {
+	input.schema == "for_slack_blocks"
+	not input.data.with_fields
+}
+
+slack contains {
+	"channel": "#release",
+	"title": "Release v1.0.0",
+	"color": "info",
+	"blocks": [{"type": "divider"}],
+} if {
+	input.schema == "for_slack_blocks"
+	input.data.with_fields
+}
+`
+
+func TestTransmitSlackBlocks(t *testing.T) {
+	testCases := map[string]struct {
+		withFields bool
+		text       string
+		attachment bool
+	}{
+		"blocks only":      {withFields: false, text: "v1.0.0 released", attachment: false},
+		"blocks and title": {withFields: true, text: "Release v1.0.0", attachment: true},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			var posted url.Values
+			slackMock := mock.SlackMock{
+				PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+					posted = applySlackOptions(t, options)
+					return "", "", nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": policySlackBlocksRego,
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+			gt.NoError(t, uc.Route(context.Background(), model.Message{
+				Schema: "for_slack_blocks",
+				Data:   map[string]any{"with_fields": tc.withFields},
+			}))
+
+			gt.Equal(t, posted.Get("text"), tc.text)
+			gt.Equal(t, posted.Get("attachments") != "", tc.attachment)
+
+			var blocks []map[string]any
+			gt.NoError(t, json.Unmarshal([]byte(posted.Get("blocks")), &blocks))
+			if !tc.withFields {
+				// Blocks are passed through as is, including fields slack-go does not keep
+				gt.A(t, blocks).Length(4)
+				gt.Equal(t, blocks[3]["block_id"], "release_actions")
+				elements := gt.Cast[[]any](t, blocks[3]["elements"])
+				button := gt.Cast[map[string]any](t, elements[0])
+				gt.Equal(t, button["style"], "primary")
+				gt.Equal(t, button["url"], "https://example.com/releases/v1.0.0")
+			}
+		})
+	}
+}
+
+func TestTransmitSlackInvalidBlocks(t *testing.T) {
+	testCases := map[string]string{
+		"no type":            `{"text": {"type": "mrkdwn", "text": "x"}}`,
+		"unknown type":       `{"type": "carousel"}`,
+		"mrkdwn header":      `{"type": "header", "text": {"type": "mrkdwn", "text": "x"}}`,
+		"empty section":      `{"type": "section"}`,
+		"malformed section":  `{"type": "section", "text": "x"}`,
+		"image without alt":  `{"type": "image", "image_url": "https://example.com/a.png"}`,
+		"empty context":      `{"type": "context", "elements": []}`,
+		"invalid block_id":   `{"type": "divider", "block_id": 1}`,
+		"duplicated blockid": `{"type": "divider", "block_id": "a"}, {"type": "divider", "block_id": "a"}`,
+	}
+
+	for title, blocks := range testCases {
+		t.Run(title, func(t *testing.T) {
+			slackMock := mock.SlackMock{
+				PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+					return "", "", nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": "package route\nimport rego.v1\nslack contains {\"channel\": \"#c\", \"blocks\": [" + blocks + "]}\n",
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+			gt.Error(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+			gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+		})
+	}
+}
+
+const policySlackMentionRego = `package route
+
+import rego.v1
+
+slack contains {
+	"channel": input.data.channel,
+	"title": "Build failed",
+	"body": "See logs",
+	"mentions": input.data.mentions,
+} if {
+	input.schema == "for_slack_mention"
+	not input.data.blocks
+}
+
+slack contains {
+	"channel": input.data.channel,
+	"text": "Build failed",
+	"blocks": [{"type": "divider"}],
+	"mentions": input.data.mentions,
+} if {
+	input.schema == "for_slack_mention"
+	input.data.blocks == true
+}
+
+slack contains {
+	"channel": input.data.channel,
+	"body": "See logs",
+	"blocks": [{"type": "divider"}],
+	"mentions": input.data.mentions,
+} if {
+	input.schema == "for_slack_mention"
+	input.data.blocks == "with_body"
+}
+`
+
+func TestTransmitSlackMentions(t *testing.T) {
+	var posted []url.Values
+	var channels []string
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			channels = append(channels, channelID)
+			posted = append(posted, applySlackOptions(t, options))
+			return channelID, "1700000000.000100", nil
+		},
+		GetUserByEmailContextFunc: func(ctx context.Context, email string) (*slack.User, error) {
+			switch email {
+			case "alice@example.com":
+				return &slack.User{ID: "UALICE"}, nil
+			case "bob@example.com":
+				return &slack.User{ID: "UBOB"}, nil
+			default:
+				return nil, slack.SlackErrorResponse{Err: "users_not_found"}
+			}
+		},
+		OpenConversationContextFunc: func(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+			gt.A(t, params.Users).Length(1).At(0, func(t testing.TB, v string) {
+				gt.Equal(t, v, "UALICE")
+			})
+			ch := &slack.Channel{}
+			ch.ID = "DALICE"
+			return ch, false, false, nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackMentionRego,
+	}))).NoError(t)
+
+	uc := usecase.New(
+		adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)),
+		usecase.WithSlackUserMapping(map[string]string{
+			"Octocat": "U0CTOCAT",
+			"bob-gh":  "bob@example.com",
+		}),
+	)
+	route := func(data map[string]any) {
+		gt.NoError(t, uc.Route(context.Background(), model.Message{Schema: "for_slack_mention", Data: data}))
+	}
+
+	t.Run("mentions are put in text above attachment", func(t *testing.T) {
+		posted, channels = nil, nil
+		route(map[string]any{
+			"channel":  "#ci",
+			"mentions": []any{"alice@example.com", "octocat", "bob-gh", "nobody@example.com", "unknown-gh"},
+		})
+		gt.A(t, channels).Length(1).At(0, func(t testing.TB, v string) {
+			gt.Equal(t, v, "#ci")
+		})
+
+		var attachments []slack.Attachment
+		gt.NoError(t, json.Unmarshal([]byte(posted[0].Get("attachments")), &attachments))
+		section := gt.Cast[*slack.SectionBlock](t, attachments[0].Blocks.BlockSet[1])
+		gt.Equal(t, section.Text.Text, "See logs")
+		gt.Equal(t, posted[0].Get("text"), "<@UALICE> <@U0CTOCAT> <@UBOB> nobody@example.com unknown-gh")
+	})
+
+	t.Run("mentions are inserted as block above attachment if blocks are given", func(t *testing.T) {
+		posted, channels = nil, nil
+		route(map[string]any{"channel": "#ci", "mentions": []any{"alice@example.com"}, "blocks": "with_body"})
+
+		var blocks []map[string]any
+		gt.NoError(t, json.Unmarshal([]byte(posted[0].Get("blocks")), &blocks))
+		gt.A(t, blocks).Length(2)
+		gt.Equal(t, blocks[0]["text"], any(map[string]any{"type": "mrkdwn", "text": "<@UALICE>"}))
+
+		var attachments []slack.Attachment
+		gt.NoError(t, json.Unmarshal([]byte(posted[0].Get("attachments")), &attachments))
+		section := gt.Cast[*slack.SectionBlock](t, attachments[0].Blocks.BlockSet[0])
+		gt.Equal(t, section.Text.Text, "See logs")
+	})
+
+	t.Run("mentions are inserted as block if only blocks are given", func(t *testing.T) {
+		posted, channels = nil, nil
+		route(map[string]any{"channel": "#ci", "mentions": []any{"alice@example.com"}, "blocks": true})
+
+		var blocks []map[string]any
+		gt.NoError(t, json.Unmarshal([]byte(posted[0].Get("blocks")), &blocks))
+		gt.A(t, blocks).Length(2)
+		gt.Equal(t, blocks[0]["text"], any(map[string]any{"type": "mrkdwn", "text": "<@UALICE>"}))
+		gt.Equal(t, posted[0].Get("attachments"), "")
+	})
+
+	t.Run("email channel is sent as direct message", func(t *testing.T) {
+		posted, channels = nil, nil
+		route(map[string]any{"channel": "alice@example.com", "mentions": []any{}})
+		gt.A(t, channels).Length(1).At(0, func(t testing.TB, v string) {
+			gt.Equal(t, v, "DALICE")
+		})
+	})
+
+	// Lookup results including not found are cached
+	gt.A(t, slackMock.GetUserByEmailContextCalls()).Length(3)
+}
+
+func TestTransmitSlackMentionError(t *testing.T) {
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackMentionRego,
+	}))).NoError(t)
+
+	t.Run("lookup failure is error to retry", func(t *testing.T) {
+		slackMock := mock.SlackMock{
+			GetUserByEmailContextFunc: func(ctx context.Context, email string) (*slack.User, error) {
+				return nil, &slack.RateLimitedError{RetryAfter: time.Second}
+			},
+		}
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+		gt.Error(t, uc.Route(context.Background(), model.Message{
+			Schema: "for_slack_mention",
+			Data:   map[string]any{"channel": "#ci", "mentions": []any{"alice@example.com"}},
+		}))
+		gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+	})
+
+	t.Run("unknown user of direct message is error", func(t *testing.T) {
+		slackMock := mock.SlackMock{
+			GetUserByEmailContextFunc: func(ctx context.Context, email string) (*slack.User, error) {
+				return nil, slack.SlackErrorResponse{Err: "users_not_found"}
+			},
+		}
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+		gt.Error(t, uc.Route(context.Background(), model.Message{
+			Schema: "for_slack_mention",
+			Data:   map[string]any{"channel": "nobody@example.com", "mentions": []any{}},
+		}))
+		gt.A(t, slackMock.OpenConversationContextCalls()).Length(0)
+		gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+	})
+}
+
+const policySlackFileRego = `package route
+
+import rego.v1
+
+slack contains {
+	"channel": "#ci",
+	"title": "Payload",
+	"thread_key": input.data.key,
+	"files": [
+		{"filename": "payload", "content": input.data.payload, "title": "Full payload"},
+		{"filename": "log.txt", "content": "line1\nline2"},
+	],
+} if {
+	input.schema == "for_slack_file"
+}
+`
+
+func TestTransmitSlackFiles(t *testing.T) {
+	var uploaded []slack.UploadFileV2Parameters
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			return "C0123", "1700000000.000" + strconv.Itoa(len(uploaded)), nil
+		},
+		UploadFileV2ContextFunc: func(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+			uploaded = append(uploaded, params)
+			return &slack.FileSummary{ID: "F0123"}, nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackFileRego,
+	}))).NoError(t)
+
+	uc := usecase.New(adapter.New(
+		adapter.WithSlack(&slackMock),
+		adapter.WithSlackThreadStore(thread.NewMemory(time.Hour)),
+		adapter.WithPolicy(policy),
+	))
+	route := func() {
+		gt.NoError(t, uc.Route(context.Background(), model.Message{
+			Schema: "for_slack_file",
+			Data: map[string]any{
+				"key":     "run/1",
+				"payload": map[string]any{"action": "opened", "number": 1},
+			},
+		}))
+	}
+
+	route()
+	gt.A(t, uploaded).Length(2)
+	gt.Equal(t, uploaded[0].Filename, "payload.json")
+	gt.Equal(t, uploaded[0].Title, "Full payload")
+	gt.Equal(t, uploaded[0].Content, "{\n  \"action\": \"opened\",\n  \"number\": 1\n}")
+	gt.Equal(t, uploaded[0].FileSize, len(uploaded[0].Content))
+	gt.Equal(t, uploaded[1].Filename, "log.txt")
+	gt.Equal(t, uploaded[1].Content, "line1\nline2")
+	for _, file := range uploaded {
+		gt.Equal(t, file.Channel, "C0123")
+		gt.Equal(t, file.ThreadTimestamp, "1700000000.0000")
+	}
+
+	// Files of reply are uploaded to the same thread
+	route()
+	gt.A(t, uploaded).Length(4)
+	gt.Equal(t, uploaded[3].ThreadTimestamp, "1700000000.0000")
+}
+
+func TestTransmitSlackFileError(t *testing.T) {
+	t.Run("invalid file is error before posting", func(t *testing.T) {
+		slackMock := mock.SlackMock{}
+		policy := gt.R1(opac.New(opac.Data(map[string]string{
+			"transmit.rego": `package route
+import rego.v1
+slack contains {"channel": "#ci", "title": "x", "files": [{"filename": "empty.txt", "content": ""}]}`,
+		}))).NoError(t)
+
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+		gt.Error(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+		gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+	})
+
+	t.Run("upload failure is not error because the message has been posted", func(t *testing.T) {
+		slackMock := mock.SlackMock{
+			PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+				return "C0123", "1700000000.000100", nil
+			},
+			UploadFileV2ContextFunc: func(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+				return nil, errors.New("upload failed")
+			},
+		}
+		policy := gt.R1(opac.New(opac.Data(map[string]string{
+			"transmit.rego": `package route
+import rego.v1
+slack contains {"channel": "#ci", "title": "x", "files": [{"filename": "a.txt", "content": "a"}, {"filename": "b.txt", "content": "b"}]}`,
+		}))).NoError(t)
+
+		uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+		gt.NoError(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+		gt.A(t, slackMock.PostMessageContextCalls()).Length(1)
+		gt.A(t, slackMock.UploadFileV2ContextCalls()).Length(2)
+	})
+
+	t.Run("upload is retried without posting the message again and given up one is dead letter", func(t *testing.T) {
+		slackMock := mock.SlackMock{
+			PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+				return "C0123", "1700000000.000100", nil
+			},
+			UploadFileV2ContextFunc: func(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+				if params.Filename == "a.txt" {
+					return nil, &slack.RateLimitedError{RetryAfter: 10 * time.Millisecond}
+				}
+				return &slack.FileSummary{ID: "F0123"}, nil
+			},
+		}
+		policy := gt.R1(opac.New(opac.Data(map[string]string{
+			"transmit.rego": `package route
+import rego.v1
+slack contains {"channel": "#ci", "title": "x", "files": [{"filename": "a.txt", "content": "a"}, {"filename": "b.txt", "content": "b"}]}`,
+		}))).NoError(t)
+
+		ctx := context.Background()
+		q := queue.NewMemory()
+		store := deadletter.NewMemory()
+		uc := usecase.New(
+			adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy), adapter.WithQueue(q), adapter.WithDeadLetter(store)),
+			usecase.WithMaxAttempts(3),
+			usecase.WithPollInterval(10*time.Millisecond),
+		)
+		gt.NoError(t, uc.Route(ctx, model.Message{Schema: "any"}))
+
+		runDelivery(t, uc, func() bool {
+			entries, _ := store.List(ctx)
+			return len(entries) == 1 && q.Len() == 0
+		})
+		gt.A(t, slackMock.PostMessageContextCalls()).Length(1)
+		// a.txt is attempted 3 times and b.txt once
+		gt.A(t, slackMock.UploadFileV2ContextCalls()).Length(4)
+
+		entries := gt.R1(store.List(ctx)).NoError(t)
+		gt.V(t, entries[0].Delivery.Slack).Nil()
+		gt.Equal(t, entries[0].Delivery.SlackFile.File.Filename, "a.txt")
+		gt.Equal(t, entries[0].Delivery.SlackFile.Channel, "C0123")
+		gt.Equal(t, entries[0].Delivery.SlackFile.ThreadTS, "1700000000.000100")
+	})
+}
+
+const policySlackInteractionRego = `package route
+
+import rego.v1
+
+slack contains {
+	"channel": "#alerts",
+	"title": "Disk full",
+	"actions": [
+		{"text": "Acknowledge", "action_id": "ack", "value": "incident-1", "style": "primary"},
+		{"text": "Runbook", "action_id": "runbook", "url": "https://example.com/runbook"},
+	],
+} if {
+	input.schema == "alert"
+}
+
+slack contains {
+	"channel": input.data.container.channel_id,
+	"ts": input.data.container.message_ts,
+	"title": "Disk full",
+	"body": sprintf("Acknowledged by <@%s>", [input.data.user.id]),
+} if {
+	input.source == "slack.interaction"
+	input.data.actions[0].action_id == "ack"
+}
+`
+
+func TestTransmitSlackInteraction(t *testing.T) {
+	var posted url.Values
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			posted = applySlackOptions(t, options)
+			return "C0123", "1700000000.000100", nil
+		},
+		UpdateMessageContextFunc: func(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+			return channelID, timestamp, "", nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policySlackInteractionRego,
+	}))).NoError(t)
+	uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+	ctx := context.Background()
+
+	gt.NoError(t, uc.Route(ctx, model.Message{Schema: "alert"}))
+	var attachments []slack.Attachment
+	gt.NoError(t, json.Unmarshal([]byte(posted.Get("attachments")), &attachments))
+	// Empty section is not rendered because Slack rejects it
+	gt.A(t, attachments[0].Blocks.BlockSet).Length(2)
+	actions := gt.Cast[*slack.ActionBlock](t, attachments[0].Blocks.BlockSet[1])
+	gt.A(t, actions.Elements.ElementSet).Length(2)
+	ack := gt.Cast[*slack.ButtonBlockElement](t, actions.Elements.ElementSet[0])
+	gt.Equal(t, ack.ActionID, "ack")
+	gt.Equal(t, ack.Value, "incident-1")
+	gt.Equal(t, ack.Style, slack.StylePrimary)
+	runbook := gt.Cast[*slack.ButtonBlockElement](t, actions.Elements.ElementSet[1])
+	gt.Equal(t, runbook.URL, "https://example.com/runbook")
+
+	// Click of the button updates the original message
+	gt.NoError(t, uc.Route(ctx, model.Message{
+		Source: "slack.interaction",
+		Schema: "block_actions",
+		Data: map[string]any{
+			"user":      map[string]any{"id": "U0123"},
+			"container": map[string]any{"channel_id": "C0123", "message_ts": "1700000000.000100"},
+			"actions":   []any{map[string]any{"action_id": "ack"}},
+		},
+	}))
+	gt.A(t, slackMock.PostMessageContextCalls()).Length(1)
+	gt.A(t, slackMock.UpdateMessageContextCalls()).Length(1).At(0, func(t testing.TB, v struct {
+		Ctx       context.Context
+		ChannelID string
+		Timestamp string
+		Options   []slack.MsgOption
+	}) {
+		gt.Equal(t, v.ChannelID, "C0123")
+		gt.Equal(t, v.Timestamp, "1700000000.000100")
+	})
+}
+
+func TestTransmitSlackActionsWithBlocks(t *testing.T) {
+	var posted url.Values
+	slackMock := mock.SlackMock{
+		PostMessageContextFunc: func(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+			posted = applySlackOptions(t, options)
+			return "C0123", "1700000000.000100", nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": "package route\nimport rego.v1\nslack contains {\"channel\": \"#c\", \"blocks\": [{\"type\": \"divider\"}], \"actions\": [{\"text\": \"Ack\", \"action_id\": \"ack\"}]}\n",
+	}))).NoError(t)
+
+	uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+	gt.NoError(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+
+	var attachments []slack.Attachment
+	gt.NoError(t, json.Unmarshal([]byte(posted.Get("attachments")), &attachments))
+	gt.A(t, attachments[0].Blocks.BlockSet).Length(1).At(0, func(t testing.TB, v slack.Block) {
+		gt.Equal(t, v.BlockType(), slack.MBTAction)
+	})
+}
+
+func TestTransmitSlackInvalidActions(t *testing.T) {
+	testCases := map[string]string{
+		"no text":       `{"action_id": "a"}`,
+		"invalid style": `{"text": "A", "action_id": "a", "style": "warning"}`,
+		"duplicated id": `{"text": "A", "action_id": "a"}, {"text": "B", "action_id": "a"}`,
+	}
+
+	for title, actions := range testCases {
+		t.Run(title, func(t *testing.T) {
+			slackMock := mock.SlackMock{}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": "package route\nimport rego.v1\nslack contains {\"channel\": \"#c\", \"title\": \"x\", \"actions\": [" + actions + "]}\n",
+			}))).NoError(t)
+
+			uc := usecase.New(adapter.New(adapter.WithSlack(&slackMock), adapter.WithPolicy(policy)))
+			gt.Error(t, uc.Route(context.Background(), model.Message{Schema: "any"}))
+			gt.A(t, slackMock.PostMessageContextCalls()).Length(0)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+)
+
+func transmitTeams(ctx context.Context, msg model.TeamsMessage, client interfaces.Teams) error {
+	logger := logging.Extract(ctx)
+	logger.Debug("Transmit teams message", "message", msg)
+
+	if err := client.Post(ctx, msg.Channel, buildTeamsMessage(msg)); err != nil {
+		return goerr.Wrap(err, "failed to post teams message", goerr.V("message", msg))
+	}
+
+	return nil
+}
+
+// renderTeamsMessage returns channel name and request body that transmitTeams sends.
+func renderTeamsMessage(msg model.TeamsMessage) any {
+	return map[string]any{
+		"channel": msg.Channel,
+		"body":    buildTeamsMessage(msg),
+	}
+}
+
+// teamsContainerStyles maps color of message to container style of Adaptive Card. Adaptive Card does not accept arbitrary color code, so unknown color is rendered with "emphasis" style.
+var teamsContainerStyles = map[string]string{
+	"info":    "good",
+	"warning": "warning",
+	"error":   "attention",
+}
+
+// buildTeamsMessage builds message with Adaptive Card. The same format is accepted by both incoming webhook and Workflows.
+func buildTeamsMessage(msg model.TeamsMessage) map[string]any {
+	style := "good"
+	if msg.Color != "" {
+		if preserved, ok := teamsContainerStyles[msg.Color]; ok {
+			style = preserved
+		} else {
+			style = "emphasis"
+		}
+	}
+
+	var body []map[string]any
+
+	if msg.Title != "" {
+		body = append(body, map[string]any{
+			"type":  "Container",
+			"style": style,
+			"bleed": true,
+			"items": []map[string]any{
+				{
+					"type":   "TextBlock",
+					"text":   msg.Title,
+					"size":   "Large",
+					"weight": "Bolder",
+					"wrap":   true,
+				},
+			},
+		})
+	}
+
+	if msg.Body != "" {
+		body = append(body, map[string]any{
+			"type": "TextBlock",
+			"text": msg.Body,
+			"wrap": true,
+		})
+	}
+
+	if len(msg.Fields) > 0 {
+		facts := make([]map[string]any, len(msg.Fields))
+		for i, field := range msg.Fields {
+			value := field.Value
+			if field.Link != "" {
+				value = "[" + field.Value + "](" + field.Link + ")"
+			}
+			facts[i] = map[string]any{
+				"title": field.Name,
+				"value": value,
+			}
+		}
+		body = append(body, map[string]any{
+			"type":  "FactSet",
+			"facts": facts,
+		})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+		"msteams": map[string]any{"width": "Full"},
+	}
+
+	if len(msg.Links) > 0 {
+		actions := make([]map[string]any, len(msg.Links))
+		for i, link := range msg.Links {
+			actions[i] = map[string]any{
+				"type":  "Action.OpenUrl",
+				"title": link.Title,
+				"url":   link.URL,
+			}
+		}
+		card["actions"] = actions
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+}
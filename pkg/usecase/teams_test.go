@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+const policyTeamsRego = `package route
+
+import rego.v1
+
+teams contains {
+	"channel": "alerts",
+	"title": "Alert",
+	"body": input.data.message,
+	"color": "error",
+	"fields": [
+		{"name": "Severity", "value": "high"},
+		{"name": "Issue", "value": "#1", "link": "https://example.com/issues/1"},
+	],
+	"links": [
+		{"title": "Open", "url": "https://example.com"},
+	],
+} if {
+	input.schema == "for_teams"
+}
+`
+
+func TestTransmitTeams(t *testing.T) {
+	teamsMock := mock.TeamsMock{
+		PostFunc: func(ctx context.Context, channel string, payload any) error {
+			return nil
+		},
+	}
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyTeamsRego,
+	}))).NoError(t)
+
+	uc := usecase.New(adapter.New(adapter.WithTeams(&teamsMock), adapter.WithPolicy(policy)))
+	gt.NoError(t, uc.Route(context.Background(), model.Message{
+		Schema: "for_teams",
+		Data:   map[string]any{"message": "Something happened"},
+	}))
+
+	gt.A(t, teamsMock.PostCalls()).Length(1).At(0, func(t testing.TB, v struct {
+		Ctx     context.Context
+		Channel string
+		Payload any
+	}) {
+		gt.Equal(t, v.Channel, "alerts")
+
+		msg := gt.Cast[map[string]any](t, v.Payload)
+		gt.Equal(t, msg["type"], "message")
+		attachments := gt.Cast[[]map[string]any](t, msg["attachments"])
+		gt.A(t, attachments).Length(1)
+		gt.Equal(t, attachments[0]["contentType"], "application/vnd.microsoft.card.adaptive")
+
+		card := gt.Cast[map[string]any](t, attachments[0]["content"])
+		body := gt.Cast[[]map[string]any](t, card["body"])
+		gt.A(t, body).Length(3)
+
+		// Title in colored container
+		gt.Equal(t, body[0]["style"], "attention")
+		title := gt.Cast[[]map[string]any](t, body[0]["items"])
+		gt.Equal(t, title[0]["text"], "Alert")
+
+		gt.Equal(t, body[1]["text"], "Something happened")
+
+		facts := gt.Cast[[]map[string]any](t, body[2]["facts"])
+		gt.A(t, facts).Length(2)
+		gt.Equal(t, facts[1]["value"], "[#1](https://example.com/issues/1)")
+
+		actions := gt.Cast[[]map[string]any](t, card["actions"])
+		gt.A(t, actions).Length(1)
+		gt.Equal(t, actions[0]["url"], "https://example.com")
+	})
+}
+
+func TestTransmitTeamsNotConfigured(t *testing.T) {
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyTeamsRego,
+	}))).NoError(t)
+
+	uc := usecase.New(adapter.New(adapter.WithPolicy(policy)))
+	gt.Error(t, uc.Route(context.Background(), model.Message{
+		Schema: "for_teams",
+		Data:   map[string]any{"message": "Something happened"},
+	}))
+}
@@ -0,0 +1,13 @@
+package auth
+
+import rego.v1
+
+default allow := false
+
+allow if {
+    input.auth.github.webhook.valid
+}
+
+allow if {
+    input.auth.google.email == "pubsub@example.iam.gserviceaccount.com"
+}
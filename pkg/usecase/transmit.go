@@ -5,28 +5,67 @@ import (
 
 	"github.com/m-mizutani/goerr/v2"
 	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/dryrun"
 	"github.com/m-mizutani/xroute/pkg/utils/logging"
 )
 
+// Evaluate queries "data.route" policy with the message and returns the output without transmitting it.
+func (x *UseCases) Evaluate(ctx context.Context, msg model.Message) (*model.PolicyTransmitOutput, error) {
+	input := model.PolicyTransmitInput{
+		Message: msg,
+	}
+	var output model.PolicyTransmitOutput
+
+	if err := x.adaptors.Policy().Query(ctx, "data.route", input, &output); err != nil {
+		return nil, goerr.Wrap(err, "Failed to query policy", goerr.V("message", msg))
+	}
+	logging.Extract(ctx).Debug("Query result", "input", input, "output", output)
+
+	return &output, nil
+}
+
+// Route evaluates "data.route" policy with the message and transmits the outputs. If the delivery queue is configured, outputs are enqueued and transmitted by RunDelivery asynchronously. Otherwise, they are transmitted before returning. In dry-run mode, rendered payloads are recorded to dryrun.Recorder in ctx instead.
 func (x *UseCases) Route(ctx context.Context, msg model.Message) error {
 	logger := logging.Extract(ctx)
 	logger.Debug("Run usecase")
 	eb := goerr.NewBuilder(goerr.V("message", msg))
 
-	input := model.PolicyTransmitInput{
-		Message: msg,
+	output, err := x.Evaluate(ctx, msg)
+	if err != nil {
+		return err
 	}
-	var output model.PolicyTransmitOutput
 
-	if err := x.adaptors.Policy().Query(ctx, "data.route", input, &output); err != nil {
-		return eb.Wrap(err, "Failed to query policy")
+	deliveries := newDeliveries(msg, *output)
+
+	if x.dryRun {
+		recorder := dryrun.Extract(ctx)
+		for _, d := range deliveries {
+			rendered := renderDelivery(d)
+			logger.Info("Dry-run, message is not transmitted", "destination", rendered.Destination, "payload", rendered.Payload)
+			recorder.Add(rendered)
+		}
+		return nil
 	}
-	logger.Debug("Query result", "input", input, "output", output)
 
-	for _, slackMsg := range output.Slack {
-		if err := transmitSlack(ctx, slackMsg, x.adaptors.Slack()); err != nil {
-			return eb.Wrap(err, "Failed to transmit slack message")
+	queue := x.adaptors.Queue()
+	if queue == nil {
+		for _, d := range deliveries {
+			if err := x.deliver(ctx, d); err != nil {
+				return eb.Wrap(err, "Failed to transmit message", goerr.V("destination", destinationName(d)))
+			}
 		}
+		return nil
+	}
+
+	for _, d := range deliveries {
+		if err := queue.Push(ctx, d); err != nil {
+			return eb.Wrap(err, "Failed to enqueue delivery", goerr.V("destination", destinationName(d)))
+		}
+		logger.Debug("Delivery enqueued", "id", d.ID, "destination", destinationName(d))
+	}
+
+	if len(deliveries) > 0 {
+		x.notifyDelivery()
 	}
 
 	return nil
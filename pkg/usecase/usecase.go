@@ -1,11 +1,145 @@
 package usecase
 
-import "github.com/m-mizutani/xroute/pkg/adapter"
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
 
 type UseCases struct {
 	adaptors *adapter.Adapters
+
+	maxAttempts  int
+	backoffBase  time.Duration
+	backoffMax   time.Duration
+	pollInterval time.Duration
+	wakeup       chan struct{}
+
+	dryRun bool
+
+	webhookCredentials map[string]model.WebhookCredential
+	webhookTimeout     time.Duration
+
+	policyLoader PolicyLoader
+	policyMutex  sync.Mutex
+	policyStatus model.PolicyStatus
+
+	threadLocks slackThreadLocks
+	threadWait  time.Duration
+
+	slackUserMapping  map[string]string
+	slackUserCacheTTL time.Duration
+	slackUsers        slackUserCache
+}
+
+// PolicyLoader loads policy from its source. It's called by ReloadPolicy.
+type PolicyLoader func(ctx context.Context) (interfaces.Policy, error)
+
+type Option func(*UseCases)
+
+// WithMaxAttempts sets maximum number of attempts to transmit a delivery. Default is 5.
+func WithMaxAttempts(n int) Option {
+	return func(x *UseCases) {
+		x.maxAttempts = n
+	}
+}
+
+// WithBackoff sets base and maximum interval of exponential backoff between delivery attempts. Default is 1 second and 5 minutes.
+func WithBackoff(base, max time.Duration) Option {
+	return func(x *UseCases) {
+		x.backoffBase = base
+		x.backoffMax = max
+	}
+}
+
+// WithPollInterval sets interval to check the queue for deliveries waiting for retry. Default is 1 second.
+func WithPollInterval(d time.Duration) Option {
+	return func(x *UseCases) {
+		x.pollInterval = d
+	}
+}
+
+// WithDryRun enables dry-run mode. In dry-run mode, Route renders payloads of outputs and logs them instead of transmitting.
+func WithDryRun() Option {
+	return func(x *UseCases) {
+		x.dryRun = true
+	}
 }
 
-func New(adaptors *adapter.Adapters) *UseCases {
-	return &UseCases{adaptors: adaptors}
+// WithWebhookCredentials sets credentials that webhook destination refers by name.
+func WithWebhookCredentials(credentials map[string]model.WebhookCredential) Option {
+	return func(x *UseCases) {
+		x.webhookCredentials = credentials
+	}
+}
+
+// WithWebhookTimeout sets timeout of a webhook request. Default is 10 seconds.
+func WithWebhookTimeout(d time.Duration) Option {
+	return func(x *UseCases) {
+		x.webhookTimeout = d
+	}
+}
+
+// WithSlackUserMapping sets Slack user by GitHub login to resolve mentions. The value is Slack user ID or email.
+func WithSlackUserMapping(mapping map[string]string) Option {
+	return func(x *UseCases) {
+		x.slackUserMapping = make(map[string]string, len(mapping))
+		for login, user := range mapping {
+			x.slackUserMapping[strings.ToLower(login)] = user
+		}
+	}
+}
+
+// WithSlackUserCacheTTL sets duration to cache Slack user looked up by email. Default is 1 hour.
+func WithSlackUserCacheTTL(d time.Duration) Option {
+	return func(x *UseCases) {
+		x.slackUserCacheTTL = d
+	}
+}
+
+// WithSlackThreadWait sets duration to wait for the first message of a thread posted by another process. Default is 5 seconds.
+func WithSlackThreadWait(d time.Duration) Option {
+	return func(x *UseCases) {
+		x.threadWait = d
+	}
+}
+
+// WithPolicyLoader enables ReloadPolicy. The loader should load the policy from the same source as the initial one.
+func WithPolicyLoader(loader PolicyLoader) Option {
+	return func(x *UseCases) {
+		x.policyLoader = loader
+	}
+}
+
+// WithPolicyLoadedAt sets when the initial policy given to adapters was loaded. It's reported by PolicyStatus until the policy is reloaded.
+func WithPolicyLoadedAt(t time.Time) Option {
+	return func(x *UseCases) {
+		x.policyStatus.LoadedAt = t
+	}
+}
+
+func New(adaptors *adapter.Adapters, options ...Option) *UseCases {
+	uc := &UseCases{
+		adaptors:     adaptors,
+		maxAttempts:  5,
+		backoffBase:  time.Second,
+		backoffMax:   5 * time.Minute,
+		pollInterval: time.Second,
+		wakeup:       make(chan struct{}, 1),
+
+		webhookTimeout:    10 * time.Second,
+		slackUserCacheTTL: time.Hour,
+		threadWait:        5 * time.Second,
+	}
+
+	for _, opt := range options {
+		opt(uc)
+	}
+
+	return uc
 }
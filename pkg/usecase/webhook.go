@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/domain/interfaces"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/domain/types"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+func (x *UseCases) transmitWebhook(ctx context.Context, msg model.WebhookMessage, client interfaces.HTTPClient) error {
+	logger := logging.Extract(ctx)
+	logger.Debug("Transmit webhook", "url", msg.URL, "method", msg.Method)
+
+	ctx, cancel := context.WithTimeout(ctx, x.webhookTimeout)
+	defer cancel()
+
+	req, err := x.buildWebhookRequest(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	// Redirect is not followed with credential, because custom headers are forwarded to the redirected URL
+	if msg.Credential != "" {
+		client = withoutRedirect(client)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return goerr.Wrap(err, "failed to send webhook", goerr.V("url", msg.URL))
+	}
+	defer safe.Close(ctx, resp.Body)
+
+	if !isExpectedStatus(resp.StatusCode, msg.ExpectedStatus) {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		webhookErr := types.NewHTTPStatusError("webhook", resp.StatusCode, string(body), types.ParseRetryAfter(resp.Header))
+		return goerr.Wrap(webhookErr, "failed to transmit webhook", goerr.V("url", msg.URL), goerr.V("method", req.Method))
+	}
+
+	return nil
+}
+
+func (x *UseCases) buildWebhookRequest(ctx context.Context, msg model.WebhookMessage) (*http.Request, error) {
+	u, err := url.Parse(msg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, goerr.New("invalid webhook URL", goerr.V("url", msg.URL))
+	}
+
+	method := strings.ToUpper(msg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	body, contentType, err := encodeWebhookBody(msg.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, msg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, goerr.Wrap(err, "failed to create webhook request", goerr.V("url", msg.URL), goerr.V("method", method))
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range msg.Header {
+		req.Header.Set(k, v)
+	}
+
+	if msg.Credential != "" {
+		cred, ok := x.webhookCredentials[msg.Credential]
+		if !ok {
+			return nil, goerr.New("webhook credential is not configured", goerr.V("credential", msg.Credential))
+		}
+		if !isWebhookCredentialURL(cred, u) {
+			return nil, goerr.New("webhook URL is not allowed for the credential", goerr.V("credential", msg.Credential), goerr.V("url", msg.URL))
+		}
+		req.Header.Set(cred.Header, cred.Value)
+	}
+
+	return req, nil
+}
+
+// isWebhookCredentialURL returns true if the credential is allowed to be sent to u. u must be https, have the same host as URL of the credential, and its path must be under the path of it.
+func isWebhookCredentialURL(cred model.WebhookCredential, u *url.URL) bool {
+	allowed, err := url.Parse(cred.URL)
+	if err != nil || allowed.Scheme != "https" || allowed.Host == "" {
+		return false
+	}
+	if u.Scheme != "https" || !strings.EqualFold(u.Host, allowed.Host) {
+		return false
+	}
+
+	// Path is cleaned because the server may resolve dot segments, e.g. /api/../admin
+	prefix := strings.TrimSuffix(allowed.Path, "/")
+	p := path.Clean("/" + u.Path)
+	return prefix == "" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// withoutRedirect returns copy of the client that does not follow redirect. Client other than *http.Client is returned as is.
+func withoutRedirect(client interfaces.HTTPClient) interfaces.HTTPClient {
+	c, ok := client.(*http.Client)
+	if !ok {
+		return client
+	}
+	copied := *c
+	copied.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &copied
+}
+
+func encodeWebhookBody(body any) ([]byte, string, error) {
+	switch v := body.(type) {
+	case nil:
+		return nil, "", nil
+	case string:
+		return []byte(v), "", nil
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, "", goerr.Wrap(err, "failed to encode webhook body")
+		}
+		return raw, "application/json", nil
+	}
+}
+
+func isExpectedStatus(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return 200 <= code && code < 300
+	}
+	return slices.Contains(expected, code)
+}
+
+// renderWebhookMessage returns the request that transmitWebhook sends. Value of the credential is masked.
+func renderWebhookMessage(msg model.WebhookMessage) any {
+	method := strings.ToUpper(msg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	header := map[string]string{}
+	for k, v := range msg.Header {
+		header[k] = v
+	}
+	if msg.Credential != "" {
+		header["(credential)"] = msg.Credential
+	}
+
+	return map[string]any{
+		"url":    msg.URL,
+		"method": method,
+		"header": header,
+		"body":   msg.Body,
+	}
+}
@@ -0,0 +1,282 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/opac"
+	"github.com/m-mizutani/xroute/pkg/adapter"
+	"github.com/m-mizutani/xroute/pkg/adapter/deadletter"
+	"github.com/m-mizutani/xroute/pkg/adapter/queue"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/mock"
+	"github.com/m-mizutani/xroute/pkg/usecase"
+)
+
+const policyWebhookRego = `package route
+
+import rego.v1
+
+webhook contains {
+	"url": "https://tickets.example.com/api/issues",
+	"header": {"X-Source": "xroute"},
+	"body": {
+		"summary": input.data.title,
+		"labels": ["alert"],
+	},
+	"credential": "tickets",
+} if {
+	input.schema == "json_body"
+}
+
+webhook contains {
+	"url": "https://soar.example.com/hook",
+	"method": "put",
+	"header": {"Content-Type": "text/plain"},
+	"body": sprintf("alert: %s", [input.data.title]),
+	"expected_status": [204],
+} if {
+	input.schema == "text_body"
+}
+`
+
+func newWebhookPolicy(t *testing.T) *opac.Client {
+	return gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": policyWebhookRego,
+	}))).NoError(t)
+}
+
+func httpResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestTransmitWebhook(t *testing.T) {
+	httpMock := &mock.HTTPClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodPut {
+				return httpResponse(http.StatusNoContent, ""), nil
+			}
+			return httpResponse(http.StatusCreated, `{"id":1}`), nil
+		},
+	}
+
+	uc := usecase.New(
+		adapter.New(adapter.WithHTTPClient(httpMock), adapter.WithPolicy(newWebhookPolicy(t))),
+		usecase.WithWebhookCredentials(map[string]model.WebhookCredential{
+			"tickets": {Header: "Authorization", Value: "Bearer secret", URL: "https://tickets.example.com/api/"},
+		}),
+	)
+	ctx := context.Background()
+
+	t.Run("JSON body with credential", func(t *testing.T) {
+		gt.NoError(t, uc.Route(ctx, model.Message{
+			Schema: "json_body",
+			Data:   map[string]any{"title": "Disk full"},
+		}))
+
+		calls := httpMock.DoCalls()
+		gt.A(t, calls).Length(1)
+		req := calls[0].Req
+		gt.Equal(t, req.Method, http.MethodPost)
+		gt.Equal(t, req.URL.String(), "https://tickets.example.com/api/issues")
+		gt.Equal(t, req.Header.Get("Authorization"), "Bearer secret")
+		gt.Equal(t, req.Header.Get("X-Source"), "xroute")
+		gt.Equal(t, req.Header.Get("Content-Type"), "application/json")
+
+		var body map[string]any
+		gt.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		gt.Equal(t, body["summary"], "Disk full")
+	})
+
+	t.Run("string body with expected status", func(t *testing.T) {
+		gt.NoError(t, uc.Route(ctx, model.Message{
+			Schema: "text_body",
+			Data:   map[string]any{"title": "Disk full"},
+		}))
+
+		calls := httpMock.DoCalls()
+		gt.A(t, calls).Length(2)
+		req := calls[1].Req
+		gt.Equal(t, req.Method, http.MethodPut)
+		gt.Equal(t, req.Header.Get("Content-Type"), "text/plain")
+		gt.Equal(t, req.Header.Get("Authorization"), "")
+		body := gt.R1(io.ReadAll(req.Body)).NoError(t)
+		gt.Equal(t, string(body), "alert: Disk full")
+	})
+}
+
+func TestTransmitWebhookUnknownCredential(t *testing.T) {
+	httpMock := &mock.HTTPClientMock{}
+	uc := usecase.New(adapter.New(adapter.WithHTTPClient(httpMock), adapter.WithPolicy(newWebhookPolicy(t))))
+
+	gt.Error(t, uc.Route(context.Background(), model.Message{
+		Schema: "json_body",
+		Data:   map[string]any{"title": "Disk full"},
+	}))
+	gt.A(t, httpMock.DoCalls()).Length(0)
+}
+
+func TestWebhookCredentialURL(t *testing.T) {
+	testCases := map[string]struct {
+		url     string
+		allowed bool
+	}{
+		"under the path":        {url: "https://tickets.example.com/api/issues", allowed: true},
+		"the path itself":       {url: "https://tickets.example.com/api", allowed: true},
+		"other path":            {url: "https://tickets.example.com/admin", allowed: false},
+		"similar path":          {url: "https://tickets.example.com/apiv2/issues", allowed: false},
+		"dot segments":          {url: "https://tickets.example.com/api/../admin", allowed: false},
+		"other host":            {url: "https://attacker.example.com/api/issues", allowed: false},
+		"host with same prefix": {url: "https://tickets.example.com.attacker.com/api/issues", allowed: false},
+		"plain http":            {url: "http://tickets.example.com/api/issues", allowed: false},
+	}
+
+	for title, tc := range testCases {
+		t.Run(title, func(t *testing.T) {
+			httpMock := &mock.HTTPClientMock{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return httpResponse(http.StatusOK, ""), nil
+				},
+			}
+			policy := gt.R1(opac.New(opac.Data(map[string]string{
+				"transmit.rego": `package route
+
+import rego.v1
+
+webhook contains {"url": input.data.url, "credential": "tickets"}
+`,
+			}))).NoError(t)
+			uc := usecase.New(
+				adapter.New(adapter.WithHTTPClient(httpMock), adapter.WithPolicy(policy)),
+				usecase.WithWebhookCredentials(map[string]model.WebhookCredential{
+					"tickets": {Header: "Authorization", Value: "Bearer secret", URL: "https://tickets.example.com/api/"},
+				}),
+			)
+
+			err := uc.Route(context.Background(), model.Message{Data: map[string]any{"url": tc.url}})
+			if tc.allowed {
+				gt.NoError(t, err)
+				gt.A(t, httpMock.DoCalls()).Length(1)
+			} else {
+				gt.Error(t, err)
+				gt.A(t, httpMock.DoCalls()).Length(0)
+			}
+		})
+	}
+}
+
+func TestWebhookCredentialNotRedirected(t *testing.T) {
+	var redirected bool
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/steal", http.StatusTemporaryRedirect)
+	}))
+	defer ts.Close()
+
+	policy := gt.R1(opac.New(opac.Data(map[string]string{
+		"transmit.rego": `package route
+
+import rego.v1
+
+webhook contains {"url": input.data.url, "credential": "tickets"}
+`,
+	}))).NoError(t)
+	uc := usecase.New(
+		adapter.New(adapter.WithHTTPClient(ts.Client()), adapter.WithPolicy(policy)),
+		usecase.WithWebhookCredentials(map[string]model.WebhookCredential{
+			"tickets": {Header: "Authorization", Value: "Bearer secret", URL: ts.URL},
+		}),
+	)
+
+	err := uc.Route(context.Background(), model.Message{Data: map[string]any{"url": ts.URL + "/hook"}})
+	gt.Error(t, err)
+	gt.False(t, redirected)
+}
+
+func TestWebhookRetry(t *testing.T) {
+	var mutex sync.Mutex
+	statuses := []int{http.StatusBadGateway, http.StatusOK}
+	httpMock := &mock.HTTPClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			code := statuses[0]
+			statuses = statuses[1:]
+			return httpResponse(code, ""), nil
+		},
+	}
+
+	q := queue.NewMemory()
+	uc := usecase.New(
+		adapter.New(adapter.WithHTTPClient(httpMock), adapter.WithPolicy(newWebhookPolicy(t)), adapter.WithQueue(q)),
+		usecase.WithWebhookCredentials(map[string]model.WebhookCredential{
+			"tickets": {Header: "Authorization", Value: "Bearer secret", URL: "https://tickets.example.com/api/"},
+		}),
+		usecase.WithBackoff(10*time.Millisecond, 10*time.Millisecond),
+		usecase.WithPollInterval(10*time.Millisecond),
+	)
+
+	gt.NoError(t, uc.Route(context.Background(), model.Message{
+		Schema: "json_body",
+		Data:   map[string]any{"title": "Disk full"},
+	}))
+
+	runDelivery(t, uc, func() bool { return len(httpMock.DoCalls()) >= 2 && q.Len() == 0 })
+	gt.A(t, httpMock.DoCalls()).Length(2)
+}
+
+func TestWebhookClientError(t *testing.T) {
+	httpMock := &mock.HTTPClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return httpResponse(http.StatusBadRequest, "invalid summary"), nil
+		},
+	}
+
+	q := queue.NewMemory()
+	store := deadletter.NewMemory()
+	uc := usecase.New(
+		adapter.New(
+			adapter.WithHTTPClient(httpMock),
+			adapter.WithPolicy(newWebhookPolicy(t)),
+			adapter.WithQueue(q),
+			adapter.WithDeadLetter(store),
+		),
+		usecase.WithWebhookCredentials(map[string]model.WebhookCredential{
+			"tickets": {Header: "Authorization", Value: "Bearer secret", URL: "https://tickets.example.com/api/"},
+		}),
+		usecase.WithPollInterval(10*time.Millisecond),
+	)
+
+	ctx := context.Background()
+	gt.NoError(t, uc.Route(ctx, model.Message{
+		Schema: "json_body",
+		Data:   map[string]any{"title": "Disk full"},
+	}))
+
+	// 4xx is not retried
+	runDelivery(t, uc, func() bool {
+		entries, _ := store.List(ctx)
+		return len(entries) == 1
+	})
+	gt.A(t, httpMock.DoCalls()).Length(1)
+
+	entries := gt.R1(store.List(ctx)).NoError(t)
+	gt.S(t, entries[0].Error).Contains("invalid summary")
+	gt.Equal(t, entries[0].Delivery.Webhook.Credential, "tickets")
+}
@@ -0,0 +1,51 @@
+package dryrun
+
+import (
+	"context"
+	"sync"
+
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+)
+
+// Recorder collects rendered payloads in dry-run mode for a request.
+type Recorder struct {
+	mutex      sync.Mutex
+	deliveries []model.DryRunDelivery
+}
+
+func New() *Recorder {
+	return &Recorder{deliveries: []model.DryRunDelivery{}}
+}
+
+// Add records the delivery. It does nothing if the recorder is nil.
+func (x *Recorder) Add(d model.DryRunDelivery) {
+	if x == nil {
+		return
+	}
+
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	x.deliveries = append(x.deliveries, d)
+}
+
+// Deliveries returns recorded deliveries.
+func (x *Recorder) Deliveries() []model.DryRunDelivery {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	return append([]model.DryRunDelivery{}, x.deliveries...)
+}
+
+type ctxKey struct{}
+
+func Inject(ctx context.Context, recorder *Recorder) context.Context {
+	return context.WithValue(ctx, ctxKey{}, recorder)
+}
+
+// Extract returns the recorder in the context. It returns nil if no recorder is injected.
+func Extract(ctx context.Context) *Recorder {
+	if recorder, ok := ctx.Value(ctxKey{}).(*Recorder); ok {
+		return recorder
+	}
+	return nil
+}
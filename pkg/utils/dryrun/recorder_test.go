@@ -0,0 +1,26 @@
+package dryrun_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/domain/model"
+	"github.com/m-mizutani/xroute/pkg/utils/dryrun"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+
+	// No recorder in context, Add is ignored
+	gt.V(t, dryrun.Extract(ctx)).Nil()
+	dryrun.Extract(ctx).Add(model.DryRunDelivery{Destination: "slack"})
+
+	recorder := dryrun.New()
+	ctx = dryrun.Inject(ctx, recorder)
+	dryrun.Extract(ctx).Add(model.DryRunDelivery{Destination: "slack", Payload: "x"})
+
+	gt.A(t, recorder.Deliveries()).Length(1).At(0, func(t testing.TB, v model.DryRunDelivery) {
+		gt.Equal(t, v.Destination, "slack")
+	})
+}
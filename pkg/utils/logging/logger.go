@@ -25,8 +25,12 @@ func SetDefault(logger *slog.Logger) {
 }
 
 func Disable() {
-	logger := slog.New(slog.NewJSONHandler(&NopWriter{}, nil))
-	SetDefault(logger)
+	SetDefault(Nop())
+}
+
+// Nop returns logger that discards all logs.
+func Nop() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(&NopWriter{}, nil))
 }
 
 type NopWriter struct{}
@@ -0,0 +1,99 @@
+package watcher
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/m-mizutani/goerr/v2"
+	"github.com/m-mizutani/xroute/pkg/utils/logging"
+	"github.com/m-mizutani/xroute/pkg/utils/safe"
+)
+
+// Watch calls onChange when a file under path is created, modified, removed or renamed. path can be a file or a directory, and directories are watched recursively. Events within the debounce interval are merged into one call. Watch blocks until ctx is canceled.
+func Watch(ctx context.Context, path string, debounce time.Duration, onChange func()) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return goerr.Wrap(err, "failed to create file watcher")
+	}
+	defer safe.Close(ctx, fsw)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return goerr.Wrap(err, "failed to stat watched path", goerr.V("path", path))
+	}
+
+	// To follow a single file replaced by rename (e.g. editors and ConfigMap updates), watch its parent directory and filter events.
+	target := ""
+	if info.IsDir() {
+		if err := addRecursive(fsw, path); err != nil {
+			return err
+		}
+	} else {
+		target = filepath.Clean(path)
+		if err := fsw.Add(filepath.Dir(target)); err != nil {
+			return goerr.Wrap(err, "failed to watch directory", goerr.V("path", path))
+		}
+	}
+
+	logger := logging.Extract(ctx)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if target != "" && filepath.Clean(ev.Name) != target {
+				continue
+			}
+			if ev.Has(fsnotify.Chmod) && !ev.Has(fsnotify.Write) {
+				continue
+			}
+
+			if target == "" && ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if err := addRecursive(fsw, ev.Name); err != nil {
+						logger.Warn("Failed to watch new directory", "path", ev.Name, "error", err)
+					}
+				}
+			}
+
+			logger.Debug("Detected file change", "event", ev.String())
+			timer.Reset(debounce)
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warn("File watcher error", "error", err)
+
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+func addRecursive(fsw *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return goerr.Wrap(err, "failed to walk directory", goerr.V("path", path))
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := fsw.Add(path); err != nil {
+			return goerr.Wrap(err, "failed to watch directory", goerr.V("path", path))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+package watcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/gt"
+	"github.com/m-mizutani/xroute/pkg/utils/watcher"
+)
+
+func startWatch(t *testing.T, path string) *atomic.Int32 {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	var called atomic.Int32
+	go func() {
+		defer close(done)
+		gt.NoError(t, watcher.Watch(ctx, path, 50*time.Millisecond, func() {
+			called.Add(1)
+		}))
+	}()
+
+	// Wait for the watcher to be ready
+	time.Sleep(100 * time.Millisecond)
+	return &called
+}
+
+func waitCalled(t *testing.T, called *atomic.Int32, expected int32) {
+	deadline := time.Now().Add(3 * time.Second)
+	for called.Load() < expected && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	gt.Equal(t, called.Load(), expected)
+}
+
+func TestWatchDirectory(t *testing.T) {
+	dir := t.TempDir()
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "a.rego"), []byte("package a"), 0600))
+	called := startWatch(t, dir)
+
+	// Multiple changes in debounce interval are merged
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "a.rego"), []byte("package a\n"), 0600))
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "b.rego"), []byte("package b"), 0600))
+	waitCalled(t, called, 1)
+
+	// Files in new sub directory are also watched
+	sub := filepath.Join(dir, "sub")
+	gt.NoError(t, os.Mkdir(sub, 0700))
+	waitCalled(t, called, 2)
+	time.Sleep(100 * time.Millisecond)
+	gt.NoError(t, os.WriteFile(filepath.Join(sub, "c.rego"), []byte("package c"), 0600))
+	waitCalled(t, called, 3)
+}
+
+func TestWatchFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.rego")
+	gt.NoError(t, os.WriteFile(path, []byte("package a"), 0600))
+	called := startWatch(t, path)
+
+	// Other files in the same directory are ignored
+	gt.NoError(t, os.WriteFile(filepath.Join(dir, "other.rego"), []byte("package b"), 0600))
+	time.Sleep(200 * time.Millisecond)
+	gt.Equal(t, called.Load(), 0)
+
+	// Replacing by rename is detected
+	tmp := filepath.Join(dir, "policy.rego.tmp")
+	gt.NoError(t, os.WriteFile(tmp, []byte("package a\n"), 0600))
+	gt.NoError(t, os.Rename(tmp, path))
+	waitCalled(t, called, 1)
+}